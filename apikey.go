@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"test-task-scout-go/internal/config"
+)
+
+const apiKeyUsage = "usage: apikey <name> [read|write|admin]"
+
+// runAPIKey generates a key and prints it together with the API_KEYS entry
+// for it. The key is shown only here; the entry holds just its hash.
+func runAPIKey(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(apiKeyUsage)
+	}
+	if strings.ContainsAny(args[0], ":,") {
+		return fmt.Errorf("API key name %q must not contain ':' or ','", args[0])
+	}
+	scope := "read"
+	if len(args) == 2 {
+		scope = args[1]
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := base64.RawURLEncoding.EncodeToString(secret)
+	hash := sha256.Sum256([]byte(key))
+	entry := fmt.Sprintf("%s:%s:%s", args[0], scope, hex.EncodeToString(hash[:]))
+	if _, err := config.ParseAPIKey(entry); err != nil {
+		return err
+	}
+
+	fmt.Printf("API key:      %s\n", key)
+	fmt.Printf("Config entry: %s\n", entry)
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log"
+
+	"test-task-scout-go/internal/config"
+	"test-task-scout-go/internal/jwt"
+	"test-task-scout-go/internal/router"
+)
+
+// newAuthenticators accepts the credentials the configuration provides for.
+// Without any, the API is open to anyone.
+func newAuthenticators(cfg *config.Config) ([]router.Authenticator, error) {
+	var authenticators []router.Authenticator
+	if len(cfg.APIKeys) > 0 {
+		keys := make([]router.APIKey, len(cfg.APIKeys))
+		for i, key := range cfg.APIKeys {
+			keys[i] = router.APIKey{Name: key.Name, Scope: router.Scope(key.Scope), Hash: key.Hash}
+		}
+		log.Printf("API key authentication enabled with %d key(s)", len(keys))
+		authenticators = append(authenticators, router.NewAPIKeyAuth(keys))
+	}
+
+	if cfg.JWTJWKSFile != "" {
+		keys, err := jwt.LoadKeySource(cfg.JWTJWKSFile, cfg.JWTJWKSReloadInterval)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Bearer token authentication enabled with %d key(s) from %s (issuer %s, audience %s)",
+			len(keys.Keys()), cfg.JWTJWKSFile, cfg.JWTIssuer, cfg.JWTAudience)
+		verifier := jwt.NewVerifier(keys, cfg.JWTIssuer, cfg.JWTAudience)
+		authenticators = append(authenticators, router.NewTokenAuth(verifier, cfg.JWTReadScope, cfg.JWTWriteScope, cfg.JWTAdminScope))
+	}
+
+	if len(authenticators) == 0 {
+		log.Println("No API keys or JWKS configured, requests are not authenticated")
+	}
+	return authenticators, nil
+}
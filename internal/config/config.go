@@ -1,14 +1,68 @@
 package config
 
 import (
+	"bufio"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	RepositoryType string
 	DatabasePath   string
+	DataFilePath   string
 	Port           string
+	IDStrategy     string
+	// NearDuplicateThreshold enables the near-duplicate check on create when
+	// above zero; see service.WithNearDuplicateThreshold.
+	NearDuplicateThreshold float64
+	// WALDir makes the in-memory repository durable when set; see
+	// repository.NewDurableInMemoryRepository.
+	WALDir           string
+	WALSync          string
+	WALSyncInterval  time.Duration
+	WALSnapshotEvery int
+	// PostgresDSN is required for the postgres repository; the pool settings
+	// are passed to database/sql.
+	PostgresDSN             string
+	PostgresMaxOpenConns    int
+	PostgresMaxIdleConns    int
+	PostgresConnMaxLifetime time.Duration
+	PostgresConnMaxIdleTime time.Duration
+	// CacheSize enables the read-through cache in front of the repository
+	// when above zero; see repository.NewCachingRepository.
+	CacheSize int
+	CacheTTL  time.Duration
+	// ShutdownDrainDelay is how long the server keeps serving after reporting
+	// itself not ready, before it stops accepting connections.
+	ShutdownDrainDelay time.Duration
+	// APIKeys and JWTJWKSFile each turn on authentication; AuthPublicRead
+	// then still lets requests without credentials read.
+	APIKeys        []APIKey
+	AuthPublicRead bool
+	// JWTJWKSFile holds the keys bearer tokens are verified with; tokens must
+	// be issued by JWTIssuer for JWTAudience. JWTReadScope, JWTWriteScope and
+	// JWTAdminScope are the token scopes that allow reading, writing and
+	// pinning the quote of the day.
+	JWTJWKSFile           string
+	JWTJWKSReloadInterval time.Duration
+	JWTIssuer             string
+	JWTAudience           string
+	JWTReadScope          string
+	JWTWriteScope         string
+	JWTAdminScope         string
+}
+
+// APIKey is one entry of API_KEYS or API_KEYS_FILE, written as
+// name:scope:hash. Only the SHA-256 hash of the key is configured, so the
+// configuration does not reveal the keys.
+type APIKey struct {
+	Name  string
+	Scope string
+	Hash  []byte
 }
 
 func LoadConfig() (*Config, error) {
@@ -19,8 +73,10 @@ func LoadConfig() (*Config, error) {
 		cfg.RepositoryType = "inmemory" 
 	}
 
-	if cfg.RepositoryType != "inmemory" && cfg.RepositoryType != "sqlite" {
-		return nil, fmt.Errorf("unknown repository type: %s. Use 'inmemory' or 'sqlite'.", cfg.RepositoryType)
+	switch cfg.RepositoryType {
+	case "inmemory", "sqlite", "file", "postgres":
+	default:
+		return nil, fmt.Errorf("unknown repository type: %s. Use 'inmemory', 'sqlite', 'file' or 'postgres'.", cfg.RepositoryType)
 	}
 
 	if cfg.RepositoryType == "sqlite" {
@@ -30,10 +86,282 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	if cfg.RepositoryType == "inmemory" {
+		if err := loadWALConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.RepositoryType == "file" {
+		cfg.DataFilePath = os.Getenv("DATA_FILE_PATH")
+		if cfg.DataFilePath == "" {
+			cfg.DataFilePath = "./quotes.json"
+		}
+	}
+
+	if cfg.RepositoryType == "postgres" {
+		if err := loadPostgresConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if value := os.Getenv("CACHE_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid CACHE_SIZE: %s. Use a non-negative number of entries.", value)
+		}
+		cfg.CacheSize = size
+	}
+	cfg.CacheTTL = time.Minute
+	if value := os.Getenv("CACHE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid CACHE_TTL: %s. Use a positive duration such as 30s.", value)
+		}
+		cfg.CacheTTL = ttl
+	}
+
+	if value := os.Getenv("SHUTDOWN_DRAIN_DELAY"); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil || delay < 0 {
+			return nil, fmt.Errorf("invalid SHUTDOWN_DRAIN_DELAY: %s. Use a duration such as 5s.", value)
+		}
+		cfg.ShutdownDrainDelay = delay
+	}
+
+	if err := loadAPIKeys(cfg); err != nil {
+		return nil, err
+	}
+	if err := loadJWTConfig(cfg); err != nil {
+		return nil, err
+	}
+
 	cfg.Port = os.Getenv("PORT")
 	if cfg.Port == "" {
 		cfg.Port = "8000"
 	}
 
+	cfg.IDStrategy = os.Getenv("ID_STRATEGY")
+	if cfg.IDStrategy == "" {
+		cfg.IDStrategy = "uuidv4"
+	}
+	switch cfg.IDStrategy {
+	case "uuidv4", "uuidv7", "ulid", "slug":
+	default:
+		return nil, fmt.Errorf("unknown ID strategy: %s. Use 'uuidv4', 'uuidv7', 'ulid' or 'slug'.", cfg.IDStrategy)
+	}
+
+	if value := os.Getenv("NEAR_DUPLICATE_THRESHOLD"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			return nil, fmt.Errorf("invalid NEAR_DUPLICATE_THRESHOLD: %s. Use a number between 0 and 1.", value)
+		}
+		cfg.NearDuplicateThreshold = threshold
+	}
+
 	return cfg, nil
-} 
\ No newline at end of file
+}
+
+func loadWALConfig(cfg *Config) error {
+	cfg.WALDir = os.Getenv("WAL_DIR")
+	if cfg.WALDir == "" {
+		return nil
+	}
+
+	cfg.WALSync = os.Getenv("WAL_SYNC")
+	if cfg.WALSync == "" {
+		cfg.WALSync = "always"
+	}
+	switch cfg.WALSync {
+	case "always", "interval", "never":
+	default:
+		return fmt.Errorf("unknown WAL sync policy: %s. Use 'always', 'interval' or 'never'.", cfg.WALSync)
+	}
+
+	cfg.WALSyncInterval = time.Second
+	if value := os.Getenv("WAL_SYNC_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid WAL_SYNC_INTERVAL: %s. Use a positive duration such as 500ms.", value)
+		}
+		cfg.WALSyncInterval = interval
+	}
+
+	cfg.WALSnapshotEvery = 1000
+	if value := os.Getenv("WAL_SNAPSHOT_EVERY"); value != "" {
+		every, err := strconv.Atoi(value)
+		if err != nil || every <= 0 {
+			return fmt.Errorf("invalid WAL_SNAPSHOT_EVERY: %s. Use a positive number of writes.", value)
+		}
+		cfg.WALSnapshotEvery = every
+	}
+	return nil
+}
+
+func loadPostgresConfig(cfg *Config) error {
+	cfg.PostgresDSN = os.Getenv("POSTGRES_DSN")
+	if cfg.PostgresDSN == "" {
+		return fmt.Errorf("POSTGRES_DSN is required for the postgres repository")
+	}
+
+	var err error
+	if cfg.PostgresMaxOpenConns, err = connectionCount("POSTGRES_MAX_OPEN_CONNS", 10); err != nil {
+		return err
+	}
+	if cfg.PostgresMaxIdleConns, err = connectionCount("POSTGRES_MAX_IDLE_CONNS", 5); err != nil {
+		return err
+	}
+	if cfg.PostgresConnMaxLifetime, err = connectionDuration("POSTGRES_CONN_MAX_LIFETIME", 30*time.Minute); err != nil {
+		return err
+	}
+	if cfg.PostgresConnMaxIdleTime, err = connectionDuration("POSTGRES_CONN_MAX_IDLE_TIME", 5*time.Minute); err != nil {
+		return err
+	}
+	return nil
+}
+
+func connectionCount(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s. Use a positive number of connections.", name, value)
+	}
+	return count, nil
+}
+
+func connectionDuration(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s. Use a positive duration such as 10m.", name, value)
+	}
+	return duration, nil
+}
+
+func loadAPIKeys(cfg *Config) error {
+	if value := os.Getenv("API_KEYS"); value != "" {
+		for _, entry := range strings.Split(value, ",") {
+			key, err := ParseAPIKey(strings.TrimSpace(entry))
+			if err != nil {
+				return fmt.Errorf("invalid API_KEYS: %w", err)
+			}
+			cfg.APIKeys = append(cfg.APIKeys, key)
+		}
+	}
+
+	if path := os.Getenv("API_KEYS_FILE"); path != "" {
+		keys, err := readAPIKeysFile(path)
+		if err != nil {
+			return err
+		}
+		cfg.APIKeys = append(cfg.APIKeys, keys...)
+	}
+
+	names := make(map[string]bool, len(cfg.APIKeys))
+	for _, key := range cfg.APIKeys {
+		if names[key.Name] {
+			return fmt.Errorf("duplicate API key name: %s", key.Name)
+		}
+		names[key.Name] = true
+	}
+
+	if value := os.Getenv("AUTH_PUBLIC_READ"); value != "" {
+		publicRead, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid AUTH_PUBLIC_READ: %s. Use true or false.", value)
+		}
+		cfg.AuthPublicRead = publicRead
+	}
+	return nil
+}
+
+// readAPIKeysFile reads one key per line, skipping blank lines and lines
+// starting with #.
+func readAPIKeysFile(path string) ([]APIKey, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open API_KEYS_FILE: %w", err)
+	}
+	defer file.Close()
+
+	var keys []APIKey
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := ParseAPIKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API_KEYS_FILE %s, line %d: %w", path, lineNumber, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read API_KEYS_FILE: %w", err)
+	}
+	return keys, nil
+}
+
+func loadJWTConfig(cfg *Config) error {
+	cfg.JWTJWKSFile = os.Getenv("JWT_JWKS_FILE")
+	if cfg.JWTJWKSFile == "" {
+		return nil
+	}
+
+	cfg.JWTIssuer = os.Getenv("JWT_ISSUER")
+	cfg.JWTAudience = os.Getenv("JWT_AUDIENCE")
+	if cfg.JWTIssuer == "" || cfg.JWTAudience == "" {
+		return fmt.Errorf("JWT_ISSUER and JWT_AUDIENCE are required with JWT_JWKS_FILE")
+	}
+
+	cfg.JWTJWKSReloadInterval = time.Minute
+	if value := os.Getenv("JWT_JWKS_RELOAD_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid JWT_JWKS_RELOAD_INTERVAL: %s. Use a positive duration such as 30s.", value)
+		}
+		cfg.JWTJWKSReloadInterval = interval
+	}
+
+	cfg.JWTReadScope = os.Getenv("JWT_READ_SCOPE")
+	if cfg.JWTReadScope == "" {
+		cfg.JWTReadScope = "quotes:read"
+	}
+	cfg.JWTWriteScope = os.Getenv("JWT_WRITE_SCOPE")
+	if cfg.JWTWriteScope == "" {
+		cfg.JWTWriteScope = "quotes:write"
+	}
+	cfg.JWTAdminScope = os.Getenv("JWT_ADMIN_SCOPE")
+	if cfg.JWTAdminScope == "" {
+		cfg.JWTAdminScope = "quotes:admin"
+	}
+	return nil
+}
+
+// ParseAPIKey parses name:scope:hash, where scope is read, write or admin and hash
+// is the hex-encoded SHA-256 of the key.
+func ParseAPIKey(entry string) (APIKey, error) {
+	parts := strings.Split(entry, ":")
+	if len(parts) != 3 {
+		return APIKey{}, fmt.Errorf("expected name:scope:hash, got %q", entry)
+	}
+	name, scope, encodedHash := parts[0], parts[1], parts[2]
+	if name == "" {
+		return APIKey{}, fmt.Errorf("API key name is empty")
+	}
+	if scope != "read" && scope != "write" && scope != "admin" {
+		return APIKey{}, fmt.Errorf("unknown scope %q for API key %s. Use 'read', 'write' or 'admin'", scope, name)
+	}
+	hash, err := hex.DecodeString(encodedHash)
+	if err != nil || len(hash) != 32 {
+		return APIKey{}, fmt.Errorf("hash of API key %s must be 64 hex digits of SHA-256", name)
+	}
+	return APIKey{Name: name, Scope: scope, Hash: hash}, nil
+}
@@ -2,8 +2,11 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"test-task-scout-go/internal/config"
 	"testing"
+	"time"
 )
 
 func setEnv(t *testing.T, key, value string) {
@@ -77,9 +80,246 @@ func TestLoadConfig_SQLiteDefaultDBPath(t *testing.T) {
 	}
 }
 
-func TestLoadConfig_InvalidRepositoryType(t *testing.T) {
+func TestLoadConfig_FileDefaultPath(t *testing.T) {
+	setEnv(t, "REPOSITORY_TYPE", "file")
+	os.Unsetenv("DATA_FILE_PATH")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+
+	if cfg.DataFilePath != "./quotes.json" {
+		t.Errorf("Expected DataFilePath './quotes.json', got '%s'", cfg.DataFilePath)
+	}
+	if cfg.DatabasePath != "" {
+		t.Errorf("Expected empty DatabasePath, got '%s'", cfg.DatabasePath)
+	}
+
+	setEnv(t, "DATA_FILE_PATH", "/data/quotes.json")
+	cfg, err = config.LoadConfig()
+	if err != nil || cfg.DataFilePath != "/data/quotes.json" {
+		t.Errorf("Expected DataFilePath '/data/quotes.json', got %+v (%v)", cfg, err)
+	}
+}
+
+func TestLoadConfig_WAL(t *testing.T) {
+	os.Unsetenv("REPOSITORY_TYPE")
+	setEnv(t, "WAL_DIR", "/var/lib/quotes")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.WALDir != "/var/lib/quotes" || cfg.WALSync != "always" || cfg.WALSyncInterval != time.Second || cfg.WALSnapshotEvery != 1000 {
+		t.Errorf("Unexpected WAL defaults: %+v", cfg)
+	}
+
+	setEnv(t, "WAL_SYNC", "interval")
+	setEnv(t, "WAL_SYNC_INTERVAL", "250ms")
+	setEnv(t, "WAL_SNAPSHOT_EVERY", "50")
+	cfg, err = config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.WALSync != "interval" || cfg.WALSyncInterval != 250*time.Millisecond || cfg.WALSnapshotEvery != 50 {
+		t.Errorf("Unexpected WAL settings: %+v", cfg)
+	}
+
+	for key, value := range map[string]string{"WAL_SYNC": "sometimes", "WAL_SYNC_INTERVAL": "soon", "WAL_SNAPSHOT_EVERY": "0"} {
+		t.Run(key, func(t *testing.T) {
+			setEnv(t, key, value)
+			if _, err := config.LoadConfig(); err == nil {
+				t.Errorf("Expected an error for %s=%s", key, value)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Postgres(t *testing.T) {
 	setEnv(t, "REPOSITORY_TYPE", "postgres")
 
+	if _, err := config.LoadConfig(); err == nil {
+		t.Error("Expected an error without POSTGRES_DSN")
+	}
+
+	setEnv(t, "POSTGRES_DSN", "postgres://quotes@localhost/quotes")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.PostgresDSN != "postgres://quotes@localhost/quotes" || cfg.PostgresMaxOpenConns != 10 || cfg.PostgresMaxIdleConns != 5 ||
+		cfg.PostgresConnMaxLifetime != 30*time.Minute || cfg.PostgresConnMaxIdleTime != 5*time.Minute {
+		t.Errorf("Unexpected Postgres defaults: %+v", cfg)
+	}
+
+	setEnv(t, "POSTGRES_MAX_OPEN_CONNS", "20")
+	setEnv(t, "POSTGRES_MAX_IDLE_CONNS", "2")
+	setEnv(t, "POSTGRES_CONN_MAX_LIFETIME", "1h")
+	setEnv(t, "POSTGRES_CONN_MAX_IDLE_TIME", "30s")
+	cfg, err = config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.PostgresMaxOpenConns != 20 || cfg.PostgresMaxIdleConns != 2 ||
+		cfg.PostgresConnMaxLifetime != time.Hour || cfg.PostgresConnMaxIdleTime != 30*time.Second {
+		t.Errorf("Unexpected Postgres settings: %+v", cfg)
+	}
+
+	for key, value := range map[string]string{"POSTGRES_MAX_OPEN_CONNS": "many", "POSTGRES_MAX_IDLE_CONNS": "-1", "POSTGRES_CONN_MAX_LIFETIME": "0s"} {
+		t.Run(key, func(t *testing.T) {
+			setEnv(t, key, value)
+			if _, err := config.LoadConfig(); err == nil {
+				t.Errorf("Expected an error for %s=%s", key, value)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Cache(t *testing.T) {
+	os.Unsetenv("REPOSITORY_TYPE")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.CacheSize != 0 || cfg.CacheTTL != time.Minute {
+		t.Errorf("Unexpected cache defaults: %+v", cfg)
+	}
+
+	setEnv(t, "CACHE_SIZE", "500")
+	setEnv(t, "CACHE_TTL", "10s")
+	cfg, err = config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.CacheSize != 500 || cfg.CacheTTL != 10*time.Second {
+		t.Errorf("Unexpected cache settings: %+v", cfg)
+	}
+
+	for key, value := range map[string]string{"CACHE_SIZE": "-1", "CACHE_TTL": "forever"} {
+		t.Run(key, func(t *testing.T) {
+			setEnv(t, key, value)
+			if _, err := config.LoadConfig(); err == nil {
+				t.Errorf("Expected an error for %s=%s", key, value)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ShutdownDrainDelay(t *testing.T) {
+	os.Unsetenv("REPOSITORY_TYPE")
+
+	cfg, err := config.LoadConfig()
+	if err != nil || cfg.ShutdownDrainDelay != 0 {
+		t.Fatalf("Expected no drain delay by default, got %+v (%v)", cfg, err)
+	}
+
+	setEnv(t, "SHUTDOWN_DRAIN_DELAY", "5s")
+	cfg, err = config.LoadConfig()
+	if err != nil || cfg.ShutdownDrainDelay != 5*time.Second {
+		t.Errorf("Expected a drain delay of 5s, got %+v (%v)", cfg, err)
+	}
+
+	setEnv(t, "SHUTDOWN_DRAIN_DELAY", "-1s")
+	if _, err := config.LoadConfig(); err == nil {
+		t.Error("Expected an error for a negative drain delay")
+	}
+}
+
+func TestLoadConfig_APIKeys(t *testing.T) {
+	os.Unsetenv("REPOSITORY_TYPE")
+	const hash = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	cfg, err := config.LoadConfig()
+	if err != nil || len(cfg.APIKeys) != 0 || cfg.AuthPublicRead {
+		t.Fatalf("Expected authentication to be off by default, got %+v (%v)", cfg, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys")
+	contents := "# Deployment keys\n\nci:write:" + hash + "\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	setEnv(t, "API_KEYS", "dashboard:read:"+strings.ToUpper(hash)+", editor:admin:"+hash)
+	setEnv(t, "API_KEYS_FILE", path)
+	setEnv(t, "AUTH_PUBLIC_READ", "true")
+	cfg, err = config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if len(cfg.APIKeys) != 3 || !cfg.AuthPublicRead {
+		t.Fatalf("Unexpected auth settings: %+v", cfg)
+	}
+	if key := cfg.APIKeys[0]; key.Name != "dashboard" || key.Scope != "read" || len(key.Hash) != 32 {
+		t.Errorf("Unexpected key from API_KEYS: %+v", key)
+	}
+	if key := cfg.APIKeys[1]; key.Name != "editor" || key.Scope != "admin" || len(key.Hash) != 32 {
+		t.Errorf("Unexpected admin key from API_KEYS: %+v", key)
+	}
+	if key := cfg.APIKeys[2]; key.Name != "ci" || key.Scope != "write" || len(key.Hash) != 32 {
+		t.Errorf("Unexpected key from API_KEYS_FILE: %+v", key)
+	}
+
+	for name, value := range map[string]string{
+		"missing hash":   "ci:write",
+		"unknown scope":  "ci:owner:" + hash,
+		"short hash":     "ci:write:2cf24dba",
+		"empty name":     ":read:" + hash,
+		"duplicate name": "ci:read:" + hash,
+	} {
+		t.Run(name, func(t *testing.T) {
+			setEnv(t, "API_KEYS", value)
+			if _, err := config.LoadConfig(); err == nil {
+				t.Errorf("Expected an error for API_KEYS=%s", value)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_JWT(t *testing.T) {
+	os.Unsetenv("REPOSITORY_TYPE")
+
+	cfg, err := config.LoadConfig()
+	if err != nil || cfg.JWTJWKSFile != "" {
+		t.Fatalf("Expected bearer tokens to be off by default, got %+v (%v)", cfg, err)
+	}
+
+	setEnv(t, "JWT_JWKS_FILE", "/etc/quotes/jwks.json")
+	if _, err := config.LoadConfig(); err == nil {
+		t.Error("Expected an error without JWT_ISSUER and JWT_AUDIENCE")
+	}
+
+	setEnv(t, "JWT_ISSUER", "https://gateway.example")
+	setEnv(t, "JWT_AUDIENCE", "quotes")
+	cfg, err = config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.JWTJWKSReloadInterval != time.Minute || cfg.JWTReadScope != "quotes:read" || cfg.JWTWriteScope != "quotes:write" || cfg.JWTAdminScope != "quotes:admin" {
+		t.Errorf("Unexpected JWT defaults: %+v", cfg)
+	}
+
+	setEnv(t, "JWT_JWKS_RELOAD_INTERVAL", "10s")
+	setEnv(t, "JWT_WRITE_SCOPE", "quotes.edit")
+	setEnv(t, "JWT_ADMIN_SCOPE", "quotes.admin")
+	cfg, err = config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.JWTJWKSReloadInterval != 10*time.Second || cfg.JWTWriteScope != "quotes.edit" || cfg.JWTAdminScope != "quotes.admin" {
+		t.Errorf("Unexpected JWT settings: %+v", cfg)
+	}
+
+	setEnv(t, "JWT_JWKS_RELOAD_INTERVAL", "0s")
+	if _, err := config.LoadConfig(); err == nil {
+		t.Error("Expected an error for a zero reload interval")
+	}
+}
+
+func TestLoadConfig_InvalidRepositoryType(t *testing.T) {
+	setEnv(t, "REPOSITORY_TYPE", "mongodb")
+
 	cfg, err := config.LoadConfig()
 	if err == nil {
 		t.Error("LoadConfig did not return an error for invalid repository type")
@@ -88,8 +328,54 @@ func TestLoadConfig_InvalidRepositoryType(t *testing.T) {
 		t.Errorf("Expected nil config for invalid type, got %+v", cfg)
 	}
 
-	expectedErr := "unknown repository type: postgres. Use 'inmemory' or 'sqlite'."
+	expectedErr := "unknown repository type: mongodb. Use 'inmemory', 'sqlite', 'file' or 'postgres'."
 	if err != nil && err.Error() != expectedErr {
 		t.Errorf("Expected error message '%s', got '%s'", expectedErr, err.Error())
 	}
-} 
\ No newline at end of file
+} 
+func TestLoadConfig_NearDuplicateThreshold(t *testing.T) {
+	os.Unsetenv("REPOSITORY_TYPE")
+	setEnv(t, "NEAR_DUPLICATE_THRESHOLD", "0.85")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.NearDuplicateThreshold != 0.85 {
+		t.Errorf("Expected NearDuplicateThreshold 0.85, got %v", cfg.NearDuplicateThreshold)
+	}
+
+	for _, value := range []string{"1.5", "-0.1", "high"} {
+		setEnv(t, "NEAR_DUPLICATE_THRESHOLD", value)
+		if _, err := config.LoadConfig(); err == nil {
+			t.Errorf("Expected an error for NEAR_DUPLICATE_THRESHOLD=%s", value)
+		}
+	}
+}
+
+func TestLoadConfig_IDStrategy(t *testing.T) {
+	os.Unsetenv("REPOSITORY_TYPE")
+	os.Unsetenv("ID_STRATEGY")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.IDStrategy != "uuidv4" {
+		t.Errorf("Expected default IDStrategy 'uuidv4', got '%s'", cfg.IDStrategy)
+	}
+
+	setEnv(t, "ID_STRATEGY", "ulid")
+	cfg, err = config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.IDStrategy != "ulid" {
+		t.Errorf("Expected IDStrategy 'ulid', got '%s'", cfg.IDStrategy)
+	}
+
+	setEnv(t, "ID_STRATEGY", "nanoid")
+	if _, err := config.LoadConfig(); err == nil {
+		t.Error("LoadConfig did not return an error for an unknown ID strategy")
+	}
+}
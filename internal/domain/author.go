@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Author is a person quotes are attributed to. Name is the canonical spelling
+// and Aliases are other names that resolve to the same author. Names are
+// compared by AuthorKey, so spellings differing only in case, punctuation or
+// spacing need no alias.
+type Author struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Bio        string    `json:"bio"`
+	Aliases    []string  `json:"aliases"`
+	QuoteCount int       `json:"quote_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AuthorKey is the form in which author names are matched. Names without a
+// letter or a digit, such as "🦊", fall back to their lower-cased spelling
+// with spacing collapsed so that they do not all share an empty key.
+func AuthorKey(name string) string {
+	if key := NormalizeForMatch(name); key != "" {
+		return key
+	}
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// FuzzyAuthorDistance is how many edits AuthorMatchFuzzy tolerates for a query
+// key: one per four characters, and at least one.
+func FuzzyAuthorDistance(query string) int {
+	return max(1, utf8.RuneCountInString(query)/4)
+}
+
+// EditDistance is the Levenshtein distance between a and b in runes.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
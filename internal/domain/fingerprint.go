@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// NormalizeForMatch reduces s to the form used to compare quotes: case
+// folded, with full-width Latin letters and digits narrowed, punctuation and
+// symbols treated as spaces and whitespace collapsed. "Don’t  panic!",
+// "don't panic" and "Ｄｏｎ'ｔ panic" normalize alike.
+func NormalizeForMatch(s string) string {
+	s = strings.Map(foldRune, s)
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !unicode.IsMark(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// foldRune maps the full-width forms of ASCII to ASCII, and lowers the upper
+// case so that letters with several lower case forms, such as the final sigma,
+// fold into one.
+func foldRune(r rune) rune {
+	if r >= '！' && r <= '～' {
+		r -= '！' - '!'
+	}
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// Fingerprint identifies a quote's content: two quotes with the same
+// fingerprint say the same thing by the same author.
+func Fingerprint(text, author string) string {
+	sum := sha256.Sum256([]byte(AuthorKey(author) + "\x00" + NormalizeForMatch(text)))
+	return hex.EncodeToString(sum[:])
+}
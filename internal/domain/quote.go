@@ -1,7 +1,264 @@
 package domain
 
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Quote.Author is the canonical name of the author AuthorID refers to.
 type Quote struct {
-	ID     string `json:"id"`
-	Text   string `json:"text"`
-	Author string `json:"author"`
-} 
\ No newline at end of file
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	Author    string    `json:"author"`
+	AuthorID  string    `json:"author_id"`
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by"`
+	Tags      []string  `json:"tags"`
+}
+
+// QuotePatch describes a JSON Merge Patch; nil fields are left unchanged.
+type QuotePatch struct {
+	Text   *string
+	Author *string
+	Tags   *[]string
+}
+
+// NormalizeTags lowercases and trims tags, dropping blanks and duplicates. The
+// result is sorted and never nil.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type TagMode string
+
+const (
+	TagModeAny TagMode = "any"
+	TagModeAll TagMode = "all"
+)
+
+func (m TagMode) Valid() bool {
+	return m == TagModeAny || m == TagModeAll
+}
+
+// AuthorMatch selects how QuoteFilter.Author is compared with authors.
+// AuthorMatchExact compares it with the quote's author as stored; the other
+// modes compare AuthorKey forms with every name of the author, aliases
+// included.
+type AuthorMatch string
+
+const (
+	AuthorMatchExact           AuthorMatch = "exact"
+	AuthorMatchCaseInsensitive AuthorMatch = "case_insensitive"
+	AuthorMatchPrefix          AuthorMatch = "prefix"
+	// AuthorMatchFuzzy allows up to FuzzyAuthorDistance edits.
+	AuthorMatchFuzzy AuthorMatch = "fuzzy"
+)
+
+func (m AuthorMatch) Valid() bool {
+	switch m {
+	case AuthorMatchExact, AuthorMatchCaseInsensitive, AuthorMatchPrefix, AuthorMatchFuzzy:
+		return true
+	}
+	return false
+}
+
+// MatchesKey reports whether the author name key matches the query key, both
+// in AuthorKey form. It is false for AuthorMatchExact, which compares names.
+func (m AuthorMatch) MatchesKey(query, key string) bool {
+	switch m {
+	case AuthorMatchCaseInsensitive:
+		return key == query
+	case AuthorMatchPrefix:
+		return strings.HasPrefix(key, query)
+	case AuthorMatchFuzzy:
+		return EditDistance(query, key) <= FuzzyAuthorDistance(query)
+	}
+	return false
+}
+
+// QuoteFilter narrows down a set of quotes; zero fields match everything.
+// Author is compared as AuthorMatch says, exactly when it is empty. Matches
+// sees only the quote, so it checks the canonical name and not aliases.
+// CreatedAfter and CreatedBefore are exclusive bounds. With TagModeAll a quote
+// must carry every tag, otherwise any one of them is enough. MinLength and
+// MaxLength bound the text length in characters, inclusively.
+type QuoteFilter struct {
+	Author        string
+	AuthorMatch   AuthorMatch
+	AuthorID      string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Tags          []string
+	TagMode       TagMode
+	MinLength     int
+	MaxLength     int
+}
+
+func (f QuoteFilter) Matches(quote Quote) bool {
+	if f.Author != "" && !f.matchesAuthor(quote.Author) {
+		return false
+	}
+	if f.AuthorID != "" && quote.AuthorID != f.AuthorID {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && !quote.CreatedAt.After(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !quote.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	if f.MinLength > 0 || f.MaxLength > 0 {
+		length := utf8.RuneCountInString(quote.Text)
+		if length < f.MinLength || (f.MaxLength > 0 && length > f.MaxLength) {
+			return false
+		}
+	}
+	if len(f.Tags) == 0 {
+		return true
+	}
+	matched := 0
+	for _, tag := range f.Tags {
+		if hasTag(quote.Tags, tag) {
+			matched++
+		}
+	}
+	if f.TagMode == TagModeAll {
+		return matched == len(f.Tags)
+	}
+	return matched > 0
+}
+
+func (f QuoteFilter) matchesAuthor(name string) bool {
+	if f.AuthorMatch == "" || f.AuthorMatch == AuthorMatchExact {
+		return name == f.Author
+	}
+	return f.AuthorMatch.MatchesKey(AuthorKey(f.Author), AuthorKey(name))
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+type SearchResult struct {
+	Quote   Quote   `json:"quote"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+type SortField string
+
+const (
+	SortByID      SortField = "id"
+	SortByAuthor  SortField = "author"
+	SortByCreated SortField = "created"
+)
+
+func (f SortField) Valid() bool {
+	switch f {
+	case SortByID, SortByAuthor, SortByCreated:
+		return true
+	}
+	return false
+}
+
+type ListQuery struct {
+	QuoteFilter
+	SortBy     SortField
+	Descending bool
+	Cursor     string
+	Limit      int
+}
+
+type QuotePage struct {
+	Quotes     []Quote `json:"quotes"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// DailyQuote is the quote selected for a calendar date (YYYY-MM-DD). Pinned is
+// set when it was chosen by hand rather than derived from the date.
+type DailyQuote struct {
+	Date   string `json:"date"`
+	Pinned bool   `json:"pinned"`
+	Quote  Quote  `json:"quote"`
+}
+
+// QuoteInput is a quote as submitted by a client, before it is assigned an ID.
+type QuoteInput struct {
+	Text   string   `json:"text"`
+	Author string   `json:"author"`
+	Tags   []string `json:"tags"`
+}
+
+type ImportMode string
+
+const (
+	// ImportAtomic stores every row or none of them.
+	ImportAtomic ImportMode = "atomic"
+	// ImportBestEffort stores the rows that can be stored and reports the rest.
+	ImportBestEffort ImportMode = "best_effort"
+)
+
+func (m ImportMode) Valid() bool {
+	return m == ImportAtomic || m == ImportBestEffort
+}
+
+type ImportOptions struct {
+	Mode      ImportMode
+	DryRun    bool
+	CreatedBy string
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated ImportRowStatus = "created"
+	// ImportRowValid marks a row that passed validation in a dry run.
+	ImportRowValid   ImportRowStatus = "valid"
+	ImportRowInvalid ImportRowStatus = "invalid"
+	ImportRowFailed  ImportRowStatus = "failed"
+	// ImportRowSkipped marks a valid row left out because an atomic import failed.
+	ImportRowSkipped ImportRowStatus = "skipped"
+)
+
+// ImportRowResult reports the outcome for one input row; Row is 1-based.
+type ImportRowResult struct {
+	Row    int             `json:"row"`
+	Status ImportRowStatus `json:"status"`
+	ID     string          `json:"id,omitempty"`
+	Field  string          `json:"field,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	Mode     ImportMode        `json:"mode"`
+	DryRun   bool              `json:"dry_run"`
+	Total    int               `json:"total"`
+	Imported int               `json:"imported"`
+	Failed   int               `json:"failed"`
+	Rows     []ImportRowResult `json:"rows"`
+}
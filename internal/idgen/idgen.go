@@ -0,0 +1,168 @@
+// Package idgen provides quote ID generators built on the standard library
+// only. All of them draw randomness from crypto/rand and are safe for
+// concurrent use.
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// UUIDv4 generates random RFC 9562 version 4 UUIDs. They reveal nothing about
+// when or where they were created.
+type UUIDv4 struct{}
+
+func (UUIDv4) NewID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b)
+}
+
+// UUIDv7 generates RFC 9562 version 7 UUIDs: a millisecond timestamp followed
+// by random bits, so IDs sort in creation order. Within one millisecond the
+// 12-bit rand_a field is used as a counter to keep that order.
+type UUIDv7 struct {
+	mu     sync.Mutex
+	lastMS int64
+	seq    uint16
+}
+
+func (g *UUIDv7) NewID() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	g.mu.Lock()
+	ms := time.Now().UnixMilli()
+	if ms <= g.lastMS {
+		g.seq++
+		if g.seq > 0x0fff {
+			// The counter is exhausted; borrow the next millisecond.
+			g.lastMS++
+			g.seq = 0
+		}
+		ms = g.lastMS
+	} else {
+		g.lastMS = ms
+		g.seq = binary.BigEndian.Uint16(b[6:8]) & 0x07ff
+	}
+	seq := g.seq
+	g.mu.Unlock()
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(ms))
+	copy(b[0:6], ts[2:8])
+	binary.BigEndian.PutUint16(b[6:8], 0x7000|seq)
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b)
+}
+
+func formatUUID(b [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:36], b[10:16])
+	return string(buf[:])
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID generates 26-character, lexicographically sortable IDs in the ULID
+// layout: a 48-bit millisecond timestamp and 80 random bits in Crockford
+// base32. IDs created in the same millisecond increment the random part, so
+// they still sort in creation order.
+type ULID struct {
+	mu      sync.Mutex
+	lastMS  int64
+	entropy [10]byte
+}
+
+func (g *ULID) NewID() string {
+	g.mu.Lock()
+	ms := time.Now().UnixMilli()
+	if ms <= g.lastMS && increment(g.entropy[:]) {
+		ms = g.lastMS
+	} else {
+		if ms <= g.lastMS {
+			// The random part overflowed; borrow the next millisecond.
+			ms = g.lastMS + 1
+		}
+		g.lastMS = ms
+		rand.Read(g.entropy[:])
+	}
+	var b [16]byte
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(ms))
+	copy(b[0:6], ts[2:8])
+	copy(b[6:], g.entropy[:])
+	g.mu.Unlock()
+
+	// 128 bits in 26 characters of 5 bits each; the first character holds the
+	// top 3 bits only.
+	n := new(big.Int).SetBytes(b[:])
+	var out [26]byte
+	mask := big.NewInt(31)
+	digit := new(big.Int)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockfordAlphabet[digit.And(n, mask).Int64()]
+		n.Rsh(n, 5)
+	}
+	return string(out[:])
+}
+
+// increment adds one to a big-endian number and reports false on overflow.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// DefaultSlugLength gives about 65 bits of randomness.
+const DefaultSlugLength = 11
+
+// Slug generates short random base62 IDs suited to URLs. Being short, they
+// are the most likely to collide; callers should retry on a conflict.
+type Slug struct {
+	Length int
+}
+
+func (g Slug) NewID() string {
+	length := g.Length
+	if length <= 0 {
+		length = DefaultSlugLength
+	}
+	out := make([]byte, length)
+	var buf [64]byte
+	for i := 0; i < length; {
+		rand.Read(buf[:])
+		for _, c := range buf {
+			// Rejecting bytes from 248 up keeps every character equally likely.
+			if c >= 248 {
+				continue
+			}
+			out[i] = base62Alphabet[c%62]
+			i++
+			if i == length {
+				break
+			}
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,104 @@
+package idgen_test
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+
+	"test-task-scout-go/internal/idgen"
+)
+
+type generator interface {
+	NewID() string
+}
+
+func generate(g generator, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = g.NewID()
+	}
+	return ids
+}
+
+func assertUnique(t *testing.T, ids []string) {
+	t.Helper()
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("Duplicate ID generated: %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func assertFormat(t *testing.T, ids []string, pattern *regexp.Regexp) {
+	t.Helper()
+	for _, id := range ids {
+		if !pattern.MatchString(id) {
+			t.Fatalf("ID %q does not match %s", id, pattern)
+		}
+	}
+}
+
+func assertSorted(t *testing.T, ids []string) {
+	t.Helper()
+	if !sort.StringsAreSorted(ids) {
+		t.Error("Expected IDs to sort in generation order")
+	}
+}
+
+func TestUUIDv4(t *testing.T) {
+	ids := generate(idgen.UUIDv4{}, 10000)
+	assertFormat(t, ids, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+	assertUnique(t, ids)
+}
+
+func TestUUIDv7(t *testing.T) {
+	ids := generate(&idgen.UUIDv7{}, 10000)
+	assertFormat(t, ids, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+	assertUnique(t, ids)
+	assertSorted(t, ids)
+}
+
+func TestULID(t *testing.T) {
+	ids := generate(&idgen.ULID{}, 10000)
+	assertFormat(t, ids, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`))
+	assertUnique(t, ids)
+	assertSorted(t, ids)
+}
+
+func TestSlug(t *testing.T) {
+	ids := generate(idgen.Slug{}, 10000)
+	assertFormat(t, ids, regexp.MustCompile(`^[0-9A-Za-z]{11}$`))
+	assertUnique(t, ids)
+
+	if id := (idgen.Slug{Length: 6}).NewID(); len(id) != 6 {
+		t.Errorf("Expected a 6-character slug, got %q", id)
+	}
+}
+
+func TestConcurrentGeneration(t *testing.T) {
+	for name, g := range map[string]generator{
+		"UUIDv7": &idgen.UUIDv7{},
+		"ULID":   &idgen.ULID{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var ids []string
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					batch := generate(g, 1000)
+					mu.Lock()
+					ids = append(ids, batch...)
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+			assertUnique(t, ids)
+		})
+	}
+}
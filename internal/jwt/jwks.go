@@ -0,0 +1,215 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+)
+
+const (
+	// minSecretSize is the shortest HS256 secret accepted, in bytes; RFC 7518
+	// requires the key to be at least as long as the hash.
+	minSecretSize = sha256.Size
+	minRSABits    = 2048
+)
+
+// Key is a verification key from a JWKS file. Each key verifies only its
+// own algorithm, so that an RSA public key can never be used as an HMAC
+// secret.
+type Key struct {
+	ID        string
+	Algorithm string
+	secret    []byte
+	public    *rsa.PublicKey
+}
+
+func (k Key) verify(signed, signature []byte) bool {
+	switch k.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), signature)
+	case RS256:
+		digest := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(k.public, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+// jwk is a JSON Web Key (RFC 7517) with the members of oct and RSA keys.
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	K         string `json:"k"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+// parseKeySet reads the oct and RSA signing keys of a JWKS document. Keys of
+// other types, and keys meant for encryption, are skipped.
+func parseKeySet(data []byte) ([]Key, error) {
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	var keys []Key
+	for i, raw := range document.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := parseKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d (kid %q): %w", i, raw.KeyID, err)
+		}
+		if key != nil {
+			keys = append(keys, *key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS has no HS256 or RS256 signing keys")
+	}
+	return keys, nil
+}
+
+func parseKey(raw jwk) (*Key, error) {
+	switch raw.KeyType {
+	case "oct":
+		if raw.Algorithm != "" && raw.Algorithm != HS256 {
+			return nil, fmt.Errorf("unsupported algorithm %s for an oct key", raw.Algorithm)
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(raw.K)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode k: %w", err)
+		}
+		if len(secret) < minSecretSize {
+			return nil, fmt.Errorf("secret must be at least %d bytes, got %d", minSecretSize, len(secret))
+		}
+		return &Key{ID: raw.KeyID, Algorithm: HS256, secret: secret}, nil
+	case "RSA":
+		if raw.Algorithm != "" && raw.Algorithm != RS256 {
+			return nil, fmt.Errorf("unsupported algorithm %s for an RSA key", raw.Algorithm)
+		}
+		n, err := base64.RawURLEncoding.DecodeString(raw.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode n: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(raw.E)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode e: %w", err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("unsupported RSA exponent")
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+		if public.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key must have at least %d bits, got %d", minRSABits, public.N.BitLen())
+		}
+		return &Key{ID: raw.KeyID, Algorithm: RS256, public: public}, nil
+	}
+	return nil, nil
+}
+
+// KeySource holds the keys of a JWKS file. When reloadInterval has passed
+// since the last look, the next verification checks whether the file has
+// changed and reads it again; a file that fails to load is logged and the
+// previous keys stay in use.
+type KeySource struct {
+	path           string
+	reloadInterval time.Duration
+
+	mu      sync.Mutex
+	keys    []Key
+	modTime time.Time
+	size    int64
+	checked time.Time
+}
+
+func LoadKeySource(path string, reloadInterval time.Duration) (*KeySource, error) {
+	s := &KeySource{path: path, reloadInterval: reloadInterval}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	if err := s.load(info); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *KeySource) load(info os.FileInfo) error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	keys, err := parseKeySet(data)
+	if err != nil {
+		return err
+	}
+	s.keys, s.modTime, s.size = keys, info.ModTime(), info.Size()
+	return nil
+}
+
+// Keys returns the current keys, reloading them first if they are due.
+func (s *KeySource) Keys() []Key {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.checked) < s.reloadInterval {
+		return s.keys
+	}
+	s.checked = now
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		log.Printf("Failed to check JWKS %s, keeping %d key(s): %v", s.path, len(s.keys), err)
+		return s.keys
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.keys
+	}
+	if err := s.load(info); err != nil {
+		log.Printf("Failed to reload JWKS %s, keeping %d key(s): %v", s.path, len(s.keys), err)
+		return s.keys
+	}
+	log.Printf("Reloaded %d key(s) from JWKS %s", len(s.keys), s.path)
+	return s.keys
+}
+
+// candidates returns the keys that may have signed a token with the given
+// header: those for its algorithm, narrowed to kid when it names one.
+func (s *KeySource) candidates(algorithm, kid string) ([]Key, error) {
+	if algorithm != HS256 && algorithm != RS256 {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+	var keys []Key
+	for _, key := range s.Keys() {
+		if key.Algorithm == algorithm && (kid == "" || key.ID == kid) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return keys, nil
+}
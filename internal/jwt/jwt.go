@@ -0,0 +1,185 @@
+// Package jwt verifies JSON Web Tokens (RFC 7519) signed with HS256 or RS256
+// by keys from a JWKS file. Other algorithms, including "none", are refused.
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// leeway tolerates clock differences between the issuer and this service in
+// the exp and nbf checks.
+const leeway = 30 * time.Second
+
+var (
+	ErrMalformed            = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnknownKey           = errors.New("unknown signing key")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrExpired              = errors.New("token has expired")
+	ErrNotYetValid          = errors.New("token is not valid yet")
+	ErrInvalidIssuer        = errors.New("invalid issuer")
+	ErrInvalidAudience      = errors.New("invalid audience")
+	ErrMissingClaim         = errors.New("missing required claim")
+)
+
+// Claims are the registered claims of a verified token, together with the
+// scopes granted by its scope or scp claim.
+type Claims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	Scopes    []string
+}
+
+// HasScope reports whether the token grants scope.
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+// Verifier accepts tokens signed by one of its keys, issued by issuer for
+// audience, that have not expired. Tokens without exp or sub are refused.
+type Verifier struct {
+	keys     *KeySource
+	issuer   string
+	audience string
+}
+
+func NewVerifier(keys *KeySource, issuer, audience string) *Verifier {
+	return &Verifier{keys: keys, issuer: issuer, audience: audience}
+}
+
+type header struct {
+	Algorithm string   `json:"alg"`
+	KeyID     string   `json:"kid"`
+	Critical  []string `json:"crit"`
+}
+
+type rawClaims struct {
+	Issuer    string     `json:"iss"`
+	Subject   string     `json:"sub"`
+	Audience  stringList `json:"aud"`
+	ExpiresAt *float64   `json:"exp"`
+	NotBefore *float64   `json:"nbf"`
+	IssuedAt  *float64   `json:"iat"`
+	Scope     string     `json:"scope"`
+	Scp       stringList `json:"scp"`
+}
+
+// Verify checks the signature of a compact serialized token first, and its
+// claims only once the signature is known to be good.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, err
+	}
+	if len(h.Critical) > 0 {
+		return nil, fmt.Errorf("%w: critical header parameters are not supported", ErrMalformed)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	keys, err := v.keys.candidates(h.Algorithm, h.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	if !slices.ContainsFunc(keys, func(k Key) bool { return k.verify(signed, signature) }) {
+		return nil, ErrInvalidSignature
+	}
+
+	var raw rawClaims
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, err
+	}
+	claims := &Claims{
+		Issuer:   raw.Issuer,
+		Subject:  raw.Subject,
+		Audience: raw.Audience,
+		Scopes:   append(strings.Fields(raw.Scope), raw.Scp...),
+	}
+	if raw.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: exp", ErrMissingClaim)
+	}
+	claims.ExpiresAt = numericDate(*raw.ExpiresAt)
+	if raw.NotBefore != nil {
+		claims.NotBefore = numericDate(*raw.NotBefore)
+	}
+	if raw.IssuedAt != nil {
+		claims.IssuedAt = numericDate(*raw.IssuedAt)
+	}
+	if err := v.validate(claims, time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Verifier) validate(claims *Claims, now time.Time) error {
+	if !now.Before(claims.ExpiresAt.Add(leeway)) {
+		return ErrExpired
+	}
+	if !claims.NotBefore.IsZero() && now.Add(leeway).Before(claims.NotBefore) {
+		return ErrNotYetValid
+	}
+	if claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: %q", ErrInvalidIssuer, claims.Issuer)
+	}
+	if !slices.Contains(claims.Audience, v.audience) {
+		return fmt.Errorf("%w: %q", ErrInvalidAudience, claims.Audience)
+	}
+	if claims.Subject == "" {
+		return fmt.Errorf("%w: sub", ErrMissingClaim)
+	}
+	return nil
+}
+
+func decodeSegment(segment string, dst any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return nil
+}
+
+// numericDate converts seconds since the epoch, which may have a fraction.
+func numericDate(seconds float64) time.Time {
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*1e9))
+}
+
+// stringList is a claim holding either one string or an array of them, such
+// as aud.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = stringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
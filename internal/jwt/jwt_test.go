@@ -0,0 +1,243 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"test-task-scout-go/internal/jwt"
+)
+
+const (
+	testIssuer   = "https://gateway.example"
+	testAudience = "quotes"
+)
+
+var (
+	testSecret = []byte("0123456789abcdef0123456789abcdef")
+	encode     = base64.RawURLEncoding.EncodeToString
+)
+
+// signToken serializes header and claims and signs them with key, which is
+// an HMAC secret or an RSA private key.
+func signToken(t *testing.T, header, claims map[string]any, key any) string {
+	t.Helper()
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		t.Fatalf("Failed to encode header: %v", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("Failed to encode claims: %v", err)
+	}
+	signed := encode(headerJSON) + "." + encode(claimsJSON)
+
+	var signature []byte
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+	}
+	return signed + "." + encode(signature)
+}
+
+func validClaims() map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":   testIssuer,
+		"sub":   "alice",
+		"aud":   []string{"other", testAudience},
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"scope": "quotes:read quotes:write",
+	}
+}
+
+func octJWK(kid string, secret []byte) map[string]any {
+	return map[string]any{"kty": "oct", "kid": kid, "alg": "HS256", "k": encode(secret)}
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]any {
+	return map[string]any{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": encode(key.N.Bytes()), "e": encode(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]any) {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatalf("Failed to encode JWKS: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write JWKS: %v", err)
+	}
+}
+
+func newTestVerifier(t *testing.T, keys ...map[string]any) *jwt.Verifier {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, keys...)
+	source, err := jwt.LoadKeySource(path, time.Minute)
+	if err != nil {
+		t.Fatalf("LoadKeySource failed: %v", err)
+	}
+	return jwt.NewVerifier(source, testIssuer, testAudience)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	verifier := newTestVerifier(t, octJWK("hmac", testSecret), rsaJWK("rsa", rsaKey))
+
+	for name, token := range map[string]string{
+		"HS256":        signToken(t, map[string]any{"alg": "HS256", "kid": "hmac"}, validClaims(), testSecret),
+		"RS256":        signToken(t, map[string]any{"alg": "RS256", "kid": "rsa"}, validClaims(), rsaKey),
+		"RS256 no kid": signToken(t, map[string]any{"alg": "RS256"}, validClaims(), rsaKey),
+	} {
+		t.Run(name, func(t *testing.T) {
+			claims, err := verifier.Verify(token)
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if claims.Subject != "alice" || claims.Issuer != testIssuer {
+				t.Errorf("Unexpected claims: %+v", claims)
+			}
+			if !claims.HasScope("quotes:write") || claims.HasScope("admin") {
+				t.Errorf("Unexpected scopes: %v", claims.Scopes)
+			}
+		})
+	}
+
+	t.Run("scp array and single audience", func(t *testing.T) {
+		claims := validClaims()
+		delete(claims, "scope")
+		claims["scp"] = []string{"quotes:read"}
+		claims["aud"] = testAudience
+		verified, err := verifier.Verify(signToken(t, map[string]any{"alg": "HS256"}, claims, testSecret))
+		if err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		if !verified.HasScope("quotes:read") || verified.HasScope("quotes:write") {
+			t.Errorf("Unexpected scopes: %v", verified.Scopes)
+		}
+	})
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	verifier := newTestVerifier(t, octJWK("hmac", testSecret), rsaJWK("rsa", rsaKey))
+	hs256 := map[string]any{"alg": "HS256", "kid": "hmac"}
+	withClaim := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	valid := signToken(t, hs256, validClaims(), testSecret)
+	now := time.Now()
+
+	for name, test := range map[string]struct {
+		token    string
+		expected error
+	}{
+		"malformed":          {"not-a-token", jwt.ErrMalformed},
+		"none algorithm":     {encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(`{"sub":"alice"}`)) + ".", jwt.ErrUnsupportedAlgorithm},
+		"unknown kid":        {signToken(t, map[string]any{"alg": "HS256", "kid": "old"}, validClaims(), testSecret), jwt.ErrUnknownKey},
+		"algorithm of key":   {signToken(t, map[string]any{"alg": "HS256", "kid": "rsa"}, validClaims(), testSecret), jwt.ErrUnknownKey},
+		"wrong secret":       {signToken(t, hs256, validClaims(), []byte("fedcba9876543210fedcba9876543210")), jwt.ErrInvalidSignature},
+		"tampered claims":    {valid[:len(valid)-1] + "A", jwt.ErrInvalidSignature},
+		"expired":            {signToken(t, hs256, withClaim("exp", now.Add(-time.Minute).Unix()), testSecret), jwt.ErrExpired},
+		"not yet valid":      {signToken(t, hs256, withClaim("nbf", now.Add(time.Hour).Unix()), testSecret), jwt.ErrNotYetValid},
+		"wrong issuer":       {signToken(t, hs256, withClaim("iss", "https://evil.example"), testSecret), jwt.ErrInvalidIssuer},
+		"wrong audience":     {signToken(t, hs256, withClaim("aud", "billing"), testSecret), jwt.ErrInvalidAudience},
+		"missing expiry":     {signToken(t, hs256, withClaim("exp", nil), testSecret), jwt.ErrMissingClaim},
+		"missing subject":    {signToken(t, hs256, withClaim("sub", nil), testSecret), jwt.ErrMissingClaim},
+		"critical extension": {signToken(t, map[string]any{"alg": "HS256", "crit": []string{"exp"}}, validClaims(), testSecret), jwt.ErrMalformed},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := verifier.Verify(test.token); !errors.Is(err, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestLoadKeySource_InvalidKeys(t *testing.T) {
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	for name, key := range map[string]map[string]any{
+		"short secret":      octJWK("hmac", []byte("short")),
+		"small RSA key":     rsaJWK("rsa", smallKey),
+		"foreign algorithm": {"kty": "oct", "alg": "HS512", "k": encode(testSecret)},
+		"encryption only":   {"kty": "oct", "use": "enc", "k": encode(testSecret)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			writeJWKS(t, path, key)
+			if _, err := jwt.LoadKeySource(path, time.Minute); err == nil {
+				t.Error("Expected LoadKeySource to fail")
+			}
+		})
+	}
+}
+
+func TestKeySource_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, octJWK("first", testSecret))
+	source, err := jwt.LoadKeySource(path, time.Nanosecond)
+	if err != nil {
+		t.Fatalf("LoadKeySource failed: %v", err)
+	}
+	verifier := jwt.NewVerifier(source, testIssuer, testAudience)
+
+	rotated := []byte("a different secret of 32+ bytes, for the second key")
+	first := signToken(t, map[string]any{"alg": "HS256", "kid": "first"}, validClaims(), testSecret)
+	second := signToken(t, map[string]any{"alg": "HS256", "kid": "second"}, validClaims(), rotated)
+	if _, err := verifier.Verify(first); err != nil {
+		t.Fatalf("Verify failed before the rotation: %v", err)
+	}
+
+	writeJWKS(t, path, octJWK("second", rotated))
+	if _, err := verifier.Verify(second); err != nil {
+		t.Errorf("Expected the rotated key to be loaded: %v", err)
+	}
+	if _, err := verifier.Verify(first); !errors.Is(err, jwt.ErrUnknownKey) {
+		t.Errorf("Expected the removed key to be refused, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{broken"), 0o600); err != nil {
+		t.Fatalf("Failed to write JWKS: %v", err)
+	}
+	if _, err := verifier.Verify(second); err != nil {
+		t.Errorf("Expected a broken file to keep the previous keys: %v", err)
+	}
+}
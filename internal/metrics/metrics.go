@@ -0,0 +1,341 @@
+// Package metrics collects counters, gauges and histograms and serves them in
+// the Prometheus text exposition format, version 0.0.4.
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, used by the Prometheus
+// client libraries for request latencies.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// Registry holds metric families and writes all of them on each scrape. The
+// methods creating metrics panic on an invalid or conflicting registration,
+// which is a programming error.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     metricType
+	metrics []collector
+}
+
+// collector writes the samples of one metric in a family.
+type collector interface {
+	collect(name string, b *strings.Builder)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+func (r *Registry) register(name, help string, typ metricType, c collector) {
+	if !validName(name) {
+		panic(fmt.Sprintf("metrics: invalid metric name %q", name))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, typ: typ}
+		r.families[name] = f
+	} else if f.typ != typ {
+		panic(fmt.Sprintf("metrics: %s is already registered as a %s", name, f.typ))
+	}
+	f.metrics = append(f.metrics, c)
+}
+
+// ServeHTTP writes every family, ordered by name.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	w.Write([]byte(r.Gather()))
+}
+
+// Gather returns the current value of every metric in the text format.
+func (r *Registry) Gather() string {
+	r.mu.Lock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.Unlock()
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	var b strings.Builder
+	for _, f := range families {
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.name, f.typ)
+		for _, c := range f.metrics {
+			c.collect(f.name, &b)
+		}
+	}
+	return b.String()
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	labels []string
+	mu     sync.Mutex
+	values map[string]*labeledValue
+}
+
+type labeledValue struct {
+	labelValues []string
+	value       float64
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	checkLabels(labels)
+	c := &CounterVec{labels: labels, values: make(map[string]*labeledValue)}
+	r.register(name, help, typeCounter, c)
+	return c
+}
+
+// Inc adds one to the counter with the given label values, which must match
+// the label names in number and order.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds delta, which must not be negative.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("metrics: counters cannot decrease")
+	}
+	key := labelKey(c.labels, labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[key]
+	if !ok {
+		v = &labeledValue{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = v
+	}
+	v.value += delta
+}
+
+func (c *CounterVec) collect(name string, b *strings.Builder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range sortedKeys(c.values) {
+		v := c.values[key]
+		writeSample(b, name, formatLabels(c.labels, v.labelValues, "", ""), v.value)
+	}
+}
+
+// HistogramVec counts observations in cumulative buckets, partitioned by
+// labels.
+type HistogramVec struct {
+	labels  []string
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogram
+}
+
+type histogram struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec creates a histogram with the given bucket upper bounds in
+// increasing order; the +Inf bucket is implicit.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	checkLabels(labels)
+	for _, label := range labels {
+		if label == "le" {
+			panic("metrics: histograms cannot have an le label")
+		}
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic("metrics: histogram buckets must be sorted")
+	}
+	h := &HistogramVec{labels: labels, buckets: buckets, values: make(map[string]*histogram)}
+	r.register(name, help, typeHistogram, h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := labelKey(h.labels, labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	v, ok := h.values[key]
+	if !ok {
+		v = &histogram{labelValues: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.values[key] = v
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			v.counts[i]++
+		}
+	}
+	v.count++
+	v.sum += value
+}
+
+func (h *HistogramVec) collect(name string, b *strings.Builder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range sortedKeys(h.values) {
+		v := h.values[key]
+		for i, bound := range h.buckets {
+			writeSample(b, name+"_bucket", formatLabels(h.labels, v.labelValues, "le", formatValue(bound)), float64(v.counts[i]))
+		}
+		writeSample(b, name+"_bucket", formatLabels(h.labels, v.labelValues, "le", "+Inf"), float64(v.count))
+		writeSample(b, name+"_sum", formatLabels(h.labels, v.labelValues, "", ""), v.sum)
+		writeSample(b, name+"_count", formatLabels(h.labels, v.labelValues, "", ""), float64(v.count))
+	}
+}
+
+// funcMetric reads its value when scraped. Metrics registered under the same
+// name form one family and are told apart by their constant labels.
+type funcMetric struct {
+	labels []string
+	values []string
+	fn     func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is fn's result at scrape time.
+// labelPairs are constant label names and values, alternating.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64, labelPairs ...string) {
+	r.register(name, help, typeGauge, newFuncMetric(fn, labelPairs))
+}
+
+// NewCounterFunc is NewGaugeFunc for a value that only grows.
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64, labelPairs ...string) {
+	r.register(name, help, typeCounter, newFuncMetric(fn, labelPairs))
+}
+
+func newFuncMetric(fn func() float64, labelPairs []string) *funcMetric {
+	if len(labelPairs)%2 != 0 {
+		panic("metrics: label names and values must come in pairs")
+	}
+	m := &funcMetric{fn: fn}
+	for i := 0; i < len(labelPairs); i += 2 {
+		m.labels = append(m.labels, labelPairs[i])
+		m.values = append(m.values, labelPairs[i+1])
+	}
+	checkLabels(m.labels)
+	return m
+}
+
+func (m *funcMetric) collect(name string, b *strings.Builder) {
+	writeSample(b, name, formatLabels(m.labels, m.values, "", ""), m.fn())
+}
+
+func checkLabels(labels []string) {
+	for _, label := range labels {
+		if !validName(label) || strings.Contains(label, ":") || strings.HasPrefix(label, "__") {
+			panic(fmt.Sprintf("metrics: invalid label name %q", label))
+		}
+	}
+}
+
+// validName reports whether name matches [a-zA-Z_:][a-zA-Z0-9_:]*.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		letter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == ':'
+		if !letter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func labelKey(labels, values []string) string {
+	if len(values) != len(labels) {
+		panic(fmt.Sprintf("metrics: expected %d label values, got %d", len(labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatLabels renders {name="value",...}, followed by the extra label when
+// extraName is set, or "" when there are no labels.
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", name, escapeLabelValue(values[i]))
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", extraName, extraValue)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func writeSample(b *strings.Builder, name, labels string, value float64) {
+	b.WriteString(name)
+	b.WriteString(labels)
+	b.WriteByte(' ')
+	b.WriteString(formatValue(value))
+	b.WriteByte('\n')
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,89 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"test-task-scout-go/internal/metrics"
+)
+
+func TestRegistry_Gather(t *testing.T) {
+	registry := metrics.NewRegistry()
+	requests := registry.NewCounterVec("requests_total", "Requests.\nBy path.", "path", "code")
+	latency := registry.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "path")
+	registry.NewGaugeFunc("items", "Stored items.", func() float64 { return 42 })
+	registry.NewCounterFunc("hits_total", "Hits.", func() float64 { return 3 }, "cache", "a")
+	registry.NewCounterFunc("hits_total", "Hits.", func() float64 { return 5 }, "cache", "b")
+
+	requests.Inc("/b", "200")
+	requests.Inc("/a", "200")
+	requests.Add(2, "/a", "200")
+	requests.Inc(`/"q"\`, "500")
+	latency.Observe(0.05, "/a")
+	latency.Observe(0.5, "/a")
+	latency.Observe(3, "/a")
+
+	expected := `# HELP hits_total Hits.
+# TYPE hits_total counter
+hits_total{cache="a"} 3
+hits_total{cache="b"} 5
+# HELP items Stored items.
+# TYPE items gauge
+items 42
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{path="/a",le="0.1"} 1
+latency_seconds_bucket{path="/a",le="1"} 2
+latency_seconds_bucket{path="/a",le="+Inf"} 3
+latency_seconds_sum{path="/a"} 3.55
+latency_seconds_count{path="/a"} 3
+# HELP requests_total Requests.\nBy path.
+# TYPE requests_total counter
+requests_total{path="/\"q\"\\",code="500"} 1
+requests_total{path="/a",code="200"} 3
+requests_total{path="/b",code="200"} 1
+`
+	if got := registry.Gather(); got != expected {
+		t.Errorf("Unexpected exposition:\n%s\nexpected:\n%s", got, expected)
+	}
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.NewCounterVec("events_total", "Events.").Inc()
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected Content-Type %q", contentType)
+	}
+	if !strings.Contains(recorder.Body.String(), "events_total 1\n") {
+		t.Errorf("Counter is missing from the response:\n%s", recorder.Body.String())
+	}
+}
+
+func TestRegistry_InvalidRegistrations(t *testing.T) {
+	for name, register := range map[string]func(*metrics.Registry){
+		"invalid name":  func(r *metrics.Registry) { r.NewCounterVec("1st", "") },
+		"invalid label": func(r *metrics.Registry) { r.NewCounterVec("ok_total", "", "bad-label") },
+		"le label":      func(r *metrics.Registry) { r.NewHistogramVec("latency", "", metrics.DefaultBuckets, "le") },
+		"type conflict": func(r *metrics.Registry) {
+			r.NewCounterVec("things", "")
+			r.NewGaugeFunc("things", "", func() float64 { return 0 })
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected a panic")
+				}
+			}()
+			register(metrics.NewRegistry())
+		})
+	}
+}
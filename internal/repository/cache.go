@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+)
+
+type CacheOptions struct {
+	// Size caps the entries of each cache, the quotes by ID and the lists.
+	Size int
+	TTL  time.Duration
+}
+
+// CacheStats counts lookups in one of the caches.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// CachingRepository is a read-through cache in front of another repository.
+// GetByID is served from an LRU of quotes; GetAll, GetByAuthor and List from an
+// LRU of results grouped by the author they are filtered on. Writes made
+// through the decorator evict the quote and the lists of its author, together
+// with the lists not restricted to one author. Merging authors clears both
+// caches.
+//
+// Writes made elsewhere, such as by another instance sharing a Postgres
+// database, are only seen once the cached entries expire after TTL.
+type CachingRepository struct {
+	QuoteRepository
+
+	ttl time.Duration
+
+	mu     sync.Mutex
+	quotes *lruCache[domain.Quote]
+	lists  *lruCache[cachedList]
+	// generation counts invalidations. A result read from the backend is
+	// only cached if no invalidation happened meanwhile, since it may
+	// predate the write.
+	generation uint64
+
+	quoteHits, quoteMisses atomic.Uint64
+	listHits, listMisses   atomic.Uint64
+}
+
+// cachedList is a list result; group is the author it is filtered on, or ""
+// when it may contain quotes of any author.
+type cachedList struct {
+	group  string
+	quotes []domain.Quote
+	next   string
+}
+
+func NewCachingRepository(repo QuoteRepository, opts CacheOptions) *CachingRepository {
+	return &CachingRepository{
+		QuoteRepository: repo,
+		ttl:             opts.TTL,
+		quotes:          newLRUCache[domain.Quote](opts.Size),
+		lists:           newLRUCache[cachedList](opts.Size),
+	}
+}
+
+func (r *CachingRepository) CheckHealth(ctx context.Context) error {
+	return CheckHealth(ctx, r.QuoteRepository)
+}
+
+// QuoteStats reports lookups in the cache of quotes by ID.
+func (r *CachingRepository) QuoteStats() CacheStats {
+	return CacheStats{Hits: r.quoteHits.Load(), Misses: r.quoteMisses.Load()}
+}
+
+// ListStats reports lookups in the cache of lists.
+func (r *CachingRepository) ListStats() CacheStats {
+	return CacheStats{Hits: r.listHits.Load(), Misses: r.listMisses.Load()}
+}
+
+func (r *CachingRepository) GetByID(ctx context.Context, id string) (*domain.Quote, error) {
+	r.mu.Lock()
+	quote, ok := r.quotes.get(id, time.Now())
+	generation := r.generation
+	r.mu.Unlock()
+	if ok {
+		r.quoteHits.Add(1)
+		return &quote, nil
+	}
+	r.quoteMisses.Add(1)
+
+	found, err := r.QuoteRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	if r.generation == generation {
+		r.quotes.put(id, *found, time.Now().Add(r.ttl))
+	}
+	r.mu.Unlock()
+	return found, nil
+}
+
+func (r *CachingRepository) GetAll(ctx context.Context) ([]domain.Quote, error) {
+	quotes, _, err := r.cachedList("all", "", func() ([]domain.Quote, string, error) {
+		quotes, err := r.QuoteRepository.GetAll(ctx)
+		return quotes, "", err
+	})
+	return quotes, err
+}
+
+func (r *CachingRepository) GetByAuthor(ctx context.Context, author string) ([]domain.Quote, error) {
+	quotes, _, err := r.cachedList("author:"+author, author, func() ([]domain.Quote, string, error) {
+		quotes, err := r.QuoteRepository.GetByAuthor(ctx, author)
+		return quotes, "", err
+	})
+	return quotes, err
+}
+
+func (r *CachingRepository) List(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error) {
+	key, err := json.Marshal(query)
+	if err != nil {
+		return r.QuoteRepository.List(ctx, query)
+	}
+	group := ""
+	if query.Author != "" && (query.AuthorMatch == "" || query.AuthorMatch == domain.AuthorMatchExact) {
+		group = query.Author
+	}
+	quotes, next, err := r.cachedList("list:"+string(key), group, func() ([]domain.Quote, string, error) {
+		page, err := r.QuoteRepository.List(ctx, query)
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Quotes, page.NextCursor, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.QuotePage{Quotes: quotes, NextCursor: next}, nil
+}
+
+// cachedList returns a copy of the list cached under key, or loads and caches
+// it. Copies keep callers from changing what is cached.
+func (r *CachingRepository) cachedList(key, group string, load func() ([]domain.Quote, string, error)) ([]domain.Quote, string, error) {
+	r.mu.Lock()
+	cached, ok := r.lists.get(key, time.Now())
+	generation := r.generation
+	r.mu.Unlock()
+	if ok {
+		r.listHits.Add(1)
+		return copyQuotes(cached.quotes), cached.next, nil
+	}
+	r.listMisses.Add(1)
+
+	quotes, next, err := load()
+	if err != nil {
+		return nil, "", err
+	}
+	r.mu.Lock()
+	if r.generation == generation {
+		r.lists.put(key, cachedList{group: group, quotes: copyQuotes(quotes), next: next}, time.Now().Add(r.ttl))
+	}
+	r.mu.Unlock()
+	return quotes, next, nil
+}
+
+func copyQuotes(quotes []domain.Quote) []domain.Quote {
+	if quotes == nil {
+		return nil
+	}
+	return append(make([]domain.Quote, 0, len(quotes)), quotes...)
+}
+
+// invalidate evicts the quotes with the given IDs and the lists that quotes of
+// the given authors may appear in.
+func (r *CachingRepository) invalidate(ids, authors []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	for _, id := range ids {
+		r.quotes.remove(id)
+	}
+	affected := map[string]bool{"": true}
+	for _, author := range authors {
+		affected[author] = true
+	}
+	r.lists.removeIf(func(list cachedList) bool {
+		return affected[list.group]
+	})
+}
+
+func (r *CachingRepository) invalidateAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	r.quotes.clear()
+	r.lists.clear()
+}
+
+// storedAuthor returns the author of the stored quote id, or "" when it cannot
+// be found.
+func (r *CachingRepository) storedAuthor(ctx context.Context, id string) string {
+	quote, err := r.GetByID(ctx, id)
+	if err != nil {
+		return ""
+	}
+	return quote.Author
+}
+
+func (r *CachingRepository) Create(ctx context.Context, quote *domain.Quote) error {
+	err := r.QuoteRepository.Create(ctx, quote)
+	if err == nil {
+		r.invalidate([]string{quote.ID}, []string{quote.Author})
+	}
+	return err
+}
+
+func (r *CachingRepository) CreateBatch(ctx context.Context, quotes []*domain.Quote, atomic bool) ([]error, error) {
+	rowErrs, err := r.QuoteRepository.CreateBatch(ctx, quotes, atomic)
+	if err != nil {
+		return nil, err
+	}
+	var ids, authors []string
+	for i, quote := range quotes {
+		if rowErrs[i] == nil {
+			ids = append(ids, quote.ID)
+			authors = append(authors, quote.Author)
+		}
+	}
+	if len(ids) > 0 {
+		r.invalidate(ids, authors)
+	}
+	return rowErrs, nil
+}
+
+// Update evicts the lists of both the quote's previous and new author, so the
+// previous one is looked up first.
+func (r *CachingRepository) Update(ctx context.Context, quote *domain.Quote) error {
+	previous := r.storedAuthor(ctx, quote.ID)
+	err := r.QuoteRepository.Update(ctx, quote)
+	if err == nil || isStale(err) {
+		r.invalidate([]string{quote.ID}, []string{previous, quote.Author})
+	}
+	return err
+}
+
+func (r *CachingRepository) Delete(ctx context.Context, id string, version int64) error {
+	author := r.storedAuthor(ctx, id)
+	err := r.QuoteRepository.Delete(ctx, id, version)
+	if err == nil || isStale(err) {
+		r.invalidate([]string{id}, []string{author})
+	}
+	return err
+}
+
+// isStale reports whether err suggests the cached quote is out of date, so
+// that a retry reads it from the backend.
+func isStale(err error) bool {
+	return errors.Is(err, ErrVersionConflict) || errors.Is(err, ErrNotFound)
+}
+
+func (r *CachingRepository) MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error) {
+	merged, err := r.QuoteRepository.MergeAuthors(ctx, targetID, sourceID)
+	if err == nil {
+		r.invalidateAll()
+	}
+	return merged, err
+}
+
+// lruCache holds up to size values, evicting the least recently used one to
+// make room. Expired values are dropped when they are looked up. It is not
+// safe for concurrent use.
+type lruCache[V any] struct {
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+func newLRUCache[V any](size int) *lruCache[V] {
+	return &lruCache[V]{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *lruCache[V]) get(key string, now time.Time) (V, bool) {
+	var zero V
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	entry := element.Value.(*lruEntry[V])
+	if !now.Before(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *lruCache[V]) put(key string, value V, expires time.Time) {
+	if element, ok := c.entries[key]; ok {
+		element.Value = &lruEntry[V]{key: key, value: value, expires: expires}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[V]{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[V]).key)
+	}
+}
+
+func (c *lruCache[V]) remove(key string) {
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+func (c *lruCache[V]) removeIf(match func(V) bool) {
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*lruEntry[V])
+		if match(entry.value) {
+			c.order.Remove(element)
+			delete(c.entries, entry.key)
+		}
+		element = next
+	}
+}
+
+func (c *lruCache[V]) clear() {
+	c.order.Init()
+	clear(c.entries)
+}
@@ -0,0 +1,215 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/repository"
+)
+
+func newTestCachingRepository() *repository.CachingRepository {
+	return repository.NewCachingRepository(repository.NewInMemoryRepository(), repository.CacheOptions{Size: 100, TTL: time.Minute})
+}
+
+func TestCachingRepository(t *testing.T) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		testRepositoryCreateAndGet(t, newTestCachingRepository())
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		testRepositoryCreateDuplicate(t, newTestCachingRepository())
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		testRepositoryGetAll(t, newTestCachingRepository())
+	})
+
+	t.Run("GetByAuthor", func(t *testing.T) {
+		testRepositoryGetByAuthor(t, newTestCachingRepository())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		testRepositoryDelete(t, newTestCachingRepository())
+	})
+
+	t.Run("GetRandom", func(t *testing.T) {
+		testRepositoryGetRandom(t, newTestCachingRepository())
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		testRepositorySearch(t, newTestCachingRepository())
+	})
+
+	t.Run("List", func(t *testing.T) {
+		testRepositoryList(t, newTestCachingRepository())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		testRepositoryUpdate(t, newTestCachingRepository())
+	})
+
+	t.Run("Versioning", func(t *testing.T) {
+		testRepositoryVersioning(t, newTestCachingRepository())
+	})
+
+	t.Run("AuditMetadata", func(t *testing.T) {
+		testRepositoryAuditMetadata(t, newTestCachingRepository())
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		testRepositoryTags(t, newTestCachingRepository())
+	})
+
+	t.Run("RandomFilter", func(t *testing.T) {
+		testRepositoryRandomFilter(t, newTestCachingRepository())
+	})
+
+	t.Run("DailyPins", func(t *testing.T) {
+		testRepositoryDailyPins(t, newTestCachingRepository())
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		testRepositoryCanceledContext(t, newTestCachingRepository())
+	})
+
+	t.Run("CreateBatch", func(t *testing.T) {
+		testRepositoryCreateBatch(t, newTestCachingRepository())
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		testRepositoryIterate(t, newTestCachingRepository())
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		testRepositoryDuplicates(t, newTestCachingRepository())
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		testRepositoryAuthors(t, newTestCachingRepository())
+	})
+
+	t.Run("AuthorMatch", func(t *testing.T) {
+		testRepositoryAuthorMatch(t, newTestCachingRepository())
+	})
+}
+
+func TestCachingRepository_GetByID(t *testing.T) {
+	repo := newTestCachingRepository()
+	quote := &domain.Quote{ID: "cached-1", Text: "Cached text", Author: "Cached author"}
+	if err := repo.Create(t.Context(), quote); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	first, err := repo.GetByID(t.Context(), "cached-1")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	first.Text = "Changed by the caller"
+	second, err := repo.GetByID(t.Context(), "cached-1")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if second.Text != "Cached text" {
+		t.Errorf("Cached quote was changed through a returned copy: %+v", second)
+	}
+	if stats := repo.QuoteStats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v", stats)
+	}
+
+	second.Text = "Updated text"
+	if err := repo.Update(t.Context(), second); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	updated, err := repo.GetByID(t.Context(), "cached-1")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if updated.Text != "Updated text" || updated.Version != 2 {
+		t.Errorf("Expected the updated quote after Update, got %+v", updated)
+	}
+
+	if err := repo.Delete(t.Context(), "cached-1", 0); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.GetByID(t.Context(), "cached-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after Delete, got %v", err)
+	}
+}
+
+func TestCachingRepository_ListInvalidation(t *testing.T) {
+	repo := newTestCachingRepository()
+	repo.Create(t.Context(), &domain.Quote{ID: "a-1", Text: "First of A", Author: "Author A"})
+	repo.Create(t.Context(), &domain.Quote{ID: "b-1", Text: "First of B", Author: "Author B"})
+
+	byAuthor := domain.ListQuery{QuoteFilter: domain.QuoteFilter{Author: "Author A"}, SortBy: domain.SortByID, Limit: 10}
+	all := domain.ListQuery{SortBy: domain.SortByID, Limit: 10}
+	list := func(query domain.ListQuery) []domain.Quote {
+		t.Helper()
+		page, err := repo.List(t.Context(), query)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		return page.Quotes
+	}
+
+	list(byAuthor)
+	list(all)
+	if got := list(byAuthor); len(got) != 1 {
+		t.Fatalf("Expected 1 quote of Author A, got %+v", got)
+	}
+	if stats := repo.ListStats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Expected 1 hit and 2 misses, got %+v", stats)
+	}
+
+	// A quote of another author leaves the list of Author A cached.
+	repo.Create(t.Context(), &domain.Quote{ID: "b-2", Text: "Second of B", Author: "Author B"})
+	if got := list(byAuthor); len(got) != 1 {
+		t.Errorf("Expected 1 quote of Author A, got %+v", got)
+	}
+	if got := list(all); len(got) != 3 {
+		t.Errorf("Expected the unfiltered list to be reloaded with 3 quotes, got %+v", got)
+	}
+	if stats := repo.ListStats(); stats.Hits != 2 || stats.Misses != 3 {
+		t.Errorf("Expected 2 hits and 3 misses, got %+v", stats)
+	}
+
+	repo.Create(t.Context(), &domain.Quote{ID: "a-2", Text: "Second of A", Author: "Author A"})
+	if got := list(byAuthor); len(got) != 2 {
+		t.Errorf("Expected 2 quotes of Author A after Create, got %+v", got)
+	}
+	if err := repo.Delete(t.Context(), "a-1", 0); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if got := list(byAuthor); len(got) != 1 || got[0].ID != "a-2" {
+		t.Errorf("Expected only a-2 after Delete, got %+v", got)
+	}
+	quotes, err := repo.GetByAuthor(t.Context(), "Author B")
+	if err != nil || len(quotes) != 2 {
+		t.Errorf("Expected 2 quotes of Author B, got %+v (%v)", quotes, err)
+	}
+}
+
+func TestCachingRepository_Limits(t *testing.T) {
+	repo := repository.NewCachingRepository(repository.NewInMemoryRepository(), repository.CacheOptions{Size: 2, TTL: 50 * time.Millisecond})
+	for _, id := range []string{"limit-1", "limit-2", "limit-3"} {
+		repo.Create(t.Context(), &domain.Quote{ID: id, Text: "Text " + id, Author: "Author"})
+	}
+
+	for _, id := range []string{"limit-1", "limit-2", "limit-3", "limit-3", "limit-1"} {
+		if _, err := repo.GetByID(t.Context(), id); err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+	}
+	// limit-1 was evicted to make room for limit-3.
+	if stats := repo.QuoteStats(); stats.Hits != 1 || stats.Misses != 4 {
+		t.Errorf("Expected 1 hit and 4 misses, got %+v", stats)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	repo.GetByID(t.Context(), "limit-1")
+	if stats := repo.QuoteStats(); stats.Misses != 5 {
+		t.Errorf("Expected an expired entry to miss, got %+v", stats)
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound        = errors.New("quote not found")
+	ErrAlreadyExists   = errors.New("quote with this ID already exists")
+	ErrVersionConflict = errors.New("quote version conflict")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+	ErrDuplicate       = errors.New("duplicate quote")
+
+	// ErrNoQuotes is returned when there is nothing to pick from; it matches
+	// ErrNotFound so callers can treat both the same way.
+	ErrNoQuotes error = &kindError{msg: "no quotes available", kind: ErrNotFound}
+	// ErrAuthorNotFound matches ErrNotFound as well.
+	ErrAuthorNotFound error = &kindError{msg: "author not found", kind: ErrNotFound}
+)
+
+// kindError carries its own message while belonging to a broader sentinel.
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string { return e.msg }
+
+func (e *kindError) Unwrap() error { return e.kind }
+
+// DuplicateError reports that a quote repeats the quote ExistingID. It matches
+// ErrDuplicate. Similarity is 1 for an exact duplicate, that is an equal
+// fingerprint, and the similarity score otherwise.
+type DuplicateError struct {
+	ExistingID string
+	Similarity float64
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate of quote %s", e.ExistingID)
+}
+
+func (e *DuplicateError) Is(target error) bool { return target == ErrDuplicate }
+
+// AuthorExistsError reports that Name already belongs to the author
+// ExistingID. It matches ErrAlreadyExists.
+type AuthorExistsError struct {
+	ExistingID string
+	Name       string
+}
+
+func (e *AuthorExistsError) Error() string {
+	return fmt.Sprintf("author name %q is taken by author %s", e.Name, e.ExistingID)
+}
+
+func (e *AuthorExistsError) Is(target error) bool { return target == ErrAlreadyExists }
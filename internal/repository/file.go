@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// fileFormatVersion is written to every data file; newer files are refused
+// rather than silently losing what this build does not understand.
+const fileFormatVersion = 1
+
+type fileData struct {
+	Version int `json:"version"`
+	memorySnapshot
+}
+
+var errFileLocked = errors.New("data file is in use by another process")
+
+// FileRepository keeps quotes in memory like InMemoryRepository and writes the
+// whole data set to a JSON file after every change, which suits the small
+// collections it is meant for and needs no cgo. The file is replaced by
+// writing a temporary file next to it and renaming it over the old one, so a
+// crash leaves either the previous or the new data, never a mix. A lock file
+// held until Close keeps a second process from using the same data file.
+//
+// A change that cannot be written stays in memory and its error is returned;
+// the next successful write persists it.
+type FileRepository struct {
+	*InMemoryRepository
+	path string
+	lock *fileLock
+	// saveMu orders writes, so the file always ends up with the latest state.
+	saveMu sync.Mutex
+}
+
+func NewFileRepository(path string) (*FileRepository, error) {
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	repo := &FileRepository{InMemoryRepository: NewInMemoryRepository(), path: path, lock: lock}
+	if err := repo.load(); err != nil {
+		lock.release()
+		return nil, err
+	}
+	return repo, nil
+}
+
+// load reads the data file, or creates it when missing so that an unwritable
+// location is reported at startup rather than on the first change.
+func (r *FileRepository) load() error {
+	content, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r.save()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var data fileData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to parse data file %s: %w", r.path, err)
+	}
+	if data.Version > fileFormatVersion {
+		return fmt.Errorf("data file %s has format version %d, this build supports up to %d", r.path, data.Version, fileFormatVersion)
+	}
+	if err := r.restore(data.memorySnapshot); err != nil {
+		return fmt.Errorf("failed to load data file %s: %w", r.path, err)
+	}
+	return nil
+}
+
+func (r *FileRepository) save() error {
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+
+	content, err := json.MarshalIndent(fileData{Version: fileFormatVersion, memorySnapshot: r.snapshot()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode data file: %w", err)
+	}
+	if err := writeFileAtomic(r.path, content); err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with content through a synced temporary file
+// in the same directory, since a rename is only atomic within a filesystem.
+func writeFileAtomic(path string, content []byte) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	_, err = tmp.Write(content)
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Syncing the directory makes the rename itself durable. Not every
+	// platform can open a directory for that, so it is best effort.
+	if d, dirErr := os.Open(dir); dirErr == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// Close releases the lock on the data file. Every change has already been
+// written.
+func (r *FileRepository) Close() error {
+	return r.lock.release()
+}
+
+func (r *FileRepository) Create(ctx context.Context, quote *domain.Quote) error {
+	if err := r.InMemoryRepository.Create(ctx, quote); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreateBatch(ctx context.Context, quotes []*domain.Quote, atomic bool) ([]error, error) {
+	rowErrs, err := r.InMemoryRepository.CreateBatch(ctx, quotes, atomic)
+	if err != nil {
+		return nil, err
+	}
+	for _, rowErr := range rowErrs {
+		if rowErr == nil {
+			return rowErrs, r.save()
+		}
+	}
+	return rowErrs, nil
+}
+
+func (r *FileRepository) Update(ctx context.Context, quote *domain.Quote) error {
+	if err := r.InMemoryRepository.Update(ctx, quote); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) Delete(ctx context.Context, id string, version int64) error {
+	if err := r.InMemoryRepository.Delete(ctx, id, version); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) SetDailyPin(ctx context.Context, date, quoteID string) error {
+	if err := r.InMemoryRepository.SetDailyPin(ctx, date, quoteID); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) DeleteDailyPin(ctx context.Context, date string) error {
+	if err := r.InMemoryRepository.DeleteDailyPin(ctx, date); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	if err := r.InMemoryRepository.CreateAuthor(ctx, author); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error) {
+	merged, err := r.InMemoryRepository.MergeAuthors(ctx, targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
@@ -0,0 +1,299 @@
+package repository_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/repository"
+)
+
+func newTestFileRepository(t *testing.T) (*repository.FileRepository, func()) {
+	t.Helper()
+
+	repo, err := repository.NewFileRepository(filepath.Join(t.TempDir(), "quotes.json"))
+	if err != nil {
+		t.Fatalf("Failed to initialize file repository: %v", err)
+	}
+	return repo, func() { repo.Close() }
+}
+
+func TestFileRepository(t *testing.T) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryCreateAndGet(t, repo)
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryCreateDuplicate(t, repo)
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryGetAll(t, repo)
+	})
+
+	t.Run("GetByAuthor", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryGetByAuthor(t, repo)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryDelete(t, repo)
+	})
+
+	t.Run("GetRandom", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryGetRandom(t, repo)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositorySearch(t, repo)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryList(t, repo)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryUpdate(t, repo)
+	})
+
+	t.Run("Versioning", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryVersioning(t, repo)
+	})
+
+	t.Run("AuditMetadata", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryAuditMetadata(t, repo)
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryTags(t, repo)
+	})
+
+	t.Run("RandomFilter", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryRandomFilter(t, repo)
+	})
+
+	t.Run("DailyPins", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryDailyPins(t, repo)
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryCanceledContext(t, repo)
+	})
+
+	t.Run("CreateBatch", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryCreateBatch(t, repo)
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryIterate(t, repo)
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryDuplicates(t, repo)
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryAuthors(t, repo)
+	})
+
+	t.Run("AuthorMatch", func(t *testing.T) {
+		repo, cleanup := newTestFileRepository(t)
+		defer cleanup()
+		testRepositoryAuthorMatch(t, repo)
+	})
+}
+
+func TestFileRepository_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	repo, err := repository.NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("NewFileRepository failed: %v", err)
+	}
+
+	author := &domain.Author{Name: "Persisted Author", Bio: "Bio", Aliases: []string{"P. Author"}}
+	if err := repo.CreateAuthor(t.Context(), author); err != nil {
+		t.Fatalf("CreateAuthor failed: %v", err)
+	}
+	quote := &domain.Quote{ID: "file-1", Text: "Kept on disk", Author: "P. Author", Tags: []string{"disk"}}
+	if err := repo.Create(t.Context(), quote); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	quote.Text = "Kept on disk, edited"
+	if err := repo.Update(t.Context(), quote); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := repo.Create(t.Context(), &domain.Quote{ID: "file-2", Text: "Deleted later", Author: "Someone"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := repo.Delete(t.Context(), "file-2", 0); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.SetDailyPin(t.Context(), "2024-03-01", "file-1"); err != nil {
+		t.Fatalf("SetDailyPin failed: %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	repo, err = repository.NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("Reopening the data file failed: %v", err)
+	}
+	defer repo.Close()
+
+	stored, err := repo.GetByID(t.Context(), "file-1")
+	if err != nil {
+		t.Fatalf("GetByID failed after reopening: %v", err)
+	}
+	if stored.Text != quote.Text || stored.Version != 2 || stored.AuthorID != author.ID || stored.Author != "Persisted Author" ||
+		!stored.CreatedAt.Equal(quote.CreatedAt) || !stored.UpdatedAt.Equal(quote.UpdatedAt) || len(stored.Tags) != 1 {
+		t.Errorf("Quote changed on reopening. Expected %+v, got %+v", quote, stored)
+	}
+	if _, err := repo.GetByID(t.Context(), "file-2"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected the deleted quote to stay deleted, got %v", err)
+	}
+	if quoteID, err := repo.GetDailyPin(t.Context(), "2024-03-01"); err != nil || quoteID != "file-1" {
+		t.Errorf("Expected pin file-1 after reopening, got %q (%v)", quoteID, err)
+	}
+
+	storedAuthor, err := repo.GetAuthor(t.Context(), author.ID)
+	if err != nil {
+		t.Fatalf("GetAuthor failed after reopening: %v", err)
+	}
+	if storedAuthor.Bio != "Bio" || len(storedAuthor.Aliases) != 1 || storedAuthor.QuoteCount != 1 {
+		t.Errorf("Author changed on reopening: %+v", storedAuthor)
+	}
+
+	// The indexes are rebuilt, not just the data.
+	err = repo.Create(t.Context(), &domain.Quote{ID: "file-3", Text: "kept on disk, EDITED", Author: "persisted author"})
+	var duplicate *repository.DuplicateError
+	if !errors.As(err, &duplicate) || duplicate.ExistingID != "file-1" {
+		t.Errorf("Expected duplicate of file-1 after reopening, got %v", err)
+	}
+	results, err := repo.Search(t.Context(), "edited", 10)
+	if err != nil || len(results) != 1 {
+		t.Errorf("Expected one search result after reopening, got %d (%v)", len(results), err)
+	}
+}
+
+func TestFileRepository_Lock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	repo, err := repository.NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("NewFileRepository failed: %v", err)
+	}
+
+	if _, err := repository.NewFileRepository(path); err == nil {
+		t.Fatal("Expected a second repository on a locked data file to fail")
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	repo, err = repository.NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("Expected the data file to be free after Close, got %v", err)
+	}
+	repo.Close()
+}
+
+func TestFileRepository_AtomicWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "quotes.json")
+	repo, err := repository.NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("NewFileRepository failed: %v", err)
+	}
+	defer repo.Close()
+
+	for _, id := range []string{"atomic-1", "atomic-2"} {
+		if err := repo.Create(t.Context(), &domain.Quote{ID: id, Text: "Text of " + id, Author: "Author"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != "quotes.json" && entry.Name() != "quotes.json.lock" {
+			t.Errorf("Unexpected file left next to the data file: %s", entry.Name())
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	var data struct {
+		Version int            `json:"version"`
+		Quotes  []domain.Quote `json:"quotes"`
+	}
+	if err := json.Unmarshal(content, &data); err != nil {
+		t.Fatalf("Data file is not valid JSON: %v", err)
+	}
+	if data.Version != 1 || len(data.Quotes) != 2 {
+		t.Errorf("Expected format version 1 with 2 quotes, got version %d with %d", data.Version, len(data.Quotes))
+	}
+}
+
+func TestFileRepository_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := repository.NewFileRepository(path); err == nil {
+		t.Fatal("Expected an invalid data file to be refused")
+	}
+
+	// A refused file does not stay locked.
+	if err := os.WriteFile(path, []byte(`{"version": 1}`), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	repo, err := repository.NewFileRepository(path)
+	if err != nil {
+		t.Fatalf("NewFileRepository failed on a fixed data file: %v", err)
+	}
+	repo.Close()
+}
@@ -0,0 +1,30 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package repository
+
+import (
+	"errors"
+	"os"
+)
+
+// fileLock is a lock file that exists only while it is held. Without flock(2)
+// a process that crashes leaves it behind, and it has to be removed by hand.
+type fileLock struct {
+	path string
+}
+
+func lockFile(path string) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return nil, errFileLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return &fileLock{path: path}, nil
+}
+
+func (l *fileLock) release() error {
+	return os.Remove(l.path)
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"iter"
 	"math/rand"
 	"test-task-scout-go/internal/domain"
 	"sync"
@@ -13,51 +15,235 @@ func init() {
 } 
 
 type InMemoryRepository struct {
-	mu    sync.RWMutex
+	mu     sync.RWMutex
 	quotes map[string]domain.Quote
+	index  *searchIndex
+	sorted map[domain.SortField]*sortedIndex
+	tags   map[string]map[string]struct{}
+	pins   map[string]string
+	// fingerprints maps each quote's content fingerprint to its ID.
+	fingerprints map[string]string
+	authors      map[string]domain.Author
+	// authorNames maps the domain.AuthorKey of every author name to its ID.
+	authorNames map[string]string
+	// wal, when set, makes changes durable; see NewDurableInMemoryRepository.
+	wal *writeAheadLog
+	// pending collects the changes of the write in progress for the log.
+	pending []walRecord
 }
 
 func NewInMemoryRepository() *InMemoryRepository {
 	return &InMemoryRepository{
 		quotes: make(map[string]domain.Quote),
+		index:  newSearchIndex(),
+		sorted: map[domain.SortField]*sortedIndex{
+			domain.SortByID:      {},
+			domain.SortByAuthor:  {},
+			domain.SortByCreated: {},
+		},
+		tags:         make(map[string]map[string]struct{}),
+		pins:         make(map[string]string),
+		fingerprints: make(map[string]string),
+		authors:      make(map[string]domain.Author),
+		authorNames:  make(map[string]string),
 	}
 }
 
-func (r *InMemoryRepository) Create(quote *domain.Quote) error {
+func (r *InMemoryRepository) indexTags(quote domain.Quote) {
+	for _, tag := range quote.Tags {
+		ids, ok := r.tags[tag]
+		if !ok {
+			ids = make(map[string]struct{})
+			r.tags[tag] = ids
+		}
+		ids[quote.ID] = struct{}{}
+	}
+}
+
+func (r *InMemoryRepository) unindexTags(quote domain.Quote) {
+	for _, tag := range quote.Tags {
+		delete(r.tags[tag], quote.ID)
+		if len(r.tags[tag]) == 0 {
+			delete(r.tags, tag)
+		}
+	}
+}
+
+// scanCheckInterval is how many quotes a full in-memory scan visits between
+// checks for cancellation, so that an abandoned request stops early without
+// paying for a ctx.Err call on every element.
+const scanCheckInterval = 256
+
+func checkCanceled(ctx context.Context, visited int) error {
+	if visited%scanCheckInterval != 0 {
+		return nil
+	}
+	return ctx.Err()
+}
+
+func sortEntry(field domain.SortField, quote domain.Quote) indexEntry {
+	switch field {
+	case domain.SortByAuthor:
+		return indexEntry{key: quote.Author, id: quote.ID}
+	case domain.SortByCreated:
+		return indexEntry{key: formatTimestamp(quote.CreatedAt), id: quote.ID}
+	default:
+		return indexEntry{key: quote.ID, id: quote.ID}
+	}
+}
+
+func (r *InMemoryRepository) Create(ctx context.Context, quote *domain.Quote) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if err := r.writable(); err != nil {
+		return err
+	}
 	if _, exists := r.quotes[quote.ID]; exists {
-		return errors.New("quote with this ID already exists")
+		return ErrAlreadyExists
+	}
+	quote.Author = r.canonicalAuthor(quote.Author)
+	if id, exists := r.fingerprints[fingerprint(*quote)]; exists {
+		return &DuplicateError{ExistingID: id, Similarity: 1}
+	}
+	r.insert(quote)
+	return r.commit()
+}
+
+func fingerprint(quote domain.Quote) string {
+	return domain.Fingerprint(quote.Text, quote.Author)
+}
+
+// unindexFingerprint forgets the quote's fingerprint unless another quote owns
+// it, which happens when merging authors turned two quotes into duplicates.
+func (r *InMemoryRepository) unindexFingerprint(quote domain.Quote) {
+	fp := fingerprint(quote)
+	if r.fingerprints[fp] == quote.ID {
+		delete(r.fingerprints, fp)
+	}
+}
+
+// insert stores a new quote and indexes it. The caller holds the write lock
+// and has checked that the ID is free.
+func (r *InMemoryRepository) insert(quote *domain.Quote) {
+	r.resolveAuthor(quote)
+	quote.Version = 1
+	quote.Tags = domain.NormalizeTags(quote.Tags)
+	stampCreated(quote)
+	r.store(*quote)
+}
+
+// store adds a quote to every index as it is. Its fingerprint is only
+// recorded if no other quote has it. The caller holds the write lock.
+func (r *InMemoryRepository) store(quote domain.Quote) {
+	r.record(walRecord{Op: walPutQuote, Quote: &quote})
+	r.quotes[quote.ID] = quote
+	if _, taken := r.fingerprints[fingerprint(quote)]; !taken {
+		r.fingerprints[fingerprint(quote)] = quote.ID
+	}
+	r.index.add(quote)
+	r.indexTags(quote)
+	for field, idx := range r.sorted {
+		idx.insert(sortEntry(field, quote))
+	}
+}
+
+func (r *InMemoryRepository) CreateBatch(ctx context.Context, quotes []*domain.Quote, atomic bool) ([]error, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.writable(); err != nil {
+		return nil, err
+	}
+
+	rowErrs := make([]error, len(quotes))
+	seen := make(map[string]struct{}, len(quotes))
+	seenFingerprints := make(map[string]string, len(quotes))
+	failed := false
+	for i, quote := range quotes {
+		_, exists := r.quotes[quote.ID]
+		_, repeated := seen[quote.ID]
+		quote.Author = r.canonicalAuthor(quote.Author)
+		fp := fingerprint(*quote)
+		existingID, duplicate := r.fingerprints[fp]
+		if !duplicate {
+			existingID, duplicate = seenFingerprints[fp]
+		}
+		switch {
+		case exists || repeated:
+			rowErrs[i] = ErrAlreadyExists
+		case duplicate:
+			rowErrs[i] = &DuplicateError{ExistingID: existingID, Similarity: 1}
+		default:
+			seen[quote.ID] = struct{}{}
+			seenFingerprints[fp] = quote.ID
+			continue
+		}
+		failed = true
 	}
-	r.quotes[quote.ID] = *quote
-	return nil
+	if atomic && failed {
+		return rowErrs, nil
+	}
+
+	for i, quote := range quotes {
+		if rowErrs[i] == nil {
+			r.insert(quote)
+		}
+	}
+	if err := r.commit(); err != nil {
+		return nil, err
+	}
+	return rowErrs, nil
 }
 
-func (r *InMemoryRepository) GetAll() ([]domain.Quote, error) {
+func (r *InMemoryRepository) GetAll(ctx context.Context) ([]domain.Quote, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	quotes := make([]domain.Quote, 0, len(r.quotes))
 	for _, quote := range r.quotes {
+		if err := checkCanceled(ctx, len(quotes)); err != nil {
+			return nil, err
+		}
 		quotes = append(quotes, quote)
 	}
 	return quotes, nil
 }
 
-func (r *InMemoryRepository) GetByID(id string) (*domain.Quote, error) {
+// CheckHealth fails once the write-ahead log has failed or the repository is
+// closed; a repository without a log is always healthy.
+func (r *InMemoryRepository) CheckHealth(ctx context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.writable()
+}
+
+func (r *InMemoryRepository) Count(ctx context.Context) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.quotes), nil
+}
+
+func (r *InMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Quote, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	quote, exists := r.quotes[id]
 	if !exists {
-		return nil, errors.New("quote not found")
+		return nil, ErrNotFound
 	}
 	return &quote, nil
 }
 
-func (r *InMemoryRepository) GetByAuthor(author string) ([]domain.Quote, error) {
+func (r *InMemoryRepository) GetByAuthor(ctx context.Context, author string) ([]domain.Quote, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	var filteredQuotes []domain.Quote
+	visited := 0
 	for _, quote := range r.quotes {
+		if err := checkCanceled(ctx, visited); err != nil {
+			return nil, err
+		}
+		visited++
 		if quote.Author == author {
 			filteredQuotes = append(filteredQuotes, quote)
 		}
@@ -65,27 +251,262 @@ func (r *InMemoryRepository) GetByAuthor(author string) ([]domain.Quote, error)
 	return filteredQuotes, nil
 }
 
-func (r *InMemoryRepository) Delete(id string) error {
+func (r *InMemoryRepository) Update(ctx context.Context, quote *domain.Quote) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, exists := r.quotes[id]; !exists {
-		return errors.New("quote not found")
+	if err := r.writable(); err != nil {
+		return err
+	}
+	existing, exists := r.quotes[quote.ID]
+	if !exists {
+		return ErrNotFound
+	}
+	if quote.Version != 0 && quote.Version != existing.Version {
+		return ErrVersionConflict
+	}
+	quote.Author = r.canonicalAuthor(quote.Author)
+	if id, exists := r.fingerprints[fingerprint(*quote)]; exists && id != quote.ID {
+		return &DuplicateError{ExistingID: id, Similarity: 1}
+	}
+	r.resolveAuthor(quote)
+	quote.Version = existing.Version + 1
+	quote.CreatedAt = existing.CreatedAt
+	quote.CreatedBy = existing.CreatedBy
+	quote.Tags = domain.NormalizeTags(quote.Tags)
+	stampUpdated(quote)
+	r.replace(existing, *quote)
+	return r.commit()
+}
+
+// replace swaps a stored quote for its new state in every index. The caller
+// holds the write lock.
+func (r *InMemoryRepository) replace(existing, quote domain.Quote) {
+	for field, idx := range r.sorted {
+		idx.remove(sortEntry(field, existing))
+	}
+	r.unindexTags(existing)
+	r.unindexFingerprint(existing)
+	r.store(quote)
+}
+
+func (r *InMemoryRepository) Delete(ctx context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.writable(); err != nil {
+		return err
+	}
+	quote, exists := r.quotes[id]
+	if !exists {
+		return ErrNotFound
+	}
+	if version != 0 && version != quote.Version {
+		return ErrVersionConflict
+	}
+	r.remove(quote)
+	return r.commit()
+}
+
+// remove drops a stored quote from every index. The caller holds the write
+// lock.
+func (r *InMemoryRepository) remove(quote domain.Quote) {
+	r.record(walRecord{Op: walDeleteQuote, ID: quote.ID})
+	for field, idx := range r.sorted {
+		idx.remove(sortEntry(field, quote))
 	}
-	delete(r.quotes, id)
-	return nil
+	delete(r.quotes, quote.ID)
+	r.unindexFingerprint(quote)
+	r.index.remove(quote.ID)
+	r.unindexTags(quote)
 }
 
-func (r *InMemoryRepository) GetRandom() (*domain.Quote, error) {
+// GetRandom picks uniformly among the quotes matching filter using reservoir
+// sampling, so no candidate slice is built. When tags are given only quotes from
+// the tag index are considered.
+func (r *InMemoryRepository) GetRandom(ctx context.Context, filter domain.QuoteFilter) (*domain.Quote, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if len(r.quotes) == 0 {
-		return nil, errors.New("no quotes available")
+
+	var picked *domain.Quote
+	seen, visited := 0, 0
+	matches := r.quoteMatcher(filter)
+	consider := func(quote domain.Quote) error {
+		if err := checkCanceled(ctx, visited); err != nil {
+			return err
+		}
+		visited++
+		if !matches(quote) {
+			return nil
+		}
+		seen++
+		if rand.Intn(seen) == 0 {
+			picked = &quote
+		}
+		return nil
 	}
-	quotes := make([]domain.Quote, 0, len(r.quotes))
-	for _, quote := range r.quotes {
-		quotes = append(quotes, quote)
+
+	if len(filter.Tags) == 0 {
+		for _, quote := range r.quotes {
+			if err := consider(quote); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for id := range r.taggedCandidates(filter) {
+			if err := consider(r.quotes[id]); err != nil {
+				return nil, err
+			}
+		}
 	}
-	rand.Seed(time.Now().UnixNano())
-	randomIndex := rand.Intn(len(quotes))
-	return &quotes[randomIndex], nil
-} 
\ No newline at end of file
+
+	if picked == nil {
+		return nil, ErrNoQuotes
+	}
+	return picked, nil
+}
+
+// taggedCandidates returns the IDs carrying any of the filter's tags, or for
+// TagModeAll the ones under the rarest tag, which every match must be in.
+func (r *InMemoryRepository) taggedCandidates(filter domain.QuoteFilter) map[string]struct{} {
+	if filter.TagMode == domain.TagModeAll {
+		var smallest map[string]struct{}
+		for _, tag := range filter.Tags {
+			ids := r.tags[tag]
+			if smallest == nil || len(ids) < len(smallest) {
+				smallest = ids
+			}
+		}
+		return smallest
+	}
+
+	candidates := make(map[string]struct{})
+	for _, tag := range filter.Tags {
+		for id := range r.tags[tag] {
+			candidates[id] = struct{}{}
+		}
+	}
+	return candidates
+}
+
+func (r *InMemoryRepository) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make([]domain.TagCount, 0, len(r.tags))
+	for tag, ids := range r.tags {
+		counts = append(counts, domain.TagCount{Tag: tag, Count: len(ids)})
+	}
+	sortTagCounts(counts)
+	return counts, nil
+}
+
+func (r *InMemoryRepository) Search(ctx context.Context, query string, limit int) ([]domain.SearchResult, error) {
+	terms := queryTerms(query)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	docs := r.index.search(terms, limit)
+	results := make([]domain.SearchResult, 0, len(docs))
+	for _, doc := range docs {
+		quote := r.quotes[doc.id]
+		results = append(results, domain.SearchResult{
+			Quote:   quote,
+			Score:   doc.score,
+			Snippet: snippet(quote.Text, terms),
+		})
+	}
+	return results, nil
+}
+
+func (r *InMemoryRepository) List(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error) {
+	cursor, err := decodeCursor(query.Cursor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	idx, ok := r.sorted[query.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort field: %s", query.SortBy)
+	}
+
+	var after *indexEntry
+	if cursor != nil {
+		after = &indexEntry{key: cursor.Key, id: cursor.ID}
+	}
+
+	page := &domain.QuotePage{Quotes: []domain.Quote{}}
+	matches := r.quoteMatcher(query.QuoteFilter)
+	var last indexEntry
+	visited := 0
+	idx.walk(after, query.Descending, func(entry indexEntry) bool {
+		// Selective filters can walk most of the index before filling a page.
+		if err = checkCanceled(ctx, visited); err != nil {
+			return false
+		}
+		visited++
+		quote := r.quotes[entry.id]
+		if !matches(quote) {
+			return true
+		}
+		if len(page.Quotes) == query.Limit {
+			page.NextCursor = encodeCursor(pageCursor{
+				SortBy:     query.SortBy,
+				Descending: query.Descending,
+				Key:        last.key,
+				ID:         last.id,
+			})
+			return false
+		}
+		page.Quotes = append(page.Quotes, quote)
+		last = entry
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (r *InMemoryRepository) Iterate(ctx context.Context, filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+	return iteratePages(ctx, r.List, filter)
+}
+
+func (r *InMemoryRepository) GetDailyPin(ctx context.Context, date string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	quoteID, exists := r.pins[date]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return quoteID, nil
+}
+
+func (r *InMemoryRepository) SetDailyPin(ctx context.Context, date, quoteID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.writable(); err != nil {
+		return err
+	}
+	if _, exists := r.quotes[quoteID]; !exists {
+		return ErrNotFound
+	}
+	r.record(walRecord{Op: walSetPin, Date: date, QuoteID: quoteID})
+	r.pins[date] = quoteID
+	return r.commit()
+}
+
+func (r *InMemoryRepository) DeleteDailyPin(ctx context.Context, date string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.writable(); err != nil {
+		return err
+	}
+	if _, exists := r.pins[date]; !exists {
+		return ErrNotFound
+	}
+	r.record(walRecord{Op: walDeletePin, Date: date})
+	delete(r.pins, date)
+	return r.commit()
+}
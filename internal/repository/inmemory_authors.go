@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// canonicalAuthor returns the canonical spelling of name if it belongs to a
+// stored author, and name itself otherwise.
+func (r *InMemoryRepository) canonicalAuthor(name string) string {
+	if id, exists := r.authorNames[domain.AuthorKey(name)]; exists {
+		return r.authors[id].Name
+	}
+	return name
+}
+
+// quoteMatcher returns the predicate for filter. An author filter other than
+// an exact one is checked against the names of every author, aliases
+// included, as in the SQLite repository. The caller holds the lock.
+func (r *InMemoryRepository) quoteMatcher(filter domain.QuoteFilter) func(domain.Quote) bool {
+	if filter.Author == "" || filter.AuthorMatch == "" || filter.AuthorMatch == domain.AuthorMatchExact {
+		return filter.Matches
+	}
+	query := domain.AuthorKey(filter.Author)
+	authorIDs := make(map[string]bool)
+	for key, id := range r.authorNames {
+		if filter.AuthorMatch.MatchesKey(query, key) {
+			authorIDs[id] = true
+		}
+	}
+	filter.Author = ""
+	return func(quote domain.Quote) bool {
+		return authorIDs[quote.AuthorID] && filter.Matches(quote)
+	}
+}
+
+// resolveAuthor points quote at the author its Author name belongs to,
+// creating the author if the name is new. The caller holds the write lock.
+func (r *InMemoryRepository) resolveAuthor(quote *domain.Quote) {
+	if id, exists := r.authorNames[domain.AuthorKey(quote.Author)]; exists {
+		quote.AuthorID = id
+		quote.Author = r.authors[id].Name
+		return
+	}
+	author := domain.Author{ID: authorIDs.NewID(), Name: quote.Author, CreatedAt: time.Now().UTC()}
+	r.insertAuthor(&author)
+	quote.AuthorID = author.ID
+}
+
+// insertAuthor stores an author whose names are known to be free.
+func (r *InMemoryRepository) insertAuthor(author *domain.Author) {
+	normalizeAliases(author)
+	stored := *author
+	r.record(walRecord{Op: walPutAuthor, Author: &stored})
+	r.authors[author.ID] = stored
+	r.authorNames[domain.AuthorKey(author.Name)] = author.ID
+	for _, alias := range author.Aliases {
+		r.authorNames[domain.AuthorKey(alias)] = author.ID
+	}
+}
+
+func (r *InMemoryRepository) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.writable(); err != nil {
+		return err
+	}
+	for _, name := range append([]string{author.Name}, author.Aliases...) {
+		if id, exists := r.authorNames[domain.AuthorKey(name)]; exists {
+			return &AuthorExistsError{ExistingID: id, Name: name}
+		}
+	}
+	author.ID = authorIDs.NewID()
+	if author.CreatedAt.IsZero() {
+		author.CreatedAt = time.Now().UTC()
+	}
+	r.insertAuthor(author)
+	return r.commit()
+}
+
+// withQuoteCount copies a stored author with its quote count filled in.
+func withQuoteCount(author domain.Author, counts map[string]int) domain.Author {
+	author.Aliases = append([]string{}, author.Aliases...)
+	author.QuoteCount = counts[author.ID]
+	return author
+}
+
+func (r *InMemoryRepository) quoteCounts() map[string]int {
+	counts := make(map[string]int, len(r.authors))
+	for _, quote := range r.quotes {
+		counts[quote.AuthorID]++
+	}
+	return counts
+}
+
+func (r *InMemoryRepository) GetAuthor(ctx context.Context, id string) (*domain.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	author, exists := r.authors[id]
+	if !exists {
+		return nil, ErrAuthorNotFound
+	}
+	author = withQuoteCount(author, r.quoteCounts())
+	return &author, nil
+}
+
+func (r *InMemoryRepository) ListAuthors(ctx context.Context) ([]domain.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	counts := r.quoteCounts()
+	authors := make([]domain.Author, 0, len(r.authors))
+	for _, author := range r.authors {
+		authors = append(authors, withQuoteCount(author, counts))
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Name != authors[j].Name {
+			return authors[i].Name < authors[j].Name
+		}
+		return authors[i].ID < authors[j].ID
+	})
+	return authors, nil
+}
+
+func (r *InMemoryRepository) MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error) {
+	if targetID == sourceID {
+		return r.GetAuthor(ctx, targetID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.writable(); err != nil {
+		return nil, err
+	}
+	target, exists := r.authors[targetID]
+	if !exists {
+		return nil, ErrAuthorNotFound
+	}
+	source, exists := r.authors[sourceID]
+	if !exists {
+		return nil, ErrAuthorNotFound
+	}
+
+	// Oldest first, so that of two quotes turned into duplicates the older one
+	// keeps its fingerprint, as in the SQLite repository.
+	var moved []domain.Quote
+	for _, quote := range r.quotes {
+		if quote.AuthorID == sourceID {
+			moved = append(moved, quote)
+		}
+	}
+	sort.Slice(moved, func(i, j int) bool {
+		if !moved[i].CreatedAt.Equal(moved[j].CreatedAt) {
+			return moved[i].CreatedAt.Before(moved[j].CreatedAt)
+		}
+		return moved[i].ID < moved[j].ID
+	})
+	now := time.Now().UTC()
+	for _, existing := range moved {
+		quote := existing
+		quote.AuthorID = targetID
+		quote.Author = target.Name
+		quote.Version++
+		quote.UpdatedAt = now
+		r.replace(existing, quote)
+	}
+
+	target.Aliases = append(target.Aliases, source.Name)
+	target.Aliases = append(target.Aliases, source.Aliases...)
+	normalizeAliases(&target)
+	if target.Bio == "" {
+		target.Bio = source.Bio
+	}
+	r.record(walRecord{Op: walPutAuthor, Author: &target})
+	r.record(walRecord{Op: walDeleteAuthor, ID: sourceID})
+	r.authors[targetID] = target
+	for key, id := range r.authorNames {
+		if id == sourceID {
+			r.authorNames[key] = targetID
+		}
+	}
+	delete(r.authors, sourceID)
+	if err := r.commit(); err != nil {
+		return nil, err
+	}
+
+	merged := withQuoteCount(target, r.quoteCounts())
+	return &merged, nil
+}
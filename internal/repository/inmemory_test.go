@@ -30,4 +30,60 @@ func TestInMemoryRepository(t *testing.T) {
 	t.Run("GetRandom", func(t *testing.T) {
 		testRepositoryGetRandom(t, repository.NewInMemoryRepository())
 	})
-} 
\ No newline at end of file
+
+	t.Run("Search", func(t *testing.T) {
+		testRepositorySearch(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("List", func(t *testing.T) {
+		testRepositoryList(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		testRepositoryUpdate(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("Versioning", func(t *testing.T) {
+		testRepositoryVersioning(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("AuditMetadata", func(t *testing.T) {
+		testRepositoryAuditMetadata(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		testRepositoryTags(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("RandomFilter", func(t *testing.T) {
+		testRepositoryRandomFilter(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("DailyPins", func(t *testing.T) {
+		testRepositoryDailyPins(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		testRepositoryCanceledContext(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("CreateBatch", func(t *testing.T) {
+		testRepositoryCreateBatch(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		testRepositoryIterate(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		testRepositoryDuplicates(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		testRepositoryAuthors(t, repository.NewInMemoryRepository())
+	})
+
+	t.Run("AuthorMatch", func(t *testing.T) {
+		testRepositoryAuthorMatch(t, repository.NewInMemoryRepository())
+	})
+}
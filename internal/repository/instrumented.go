@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// Observer receives the duration and result of one repository operation,
+// named after the QuoteRepository method.
+type Observer func(operation string, took time.Duration, err error)
+
+// InstrumentedRepository reports every call to the repository it wraps to an
+// Observer, for metrics.
+type InstrumentedRepository struct {
+	repo    QuoteRepository
+	observe Observer
+}
+
+func NewInstrumentedRepository(repo QuoteRepository, observe Observer) *InstrumentedRepository {
+	return &InstrumentedRepository{repo: repo, observe: observe}
+}
+
+func (r *InstrumentedRepository) CheckHealth(ctx context.Context) error {
+	return CheckHealth(ctx, r.repo)
+}
+
+// track reports the operation when the returned function is called with its
+// result.
+func (r *InstrumentedRepository) track(operation string) func(err error) {
+	start := time.Now()
+	return func(err error) {
+		r.observe(operation, time.Since(start), err)
+	}
+}
+
+func (r *InstrumentedRepository) Create(ctx context.Context, quote *domain.Quote) error {
+	done := r.track("Create")
+	err := r.repo.Create(ctx, quote)
+	done(err)
+	return err
+}
+
+func (r *InstrumentedRepository) CreateBatch(ctx context.Context, quotes []*domain.Quote, atomic bool) ([]error, error) {
+	done := r.track("CreateBatch")
+	rowErrs, err := r.repo.CreateBatch(ctx, quotes, atomic)
+	done(err)
+	return rowErrs, err
+}
+
+func (r *InstrumentedRepository) GetAll(ctx context.Context) ([]domain.Quote, error) {
+	done := r.track("GetAll")
+	quotes, err := r.repo.GetAll(ctx)
+	done(err)
+	return quotes, err
+}
+
+func (r *InstrumentedRepository) Count(ctx context.Context) (int, error) {
+	done := r.track("Count")
+	count, err := r.repo.Count(ctx)
+	done(err)
+	return count, err
+}
+
+func (r *InstrumentedRepository) GetByID(ctx context.Context, id string) (*domain.Quote, error) {
+	done := r.track("GetByID")
+	quote, err := r.repo.GetByID(ctx, id)
+	done(err)
+	return quote, err
+}
+
+func (r *InstrumentedRepository) GetByAuthor(ctx context.Context, author string) ([]domain.Quote, error) {
+	done := r.track("GetByAuthor")
+	quotes, err := r.repo.GetByAuthor(ctx, author)
+	done(err)
+	return quotes, err
+}
+
+func (r *InstrumentedRepository) Update(ctx context.Context, quote *domain.Quote) error {
+	done := r.track("Update")
+	err := r.repo.Update(ctx, quote)
+	done(err)
+	return err
+}
+
+func (r *InstrumentedRepository) Delete(ctx context.Context, id string, version int64) error {
+	done := r.track("Delete")
+	err := r.repo.Delete(ctx, id, version)
+	done(err)
+	return err
+}
+
+func (r *InstrumentedRepository) GetRandom(ctx context.Context, filter domain.QuoteFilter) (*domain.Quote, error) {
+	done := r.track("GetRandom")
+	quote, err := r.repo.GetRandom(ctx, filter)
+	done(err)
+	return quote, err
+}
+
+func (r *InstrumentedRepository) Search(ctx context.Context, query string, limit int) ([]domain.SearchResult, error) {
+	done := r.track("Search")
+	results, err := r.repo.Search(ctx, query, limit)
+	done(err)
+	return results, err
+}
+
+func (r *InstrumentedRepository) List(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error) {
+	done := r.track("List")
+	page, err := r.repo.List(ctx, query)
+	done(err)
+	return page, err
+}
+
+// Iterate is timed from the start of the iteration until it ends, including
+// the time the caller spends between quotes.
+func (r *InstrumentedRepository) Iterate(ctx context.Context, filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+	return func(yield func(domain.Quote, error) bool) {
+		done := r.track("Iterate")
+		var last error
+		for quote, err := range r.repo.Iterate(ctx, filter) {
+			last = err
+			if !yield(quote, err) {
+				break
+			}
+		}
+		done(last)
+	}
+}
+
+func (r *InstrumentedRepository) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	done := r.track("ListTags")
+	tags, err := r.repo.ListTags(ctx)
+	done(err)
+	return tags, err
+}
+
+func (r *InstrumentedRepository) GetDailyPin(ctx context.Context, date string) (string, error) {
+	done := r.track("GetDailyPin")
+	quoteID, err := r.repo.GetDailyPin(ctx, date)
+	done(err)
+	return quoteID, err
+}
+
+func (r *InstrumentedRepository) SetDailyPin(ctx context.Context, date, quoteID string) error {
+	done := r.track("SetDailyPin")
+	err := r.repo.SetDailyPin(ctx, date, quoteID)
+	done(err)
+	return err
+}
+
+func (r *InstrumentedRepository) DeleteDailyPin(ctx context.Context, date string) error {
+	done := r.track("DeleteDailyPin")
+	err := r.repo.DeleteDailyPin(ctx, date)
+	done(err)
+	return err
+}
+
+func (r *InstrumentedRepository) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	done := r.track("CreateAuthor")
+	err := r.repo.CreateAuthor(ctx, author)
+	done(err)
+	return err
+}
+
+func (r *InstrumentedRepository) GetAuthor(ctx context.Context, id string) (*domain.Author, error) {
+	done := r.track("GetAuthor")
+	author, err := r.repo.GetAuthor(ctx, id)
+	done(err)
+	return author, err
+}
+
+func (r *InstrumentedRepository) ListAuthors(ctx context.Context) ([]domain.Author, error) {
+	done := r.track("ListAuthors")
+	authors, err := r.repo.ListAuthors(ctx)
+	done(err)
+	return authors, err
+}
+
+func (r *InstrumentedRepository) MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error) {
+	done := r.track("MergeAuthors")
+	author, err := r.repo.MergeAuthors(ctx, targetID, sourceID)
+	done(err)
+	return author, err
+}
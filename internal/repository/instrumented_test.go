@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/repository"
+)
+
+func newTestInstrumentedRepository() *repository.InstrumentedRepository {
+	return repository.NewInstrumentedRepository(repository.NewInMemoryRepository(), func(string, time.Duration, error) {})
+}
+
+func TestInstrumentedRepository(t *testing.T) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		testRepositoryCreateAndGet(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		testRepositoryCreateDuplicate(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		testRepositoryGetAll(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("GetByAuthor", func(t *testing.T) {
+		testRepositoryGetByAuthor(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		testRepositoryDelete(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("GetRandom", func(t *testing.T) {
+		testRepositoryGetRandom(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		testRepositorySearch(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("List", func(t *testing.T) {
+		testRepositoryList(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		testRepositoryUpdate(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("Versioning", func(t *testing.T) {
+		testRepositoryVersioning(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("AuditMetadata", func(t *testing.T) {
+		testRepositoryAuditMetadata(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		testRepositoryTags(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("RandomFilter", func(t *testing.T) {
+		testRepositoryRandomFilter(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("DailyPins", func(t *testing.T) {
+		testRepositoryDailyPins(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		testRepositoryCanceledContext(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("CreateBatch", func(t *testing.T) {
+		testRepositoryCreateBatch(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		testRepositoryIterate(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		testRepositoryDuplicates(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		testRepositoryAuthors(t, newTestInstrumentedRepository())
+	})
+
+	t.Run("AuthorMatch", func(t *testing.T) {
+		testRepositoryAuthorMatch(t, newTestInstrumentedRepository())
+	})
+}
+
+func TestInstrumentedRepository_Observer(t *testing.T) {
+	var operations []string
+	var failed []string
+	repo := repository.NewInstrumentedRepository(repository.NewInMemoryRepository(), func(operation string, took time.Duration, err error) {
+		if took < 0 {
+			t.Errorf("Negative duration for %s: %v", operation, took)
+		}
+		operations = append(operations, operation)
+		if err != nil {
+			failed = append(failed, operation)
+		}
+	})
+
+	repo.Create(t.Context(), &domain.Quote{ID: "observed-1", Text: "Observed", Author: "Observer"})
+	if _, err := repo.GetByID(t.Context(), "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	for range repo.Iterate(t.Context(), domain.QuoteFilter{}) {
+		break
+	}
+
+	if expected := []string{"Create", "GetByID", "Iterate"}; !slices.Equal(operations, expected) {
+		t.Errorf("Expected operations %v, got %v", expected, operations)
+	}
+	if expected := []string{"GetByID"}; !slices.Equal(failed, expected) {
+		t.Errorf("Expected failed operations %v, got %v", expected, failed)
+	}
+}
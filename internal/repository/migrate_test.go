@@ -0,0 +1,149 @@
+package repository_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/repository"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_second.up.sql":   {Data: []byte("SELECT 2;")},
+		"m/0001_first.up.sql":    {Data: []byte("SELECT 1;")},
+		"m/0001_first.down.sql":  {Data: []byte("SELECT -1;")},
+		"m/README.md":            {Data: []byte("ignored")},
+		"bad/0001_only.down.sql": {Data: []byte("SELECT -1;")},
+	}
+
+	migrations, err := repository.LoadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatalf("LoadMigrations failed: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "first" || migrations[0].Down != "SELECT -1;" {
+		t.Errorf("Unexpected first migration: %+v", migrations[0])
+	}
+	if migrations[1].Version != 2 || migrations[1].Down != "" {
+		t.Errorf("Unexpected second migration: %+v", migrations[1])
+	}
+
+	if _, err := repository.LoadMigrations(fsys, "bad"); err == nil {
+		t.Error("LoadMigrations accepted a migration without an up script")
+	}
+}
+
+func TestSQLiteMigrator(t *testing.T) {
+	db, err := repository.OpenSQLiteDB(filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLiteDB failed: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := repository.NewSQLiteMigrator(db)
+	if err != nil {
+		t.Fatalf("NewSQLiteMigrator failed: %v", err)
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if applied == 0 || applied != len(statuses) {
+		t.Errorf("Expected all %d migrations to be applied, got %d", len(statuses), applied)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt.IsZero() {
+			t.Errorf("Migration %d is not recorded as applied: %+v", status.Version, status)
+		}
+	}
+
+	applied, err = migrator.Up()
+	if err != nil || applied != 0 {
+		t.Errorf("Second Up should be a no-op, applied %d, err %v", applied, err)
+	}
+
+	rolledBack, err := migrator.Down(1)
+	if err != nil || rolledBack != 1 {
+		t.Fatalf("Down(1) rolled back %d, err %v", rolledBack, err)
+	}
+	statuses, _ = migrator.Status()
+	if last := statuses[len(statuses)-1]; last.Applied {
+		t.Errorf("Latest migration is still applied after Down: %+v", last)
+	}
+
+	rolledBack, err = migrator.Down(len(statuses) + 5)
+	if err != nil || rolledBack != len(statuses)-1 {
+		t.Fatalf("Down(all) rolled back %d, err %v", rolledBack, err)
+	}
+	var tables int
+	db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'quotes'").Scan(&tables)
+	if tables != 0 {
+		t.Error("quotes table still exists after rolling back every migration")
+	}
+
+	applied, err = migrator.Up()
+	if err != nil || applied != len(statuses) {
+		t.Errorf("Re-applying migrations applied %d, err %v", applied, err)
+	}
+}
+
+func TestSQLiteRepository_UpgradesLegacyDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := repository.OpenSQLiteDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenSQLiteDB failed: %v", err)
+	}
+	_, err = db.Exec(`
+	CREATE TABLE quotes (id TEXT PRIMARY KEY, text TEXT NOT NULL, author TEXT NOT NULL);
+	INSERT INTO quotes (id, text, author) VALUES ('legacy-1', 'Legacy text', 'Legacy author');
+	INSERT INTO quotes (id, text, author) VALUES ('legacy-2', 'LEGACY text!', 'legacy author');`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	repo, err := repository.NewSQLiteRepository(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository failed on legacy database: %v", err)
+	}
+	defer repo.Close()
+
+	quote, err := repo.GetByID(t.Context(), "legacy-1")
+	if err != nil {
+		t.Fatalf("GetByID failed after upgrade: %v", err)
+	}
+	if quote.Text != "Legacy text" || quote.Version != 1 || quote.CreatedAt.IsZero() {
+		t.Errorf("Legacy quote was not upgraded correctly: %+v", quote)
+	}
+
+	// Duplicates stored before fingerprinting are kept, but new ones are refused.
+	legacyDuplicate, err := repo.GetByID(t.Context(), "legacy-2")
+	if err != nil {
+		t.Errorf("Legacy duplicate was dropped during upgrade: %v", err)
+	} else if legacyDuplicate.AuthorID == "" || legacyDuplicate.AuthorID != quote.AuthorID || legacyDuplicate.Author != "Legacy author" {
+		t.Errorf("Expected legacy quotes to share one author, got %+v and %+v", quote, legacyDuplicate)
+	}
+	err = repo.Create(t.Context(), &domain.Quote{ID: "new-1", Text: "Legacy  text", Author: "Legacy Author"})
+	var duplicate *repository.DuplicateError
+	if !errors.As(err, &duplicate) || duplicate.ExistingID != "legacy-1" {
+		t.Errorf("Expected duplicate of legacy-1, got %v", err)
+	}
+
+	results, err := repo.Search(t.Context(), "legacy", 10)
+	if err != nil {
+		t.Fatalf("Search failed after upgrade: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Legacy quote is not searchable after upgrade: %+v", results)
+	}
+}
@@ -0,0 +1,7 @@
+DROP FUNCTION IF EXISTS edit_distance(TEXT, TEXT);
+DROP TABLE IF EXISTS daily_pins;
+DROP TABLE IF EXISTS quote_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS quotes;
+DROP TABLE IF EXISTS author_names;
+DROP TABLE IF EXISTS authors;
@@ -0,0 +1,80 @@
+-- Timestamps are fixed-width UTC text as in SQLite: that keeps nanosecond
+-- precision and lets page cursors compare them as strings. Columns that are
+-- sorted or paged on use the "C" collation, so that rows are ordered bytewise
+-- like in the other repositories whatever the database locale.
+CREATE TABLE IF NOT EXISTS authors (
+	id TEXT COLLATE "C" PRIMARY KEY,
+	name TEXT COLLATE "C" NOT NULL,
+	bio TEXT NOT NULL DEFAULT '',
+	created_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS author_names (
+	name_key TEXT COLLATE "C" PRIMARY KEY,
+	author_id TEXT NOT NULL REFERENCES authors (id) ON DELETE CASCADE,
+	name TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_author_names_author ON author_names (author_id);
+
+CREATE TABLE IF NOT EXISTS quotes (
+	id TEXT COLLATE "C" PRIMARY KEY,
+	text TEXT NOT NULL,
+	author TEXT COLLATE "C" NOT NULL,
+	author_id TEXT REFERENCES authors (id),
+	version BIGINT NOT NULL DEFAULT 1,
+	created_at TEXT COLLATE "C" NOT NULL,
+	updated_at TEXT NOT NULL,
+	created_by TEXT NOT NULL DEFAULT '',
+	fingerprint TEXT,
+	-- The 'simple' configuration lowercases without stemming, which suits
+	-- quotes in any language and matches the in-memory index.
+	search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', text || ' ' || author)) STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_quotes_author_id ON quotes (author, id);
+CREATE INDEX IF NOT EXISTS idx_quotes_created_id ON quotes (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_quotes_author_id_id ON quotes (author_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quotes_fingerprint ON quotes (fingerprint);
+CREATE INDEX IF NOT EXISTS idx_quotes_search ON quotes USING GIN (search_vector);
+
+CREATE TABLE IF NOT EXISTS tags (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	name TEXT COLLATE "C" NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS quote_tags (
+	quote_id TEXT NOT NULL REFERENCES quotes (id) ON DELETE CASCADE,
+	tag_id BIGINT NOT NULL REFERENCES tags (id),
+	PRIMARY KEY (quote_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_tags_tag ON quote_tags (tag_id, quote_id);
+
+CREATE TABLE IF NOT EXISTS daily_pins (
+	date TEXT PRIMARY KEY,
+	quote_id TEXT NOT NULL,
+	pinned_at TEXT NOT NULL
+);
+
+-- Levenshtein distance over characters, the same as domain.EditDistance, for
+-- fuzzy author matching. The fuzzystrmatch extension is not used because
+-- creating it needs privileges the service may not have.
+CREATE OR REPLACE FUNCTION edit_distance(a TEXT, b TEXT) RETURNS INTEGER AS $$
+DECLARE
+	prev_row INTEGER[];
+	cur_row INTEGER[];
+	cost INTEGER;
+BEGIN
+	prev_row := ARRAY(SELECT generate_series(0, length(b)));
+	FOR i IN 1..length(a) LOOP
+		cur_row := ARRAY[i];
+		FOR j IN 1..length(b) LOOP
+			cost := CASE WHEN substr(a, i, 1) = substr(b, j, 1) THEN 0 ELSE 1 END;
+			cur_row := cur_row || LEAST(prev_row[j + 1] + 1, cur_row[j] + 1, prev_row[j] + cost);
+		END LOOP;
+		prev_row := cur_row;
+	END LOOP;
+	RETURN prev_row[length(b) + 1];
+END;
+$$ LANGUAGE plpgsql IMMUTABLE STRICT;
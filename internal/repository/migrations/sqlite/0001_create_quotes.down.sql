@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS quotes_fts;
+DROP INDEX IF EXISTS idx_quotes_author_id;
+DROP TABLE IF EXISTS quotes;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS quotes (
+	id TEXT PRIMARY KEY,
+	text TEXT NOT NULL,
+	author TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_quotes_author_id ON quotes (author, id);
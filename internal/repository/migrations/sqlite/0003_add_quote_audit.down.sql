@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_quotes_created_id;
+ALTER TABLE quotes DROP COLUMN created_by;
+ALTER TABLE quotes DROP COLUMN updated_at;
+ALTER TABLE quotes DROP COLUMN created_at;
@@ -0,0 +1,12 @@
+ALTER TABLE quotes ADD COLUMN created_at TEXT NOT NULL DEFAULT '';
+ALTER TABLE quotes ADD COLUMN updated_at TEXT NOT NULL DEFAULT '';
+ALTER TABLE quotes ADD COLUMN created_by TEXT NOT NULL DEFAULT '';
+
+-- Existing quotes have no recorded creation time, so they are stamped with the
+-- migration time. The rowid goes into the fractional seconds to keep their
+-- relative insertion order when sorting by creation time.
+UPDATE quotes
+SET created_at = printf('%s.%09dZ', strftime('%Y-%m-%dT%H:%M:%S', 'now'), rowid % 1000000000),
+    updated_at = printf('%s.%09dZ', strftime('%Y-%m-%dT%H:%M:%S', 'now'), rowid % 1000000000);
+
+CREATE INDEX IF NOT EXISTS idx_quotes_created_id ON quotes (created_at, id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_quote_tags_tag;
+DROP TABLE IF EXISTS quote_tags;
+DROP TABLE IF EXISTS tags;
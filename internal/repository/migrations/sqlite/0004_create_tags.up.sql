@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS tags (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS quote_tags (
+	quote_id TEXT NOT NULL REFERENCES quotes (id) ON DELETE CASCADE,
+	tag_id INTEGER NOT NULL REFERENCES tags (id),
+	PRIMARY KEY (quote_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_tags_tag ON quote_tags (tag_id, quote_id);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS daily_pins (
+	date TEXT PRIMARY KEY,
+	quote_id TEXT NOT NULL,
+	pinned_at TEXT NOT NULL
+);
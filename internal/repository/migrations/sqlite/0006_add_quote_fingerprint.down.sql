@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_quotes_fingerprint;
+ALTER TABLE quotes DROP COLUMN fingerprint;
@@ -0,0 +1,6 @@
+-- The fingerprint needs Unicode normalization that SQLite cannot do, so it is
+-- filled in by the application on startup. Until then it is NULL, which the
+-- unique index allows any number of times.
+ALTER TABLE quotes ADD COLUMN fingerprint TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quotes_fingerprint ON quotes (fingerprint);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_quotes_author_id_id;
+ALTER TABLE quotes DROP COLUMN author_id;
+DROP TABLE IF EXISTS author_names;
+DROP TABLE IF EXISTS authors;
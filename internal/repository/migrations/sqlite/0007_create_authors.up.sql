@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS authors (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	bio TEXT NOT NULL DEFAULT '',
+	created_at TEXT NOT NULL
+);
+
+-- Every name an author goes by, the canonical one included, keyed by its
+-- normalized form. Like fingerprints the key is computed by the application,
+-- which also links existing quotes to authors on startup.
+CREATE TABLE IF NOT EXISTS author_names (
+	name_key TEXT PRIMARY KEY,
+	author_id TEXT NOT NULL REFERENCES authors (id) ON DELETE CASCADE,
+	name TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_author_names_author ON author_names (author_id);
+
+ALTER TABLE quotes ADD COLUMN author_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_quotes_author_id_id ON quotes (author_id, id);
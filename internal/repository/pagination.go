@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"iter"
+	"sort"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// pageCursor points just past the last row of a page. It records the sort it
+// was issued for so that a cursor cannot be replayed against another ordering.
+type pageCursor struct {
+	SortBy     domain.SortField `json:"s"`
+	Descending bool             `json:"d,omitempty"`
+	Key        string           `json:"k"`
+	ID         string           `json:"i"`
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string, query domain.ListQuery) (*pageCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.SortBy != query.SortBy || c.Descending != query.Descending {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// iteratePageSize is how many quotes an iterator fetches at a time.
+const iteratePageSize = 500
+
+// iteratePages yields every quote matching filter in ID order, one List page
+// at a time. Nothing is held between pages, neither a lock nor an open result
+// set, so a slow consumer never blocks writers.
+func iteratePages(ctx context.Context, list func(context.Context, domain.ListQuery) (*domain.QuotePage, error), filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+	return func(yield func(domain.Quote, error) bool) {
+		query := domain.ListQuery{QuoteFilter: filter, SortBy: domain.SortByID, Limit: iteratePageSize}
+		for {
+			page, err := list(ctx, query)
+			if err != nil {
+				yield(domain.Quote{}, err)
+				return
+			}
+			for _, quote := range page.Quotes {
+				if !yield(quote, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			query.Cursor = page.NextCursor
+		}
+	}
+}
+
+type indexEntry struct {
+	key string
+	id  string
+}
+
+func (e indexEntry) less(other indexEntry) bool {
+	if e.key != other.key {
+		return e.key < other.key
+	}
+	return e.id < other.id
+}
+
+// sortedIndex keeps quote IDs ordered by (key, id) so pages can be served by
+// binary-searching the cursor position instead of sorting on every request.
+type sortedIndex struct {
+	entries []indexEntry
+}
+
+func (idx *sortedIndex) position(entry indexEntry) int {
+	return sort.Search(len(idx.entries), func(i int) bool {
+		return !idx.entries[i].less(entry)
+	})
+}
+
+func (idx *sortedIndex) insert(entry indexEntry) {
+	i := idx.position(entry)
+	idx.entries = append(idx.entries, indexEntry{})
+	copy(idx.entries[i+1:], idx.entries[i:])
+	idx.entries[i] = entry
+}
+
+func (idx *sortedIndex) remove(entry indexEntry) {
+	i := idx.position(entry)
+	if i < len(idx.entries) && idx.entries[i] == entry {
+		idx.entries = append(idx.entries[:i], idx.entries[i+1:]...)
+	}
+}
+
+// walk calls fn for entries strictly after the cursor in the requested
+// direction until fn returns false.
+func (idx *sortedIndex) walk(after *indexEntry, descending bool, fn func(indexEntry) bool) {
+	if !descending {
+		start := 0
+		if after != nil {
+			start = idx.position(*after)
+			if start < len(idx.entries) && idx.entries[start] == *after {
+				start++
+			}
+		}
+		for i := start; i < len(idx.entries); i++ {
+			if !fn(idx.entries[i]) {
+				return
+			}
+		}
+		return
+	}
+
+	start := len(idx.entries) - 1
+	if after != nil {
+		start = idx.position(*after) - 1
+	}
+	for i := start; i >= 0; i-- {
+		if !fn(idx.entries[i]) {
+			return
+		}
+	}
+}
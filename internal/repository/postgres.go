@@ -0,0 +1,614 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// PostgresRepository stores quotes in PostgreSQL, so several instances of the
+// service can share one database. Full-text search uses a generated tsvector
+// column; everything else mirrors the SQLite repository, including the SQL of
+// the filters, which is written with ? placeholders and passed through rebind.
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+type PostgresPoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+func OpenPostgresDB(dsn string, pool PostgresPoolOptions) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return db, nil
+}
+
+func NewPostgresRepository(dsn string, pool PostgresPoolOptions) (*PostgresRepository, error) {
+	if err := migratePostgres(dsn); err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	db, err := OpenPostgresDB(dsn, pool)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresRepository{db: db}, nil
+}
+
+// postgresMigrationLock is the advisory lock key that serializes migrations
+// of instances starting at the same time.
+const postgresMigrationLock = 7_106_117_111_116_101
+
+// migratePostgres applies pending migrations over a single connection, which
+// holds the advisory lock for the whole run.
+func migratePostgres(dsn string) error {
+	db, err := OpenPostgresDB(dsn, PostgresPoolOptions{MaxOpenConns: 1})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("SELECT pg_advisory_lock($1)", postgresMigrationLock); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer db.Exec("SELECT pg_advisory_unlock($1)", postgresMigrationLock)
+
+	migrator, err := NewPostgresMigrator(db)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up()
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		log.Printf("Applied %d database migration(s)", applied)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *PostgresRepository) CheckHealth(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// rebind rewrites ? placeholders into Postgres' numbered $n form. The queries
+// it is used for contain no other question marks.
+func rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c != '?' {
+			b.WriteRune(c)
+			continue
+		}
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// pgUniqueViolation reports whether err broke the unique constraint or index
+// named constraint.
+func pgUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
+// withSavepoint runs fn so that a failed statement in it can be handled
+// without losing the transaction, which Postgres aborts on any error.
+func withSavepoint(ctx context.Context, tx *sql.Tx, fn func() error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT statement"); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	if err := fn(); err != nil {
+		// Savepoints may nest under the same name, so a rolled back one is
+		// released too for the enclosing one to be found next.
+		if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT statement; RELEASE SAVEPOINT statement"); rollbackErr != nil {
+			return fmt.Errorf("failed to roll back to savepoint: %w", rollbackErr)
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT statement"); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// pgQuoteColumns is quoteColumns in Postgres' dialect.
+const pgQuoteColumns = `q.id, q.text, q.author, COALESCE(q.author_id, ''), q.version, q.created_at, q.updated_at, q.created_by,
+	(SELECT string_agg(t.name, chr(31)) FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id WHERE qt.quote_id = q.id)`
+
+func (r *PostgresRepository) queryQuotes(ctx context.Context, query string, args ...any) ([]domain.Quote, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []domain.Quote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote row: %w", err)
+		}
+		quotes = append(quotes, quote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// withTx runs fn in a transaction, committing only if fn succeeds.
+func (r *PostgresRepository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func pgSetQuoteTags(ctx context.Context, tx *sql.Tx, quoteID string, tags []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM quote_tags WHERE quote_id = $1", quoteID); err != nil {
+		return fmt.Errorf("failed to clear quote tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO tags (name) VALUES ($1) ON CONFLICT (name) DO NOTHING", tag); err != nil {
+			return fmt.Errorf("failed to create tag: %w", err)
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO quote_tags (quote_id, tag_id) SELECT $1, id FROM tags WHERE name = $2", quoteID, tag)
+		if err != nil {
+			return fmt.Errorf("failed to tag quote: %w", err)
+		}
+	}
+	return nil
+}
+
+func pgDuplicateOf(ctx context.Context, tx *sql.Tx, fingerprint string) error {
+	var id string
+	err := tx.QueryRowContext(ctx, "SELECT id FROM quotes WHERE fingerprint = $1", fingerprint).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to find duplicate quote: %w", err)
+	}
+	return &DuplicateError{ExistingID: id, Similarity: 1}
+}
+
+func pgInsertQuote(ctx context.Context, tx *sql.Tx, quote *domain.Quote) error {
+	stampCreated(quote)
+	quote.Tags = domain.NormalizeTags(quote.Tags)
+	if err := pgResolveAuthor(ctx, tx, quote); err != nil {
+		return err
+	}
+	fingerprint := domain.Fingerprint(quote.Text, quote.Author)
+	query := `
+	INSERT INTO quotes (id, text, author, author_id, version, created_at, updated_at, created_by, fingerprint)
+	VALUES ($1, $2, $3, $4, 1, $5, $6, $7, $8)`
+	err := withSavepoint(ctx, tx, func() error {
+		_, err := tx.ExecContext(ctx, query, quote.ID, quote.Text, quote.Author, quote.AuthorID,
+			formatTimestamp(quote.CreatedAt), formatTimestamp(quote.UpdatedAt), quote.CreatedBy, fingerprint)
+		return err
+	})
+	if err != nil {
+		if pgUniqueViolation(err, "quotes_pkey") {
+			return ErrAlreadyExists
+		}
+		if pgUniqueViolation(err, "idx_quotes_fingerprint") {
+			return pgDuplicateOf(ctx, tx, fingerprint)
+		}
+		return fmt.Errorf("failed to create quote: %w", err)
+	}
+	return pgSetQuoteTags(ctx, tx, quote.ID, quote.Tags)
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, quote *domain.Quote) error {
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		return pgInsertQuote(ctx, tx, quote)
+	})
+	if err != nil {
+		return err
+	}
+	quote.Version = 1
+	return nil
+}
+
+// CreateBatch runs the whole batch in one transaction like the SQLite
+// repository. Every quote is inserted under a savepoint anyway, so a failed one
+// is undone on its own in best-effort mode.
+func (r *PostgresRepository) CreateBatch(ctx context.Context, quotes []*domain.Quote, atomic bool) ([]error, error) {
+	rowErrs := make([]error, len(quotes))
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		failed := false
+		for i, quote := range quotes {
+			err := withSavepoint(ctx, tx, func() error {
+				return pgInsertQuote(ctx, tx, quote)
+			})
+			if err != nil && !errors.Is(err, ErrAlreadyExists) && !errors.Is(err, ErrDuplicate) {
+				return err
+			}
+			if err != nil {
+				rowErrs[i] = err
+				failed = true
+			}
+		}
+		if atomic && failed {
+			return errBatchRejected
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRejected) {
+		return nil, err
+	}
+	if err == nil {
+		for i, quote := range quotes {
+			if rowErrs[i] == nil {
+				quote.Version = 1
+			}
+		}
+	}
+	return rowErrs, nil
+}
+
+func (r *PostgresRepository) GetAll(ctx context.Context) ([]domain.Quote, error) {
+	quotes, err := r.queryQuotes(ctx, "SELECT "+pgQuoteColumns+" FROM quotes q")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all quotes: %w", err)
+	}
+	return quotes, nil
+}
+
+func (r *PostgresRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM quotes").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count quotes: %w", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Quote, error) {
+	query := "SELECT " + pgQuoteColumns + " FROM quotes q WHERE q.id = $1"
+	quote, err := scanQuote(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get quote by ID: %w", err)
+	}
+
+	return &quote, nil
+}
+
+func (r *PostgresRepository) GetByAuthor(ctx context.Context, author string) ([]domain.Quote, error) {
+	quotes, err := r.queryQuotes(ctx, "SELECT "+pgQuoteColumns+" FROM quotes q WHERE q.author = $1", author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotes by author: %w", err)
+	}
+	return quotes, nil
+}
+
+// Update stores the quote and bumps its version under the same rules as the
+// SQLite repository.
+func (r *PostgresRepository) Update(ctx context.Context, quote *domain.Quote) error {
+	stampUpdated(quote)
+	quote.Tags = domain.NormalizeTags(quote.Tags)
+	var version int64
+	var createdAt, createdBy string
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := pgResolveAuthor(ctx, tx, quote); err != nil {
+			return err
+		}
+		fingerprint := domain.Fingerprint(quote.Text, quote.Author)
+		query := `
+		UPDATE quotes SET text = $1, author = $2, author_id = $3, fingerprint = $4, updated_at = $5, version = version + 1
+		WHERE id = $6 AND ($7::bigint = 0 OR version = $7)
+		RETURNING version, created_at, created_by`
+		err := withSavepoint(ctx, tx, func() error {
+			return tx.QueryRowContext(ctx, query, quote.Text, quote.Author, quote.AuthorID, fingerprint, formatTimestamp(quote.UpdatedAt),
+				quote.ID, quote.Version).Scan(&version, &createdAt, &createdBy)
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pgMissingOrConflict(ctx, tx, quote.ID)
+			}
+			if pgUniqueViolation(err, "idx_quotes_fingerprint") {
+				return pgDuplicateOf(ctx, tx, fingerprint)
+			}
+			return fmt.Errorf("failed to update quote: %w", err)
+		}
+		return pgSetQuoteTags(ctx, tx, quote.ID, quote.Tags)
+	})
+	if err != nil {
+		return err
+	}
+
+	quote.Version = version
+	quote.CreatedBy = createdBy
+	if quote.CreatedAt, err = time.Parse(timestampLayout, createdAt); err != nil {
+		return fmt.Errorf("invalid created_at for quote %s: %w", quote.ID, err)
+	}
+	return nil
+}
+
+// Delete relies on ON DELETE CASCADE, which Postgres enforces, to drop the
+// quote's tags.
+func (r *PostgresRepository) Delete(ctx context.Context, id string, version int64) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM quotes WHERE id = $1 AND ($2::bigint = 0 OR version = $2)", id, version)
+		if err != nil {
+			return fmt.Errorf("failed to delete quote: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return pgMissingOrConflict(ctx, tx, id)
+		}
+		return nil
+	})
+}
+
+func pgMissingOrConflict(ctx context.Context, tx *sql.Tx, id string) error {
+	var exists int
+	err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM quotes WHERE id = $1", id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check quote existence: %w", err)
+	}
+	if exists == 0 {
+		return ErrNotFound
+	}
+	return ErrVersionConflict
+}
+
+func (r *PostgresRepository) GetRandom(ctx context.Context, filter domain.QuoteFilter) (*domain.Quote, error) {
+	query := "SELECT " + pgQuoteColumns + " FROM quotes q"
+	conditions, args := filterConditions(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY random() LIMIT 1"
+	quote, err := scanQuote(r.db.QueryRowContext(ctx, rebind(query), args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoQuotes
+		}
+		return nil, fmt.Errorf("failed to get random quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// pgHeadlineOptions makes ts_headline mark matches for highlightSnippet.
+var pgHeadlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=%d, MinWords=%d",
+	snippetOpen, snippetClose, snippetTokens, snippetTokens/2)
+
+// Search requires every term, like the other repositories, and ranks with
+// ts_rank, which like BM25 favours quotes that repeat the terms.
+func (r *PostgresRepository) Search(ctx context.Context, query string, limit int) ([]domain.SearchResult, error) {
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	sqlQuery := `
+	SELECT ` + pgQuoteColumns + `,
+		ts_rank(q.search_vector, query) AS score,
+		ts_headline('simple', q.text, query, $1)
+	FROM quotes q, plainto_tsquery('simple', $2) query
+	WHERE q.search_vector @@ query
+	ORDER BY score DESC, q.id`
+	args := []any{pgHeadlineOptions, strings.Join(terms, " ")}
+	if limit > 0 {
+		sqlQuery += " LIMIT $3"
+		args = append(args, limit)
+	}
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.SearchResult
+	for rows.Next() {
+		var result domain.SearchResult
+		result.Quote, err = scanQuote(rows, &result.Score, &result.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result row: %w", err)
+		}
+		result.Snippet = highlightSnippet(result.Snippet)
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return results, nil
+}
+
+// List pages with a keyset condition on (sort column, id), which the
+// (author, id) and (created_at, id) indexes serve directly.
+func (r *PostgresRepository) List(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error) {
+	cursor, err := decodeCursor(query.Cursor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	column, ok := sqliteSortColumns[query.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort field: %s", query.SortBy)
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions, args := filterConditions(query.QuoteFilter)
+	if cursor != nil {
+		conditions = append(conditions, fmt.Sprintf("(q.%s, q.id) %s (?, ?)", column, comparison))
+		args = append(args, cursor.Key, cursor.ID)
+	}
+
+	sqlQuery := fmt.Sprintf("SELECT %s, q.%s FROM quotes q", pgQuoteColumns, column)
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY q.%s %s, q.id %s LIMIT ?", column, direction, direction)
+	args = append(args, query.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, rebind(sqlQuery), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotes: %w", err)
+	}
+	defer rows.Close()
+
+	page := &domain.QuotePage{Quotes: []domain.Quote{}}
+	var lastKey string
+	for rows.Next() {
+		var key string
+		quote, err := scanQuote(rows, &key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote row: %w", err)
+		}
+		if len(page.Quotes) == query.Limit {
+			last := page.Quotes[len(page.Quotes)-1]
+			page.NextCursor = encodeCursor(pageCursor{
+				SortBy:     query.SortBy,
+				Descending: query.Descending,
+				Key:        lastKey,
+				ID:         last.ID,
+			})
+			break
+		}
+		page.Quotes = append(page.Quotes, quote)
+		lastKey = key
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return page, nil
+}
+
+func (r *PostgresRepository) Iterate(ctx context.Context, filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+	return iteratePages(ctx, r.List, filter)
+}
+
+func (r *PostgresRepository) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT t.name, COUNT(*) AS uses
+	FROM quote_tags qt
+	JOIN tags t ON t.id = qt.tag_id
+	GROUP BY t.id
+	ORDER BY uses DESC, t.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.TagCount{}
+	for rows.Next() {
+		var count domain.TagCount
+		if err := rows.Scan(&count.Tag, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *PostgresRepository) GetDailyPin(ctx context.Context, date string) (string, error) {
+	var quoteID string
+	err := r.db.QueryRowContext(ctx, "SELECT quote_id FROM daily_pins WHERE date = $1", date).Scan(&quoteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get daily pin: %w", err)
+	}
+	return quoteID, nil
+}
+
+// SetDailyPin pins quoteID to date, replacing any earlier pin for that date.
+func (r *PostgresRepository) SetDailyPin(ctx context.Context, date, quoteID string) error {
+	query := `
+	INSERT INTO daily_pins (date, quote_id, pinned_at)
+	SELECT $1, id, $2 FROM quotes WHERE id = $3
+	ON CONFLICT (date) DO UPDATE SET quote_id = excluded.quote_id, pinned_at = excluded.pinned_at`
+	result, err := r.db.ExecContext(ctx, query, date, formatTimestamp(time.Now()), quoteID)
+	if err != nil {
+		return fmt.Errorf("failed to set daily pin: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteDailyPin(ctx context.Context, date string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM daily_pins WHERE date = $1", date)
+	if err != nil {
+		return fmt.Errorf("failed to delete daily pin: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// pgResolveAuthor is resolveAuthor for Postgres. Another instance may add the
+// same name between the lookup and the insert; the insert then waits for it
+// and finds the name taken, and the quote goes to that instance's author.
+func pgResolveAuthor(ctx context.Context, tx *sql.Tx, quote *domain.Quote) error {
+	query := `
+	SELECT a.id, a.name FROM author_names n JOIN authors a ON a.id = n.author_id
+	WHERE n.name_key = $1`
+	key := domain.AuthorKey(quote.Author)
+	err := tx.QueryRowContext(ctx, query, key).Scan(&quote.AuthorID, &quote.Author)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to resolve author: %w", err)
+	}
+
+	author := &domain.Author{ID: authorIDs.NewID(), Name: quote.Author, CreatedAt: time.Now().UTC()}
+	err = withSavepoint(ctx, tx, func() error {
+		return pgInsertAuthor(ctx, tx, author)
+	})
+	var exists *AuthorExistsError
+	if errors.As(err, &exists) {
+		if err := tx.QueryRowContext(ctx, query, key).Scan(&quote.AuthorID, &quote.Author); err != nil {
+			return fmt.Errorf("failed to resolve author: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	quote.AuthorID = author.ID
+	return nil
+}
+
+func pgInsertAuthor(ctx context.Context, tx *sql.Tx, author *domain.Author) error {
+	normalizeAliases(author)
+	names := append([]string{author.Name}, author.Aliases...)
+	for _, name := range names {
+		if err := pgCheckAuthorName(ctx, tx, name); err != nil {
+			return err
+		}
+	}
+
+	err := withSavepoint(ctx, tx, func() error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO authors (id, name, bio, created_at) VALUES ($1, $2, $3, $4)",
+			author.ID, author.Name, author.Bio, formatTimestamp(author.CreatedAt))
+		return err
+	})
+	if err != nil {
+		if pgUniqueViolation(err, "authors_pkey") {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create author: %w", err)
+	}
+	for _, name := range names {
+		result, err := tx.ExecContext(ctx, "INSERT INTO author_names (name_key, author_id, name) VALUES ($1, $2, $3) ON CONFLICT (name_key) DO NOTHING",
+			domain.AuthorKey(name), author.ID, name)
+		if err != nil {
+			return fmt.Errorf("failed to add author name: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			// Taken by a transaction that committed after the check above.
+			return pgCheckAuthorName(ctx, tx, name)
+		}
+	}
+	return nil
+}
+
+// pgCheckAuthorName returns an AuthorExistsError if name belongs to an author.
+func pgCheckAuthorName(ctx context.Context, tx *sql.Tx, name string) error {
+	var existingID string
+	err := tx.QueryRowContext(ctx, "SELECT author_id FROM author_names WHERE name_key = $1", domain.AuthorKey(name)).Scan(&existingID)
+	if err == nil {
+		return &AuthorExistsError{ExistingID: existingID, Name: name}
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check author name: %w", err)
+	}
+	return nil
+}
+
+// pgReassignQuote is reassignQuote for Postgres.
+func pgReassignQuote(ctx context.Context, tx *sql.Tx, id, text, authorID, author, updatedAt string) error {
+	query := `
+	UPDATE quotes SET author_id = $1, author = $2, fingerprint = $3, updated_at = $4, version = version + 1
+	WHERE id = $5`
+	fingerprint := domain.Fingerprint(text, author)
+	err := withSavepoint(ctx, tx, func() error {
+		_, err := tx.ExecContext(ctx, query, authorID, author, fingerprint, updatedAt, id)
+		return err
+	})
+	if pgUniqueViolation(err, "idx_quotes_fingerprint") {
+		_, err = tx.ExecContext(ctx, query, authorID, author, nil, updatedAt, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reassign quote %s: %w", id, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	author.ID = authorIDs.NewID()
+	if author.CreatedAt.IsZero() {
+		author.CreatedAt = time.Now().UTC()
+	}
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		return pgInsertAuthor(ctx, tx, author)
+	})
+}
+
+// pgAuthorColumns is authorColumns in Postgres' dialect.
+const pgAuthorColumns = `a.id, a.name, a.bio, a.created_at,
+	(SELECT COUNT(*) FROM quotes q WHERE q.author_id = a.id),
+	(SELECT string_agg(n.name, chr(31)) FROM author_names n WHERE n.author_id = a.id)`
+
+func (r *PostgresRepository) GetAuthor(ctx context.Context, id string) (*domain.Author, error) {
+	authors, err := queryAuthors(ctx, r.db, "SELECT "+pgAuthorColumns+" FROM authors a WHERE a.id = $1", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+	if len(authors) == 0 {
+		return nil, ErrAuthorNotFound
+	}
+	return &authors[0], nil
+}
+
+func (r *PostgresRepository) ListAuthors(ctx context.Context) ([]domain.Author, error) {
+	authors, err := queryAuthors(ctx, r.db, "SELECT "+pgAuthorColumns+" FROM authors a ORDER BY a.name, a.id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+	return authors, nil
+}
+
+// MergeAuthors locks both authors first, so that concurrent merges involving
+// either of them run one after the other.
+func (r *PostgresRepository) MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error) {
+	if targetID == sourceID {
+		return r.GetAuthor(ctx, targetID)
+	}
+
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var locked int
+		err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM (SELECT id FROM authors WHERE id IN ($1, $2) ORDER BY id FOR UPDATE) a",
+			targetID, sourceID).Scan(&locked)
+		if err != nil {
+			return fmt.Errorf("failed to lock authors: %w", err)
+		}
+		if locked < 2 {
+			return ErrAuthorNotFound
+		}
+
+		var targetName, targetBio, sourceBio string
+		err = tx.QueryRowContext(ctx, "SELECT name, bio FROM authors WHERE id = $1", targetID).Scan(&targetName, &targetBio)
+		if err == nil {
+			err = tx.QueryRowContext(ctx, "SELECT bio FROM authors WHERE id = $1", sourceID).Scan(&sourceBio)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get author: %w", err)
+		}
+
+		moved, err := queryContents(ctx, tx, "SELECT id, text, author FROM quotes WHERE author_id = $1 ORDER BY created_at, id", sourceID)
+		if err != nil {
+			return err
+		}
+		updatedAt := formatTimestamp(time.Now())
+		for _, quote := range moved {
+			if err := pgReassignQuote(ctx, tx, quote.ID, quote.Text, targetID, targetName, updatedAt); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE author_names SET author_id = $1 WHERE author_id = $2", targetID, sourceID); err != nil {
+			return fmt.Errorf("failed to move author names: %w", err)
+		}
+		if targetBio == "" && sourceBio != "" {
+			if _, err := tx.ExecContext(ctx, "UPDATE authors SET bio = $1 WHERE id = $2", sourceBio, targetID); err != nil {
+				return fmt.Errorf("failed to update author: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM authors WHERE id = $1", sourceID); err != nil {
+			return fmt.Errorf("failed to delete author: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetAuthor(ctx, targetID)
+}
@@ -1,12 +1,128 @@
 package repository
 
-import "test-task-scout-go/internal/domain"
+import (
+	"context"
+	"iter"
+	"sort"
+	"time"
 
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/idgen"
+)
+
+// QuoteRepository stores quotes together with their authors. Create,
+// CreateBatch and Update resolve quote.Author to a stored author by name or
+// alias, creating the author when there is none, and set quote.AuthorID and
+// the canonical quote.Author.
 type QuoteRepository interface {
-	Create(quote *domain.Quote) error
-	GetAll() ([]domain.Quote, error)
-	GetByID(id string) (*domain.Quote, error)
-	GetByAuthor(author string) ([]domain.Quote, error)
-	Delete(id string) error
-	GetRandom() (*domain.Quote, error)
-} 
\ No newline at end of file
+	AuthorRepository
+
+	Create(ctx context.Context, quote *domain.Quote) error
+	// CreateBatch stores quotes in one unit of work and returns one error per
+	// quote (nil when it was stored). When atomic is set and any quote fails,
+	// nothing is stored. The error result reports a failure of the batch itself.
+	CreateBatch(ctx context.Context, quotes []*domain.Quote, atomic bool) ([]error, error)
+	GetAll(ctx context.Context) ([]domain.Quote, error)
+	Count(ctx context.Context) (int, error)
+	GetByID(ctx context.Context, id string) (*domain.Quote, error)
+	GetByAuthor(ctx context.Context, author string) ([]domain.Quote, error)
+	Update(ctx context.Context, quote *domain.Quote) error
+	Delete(ctx context.Context, id string, version int64) error
+	GetRandom(ctx context.Context, filter domain.QuoteFilter) (*domain.Quote, error)
+	Search(ctx context.Context, query string, limit int) ([]domain.SearchResult, error)
+	List(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error)
+	// Iterate yields every quote matching filter in ID order without loading
+	// them all at once. Iteration stops after the first error.
+	Iterate(ctx context.Context, filter domain.QuoteFilter) iter.Seq2[domain.Quote, error]
+	ListTags(ctx context.Context) ([]domain.TagCount, error)
+
+	// Daily pins map a date (YYYY-MM-DD) to the quote shown on that day.
+	GetDailyPin(ctx context.Context, date string) (string, error)
+	SetDailyPin(ctx context.Context, date, quoteID string) error
+	DeleteDailyPin(ctx context.Context, date string) error
+}
+
+type AuthorRepository interface {
+	// CreateAuthor stores a new author and assigns its ID. It fails with an
+	// *AuthorExistsError when the name or an alias belongs to another author.
+	CreateAuthor(ctx context.Context, author *domain.Author) error
+	GetAuthor(ctx context.Context, id string) (*domain.Author, error)
+	// ListAuthors returns every author ordered by name.
+	ListAuthors(ctx context.Context) ([]domain.Author, error)
+	// MergeAuthors folds source into target: the quotes of source move to
+	// target under its name, the names of source become aliases of target and
+	// source is deleted. Moved quotes get a new version.
+	MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error)
+}
+
+// HealthChecker is implemented by repositories that can tell whether they are
+// able to serve requests, such as by pinging their database.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// CheckHealth checks repo if it is a HealthChecker; any other repository is
+// taken to be healthy.
+func CheckHealth(ctx context.Context, repo QuoteRepository) error {
+	if checker, ok := repo.(HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
+
+// authorIDs generates IDs of new authors, including the ones created
+// implicitly for a quote.
+var authorIDs = idgen.UUIDv4{}
+
+// timestampLayout is fixed-width so that formatted UTC timestamps sort
+// lexicographically in creation order.
+const timestampLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+// stampCreated fills in timestamps the caller left unset, so quotes written
+// directly through a repository still carry audit metadata.
+func stampCreated(quote *domain.Quote) {
+	if quote.CreatedAt.IsZero() {
+		quote.CreatedAt = time.Now().UTC()
+	}
+	if quote.UpdatedAt.IsZero() {
+		quote.UpdatedAt = quote.CreatedAt
+	}
+}
+
+func stampUpdated(quote *domain.Quote) {
+	if quote.UpdatedAt.IsZero() {
+		quote.UpdatedAt = time.Now().UTC()
+	}
+}
+
+// sortTagCounts orders tags by popularity, then alphabetically.
+func sortTagCounts(counts []domain.TagCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+}
+
+// normalizeAliases drops aliases that repeat the author's name or each other
+// once compared by domain.AuthorKey, and sorts the rest. The result is never
+// nil.
+func normalizeAliases(author *domain.Author) {
+	seen := map[string]bool{domain.AuthorKey(author.Name): true}
+	aliases := make([]string, 0, len(author.Aliases))
+	for _, alias := range author.Aliases {
+		key := domain.AuthorKey(alias)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	author.Aliases = aliases
+}
@@ -2,8 +2,13 @@
 package repository_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"test-task-scout-go/internal/domain"
 	"test-task-scout-go/internal/repository"
+	"time"
 
 	"testing"
 )
@@ -16,12 +21,12 @@ func testRepositoryCreateAndGet(t *testing.T, repo repository.QuoteRepository) {
 		Author: "Common Test Author",
 	}
 
-	err := repo.Create(quote)
+	err := repo.Create(t.Context(), quote)
 	if err != nil {
 		t.Fatalf("Create failed: %v", err)
 	}
 
-	retrievedQuote, err := repo.GetByID("test-123")
+	retrievedQuote, err := repo.GetByID(t.Context(), "test-123")
 	if err != nil {
 		t.Fatalf("GetByID failed: %v", err)
 	}
@@ -30,7 +35,7 @@ func testRepositoryCreateAndGet(t *testing.T, repo repository.QuoteRepository) {
 		t.Errorf("Retrieved quote does not match created one. Expected %+v, got %+v", quote, retrievedQuote)
 	}
 
-	_, err = repo.GetByID("non-existent")
+	_, err = repo.GetByID(t.Context(), "non-existent")
 	if err == nil {
 		t.Error("GetByID for non-existent ID did not return an error")
 	}
@@ -46,12 +51,12 @@ func testRepositoryCreateDuplicate(t *testing.T, repo repository.QuoteRepository
 	quote1 := &domain.Quote{ID: "dup-1", Text: "Quote 1", Author: "Author 1"}
 	quote2 := &domain.Quote{ID: "dup-1", Text: "Quote 2", Author: "Author 2"}
 
-	err := repo.Create(quote1)
+	err := repo.Create(t.Context(), quote1)
 	if err != nil {
 		t.Fatalf("Create failed for first quote: %v", err)
 	}
 
-	err = repo.Create(quote2)
+	err = repo.Create(t.Context(), quote2)
 	if err == nil {
 		t.Error("Create did not return an error for duplicate ID")
 	}
@@ -64,7 +69,7 @@ func testRepositoryCreateDuplicate(t *testing.T, repo repository.QuoteRepository
 func testRepositoryGetAll(t *testing.T, repo repository.QuoteRepository) {
 	t.Helper()
 
-	emptyQuotes, err := repo.GetAll()
+	emptyQuotes, err := repo.GetAll(t.Context())
 	if err != nil {
 		t.Fatalf("GetAll failed on empty repo: %v", err)
 	}
@@ -75,10 +80,10 @@ func testRepositoryGetAll(t *testing.T, repo repository.QuoteRepository) {
 	quote1 := &domain.Quote{ID: "all-1", Text: "Quote 1", Author: "Author 1"}
 	quote2 := &domain.Quote{ID: "all-2", Text: "Quote 2", Author: "Author 2"}
 
-	repo.Create(quote1)
-	repo.Create(quote2)
+	repo.Create(t.Context(), quote1)
+	repo.Create(t.Context(), quote2)
 
-	quotes, err := repo.GetAll()
+	quotes, err := repo.GetAll(t.Context())
 	if err != nil {
 		t.Fatalf("GetAll failed: %v", err)
 	}
@@ -86,6 +91,9 @@ func testRepositoryGetAll(t *testing.T, repo repository.QuoteRepository) {
 	if len(quotes) != 2 {
 		t.Errorf("Expected 2 quotes, got %d", len(quotes))
 	}
+	if count, err := repo.Count(t.Context()); err != nil || count != 2 {
+		t.Errorf("Expected Count to return 2, got %d (%v)", count, err)
+	}
 
 	found1 := false
 	found2 := false
@@ -110,11 +118,11 @@ func testRepositoryGetByAuthor(t *testing.T, repo repository.QuoteRepository) {
 	quote2 := &domain.Quote{ID: "author-2", Text: "Quote 2", Author: "Author B"}
 	quote3 := &domain.Quote{ID: "author-3", Text: "Quote 3", Author: "Author A"}
 
-	repo.Create(quote1)
-	repo.Create(quote2)
-	repo.Create(quote3)
+	repo.Create(t.Context(), quote1)
+	repo.Create(t.Context(), quote2)
+	repo.Create(t.Context(), quote3)
 
-	quotesA, err := repo.GetByAuthor("Author A")
+	quotesA, err := repo.GetByAuthor(t.Context(), "Author A")
 	if err != nil {
 		t.Fatalf("GetByAuthor('Author A') failed: %v", err)
 	}
@@ -135,7 +143,7 @@ func testRepositoryGetByAuthor(t *testing.T, repo repository.QuoteRepository) {
 		t.Errorf("GetByAuthor('Author A') did not return correct quotes")
 	}
 
-	quotesB, err := repo.GetByAuthor("Author B")
+	quotesB, err := repo.GetByAuthor(t.Context(), "Author B")
 	if err != nil {
 		t.Fatalf("GetByAuthor('Author B') failed: %v", err)
 	}
@@ -146,7 +154,7 @@ func testRepositoryGetByAuthor(t *testing.T, repo repository.QuoteRepository) {
 		t.Errorf("GetByAuthor('Author B') returned incorrect quote: %+v", quotesB[0])
 	}
 
-	quotesC, err := repo.GetByAuthor("Author C")
+	quotesC, err := repo.GetByAuthor(t.Context(), "Author C")
 	if err != nil {
 		t.Fatalf("GetByAuthor('Author C') failed: %v", err)
 	}
@@ -161,15 +169,15 @@ func testRepositoryDelete(t *testing.T, repo repository.QuoteRepository) {
 	quote1 := &domain.Quote{ID: "del-1", Text: "Quote 1", Author: "Author 1"}
 	quote2 := &domain.Quote{ID: "del-2", Text: "Quote 2", Author: "Author 2"}
 
-	repo.Create(quote1)
-	repo.Create(quote2)
+	repo.Create(t.Context(), quote1)
+	repo.Create(t.Context(), quote2)
 
-	err := repo.Delete("del-1")
+	err := repo.Delete(t.Context(), "del-1", 0)
 	if err != nil {
 		t.Fatalf("Delete failed for 'del-1': %v", err)
 	}
 
-	_, err = repo.GetByID("del-1")
+	_, err = repo.GetByID(t.Context(), "del-1")
 	if err == nil {
 		t.Error("GetByID for deleted quote 'del-1' did not return an error")
 	}
@@ -178,7 +186,7 @@ func testRepositoryDelete(t *testing.T, repo repository.QuoteRepository) {
 		t.Errorf("Expected error '%s' for deleted quote, got '%s'", expectedErr, err.Error())
 	}
 
-	retrievedQuote2, err := repo.GetByID("del-2")
+	retrievedQuote2, err := repo.GetByID(t.Context(), "del-2")
 	if err != nil {
 		t.Fatalf("GetByID failed for 'del-2' after deleting 'del-1': %v", err)
 	}
@@ -186,19 +194,22 @@ func testRepositoryDelete(t *testing.T, repo repository.QuoteRepository) {
 		t.Errorf("Quote 'del-2' was unexpectedly deleted or modified")
 	}
 
-	err = repo.Delete("non-existent")
+	err = repo.Delete(t.Context(), "non-existent", 0)
 	if err == nil {
 		t.Error("Delete for non-existent ID did not return an error")
 	}
 	if err != nil && err.Error() != expectedErr {
 		t.Errorf("Expected error '%s' for non-existent delete, got '%s'", expectedErr, err.Error())
 	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected error to match ErrNotFound, got '%v'", err)
+	}
 }
 
 func testRepositoryGetRandom(t *testing.T, repo repository.QuoteRepository) {
 	t.Helper()
 
-	_, err := repo.GetRandom()
+	_, err := repo.GetRandom(t.Context(), domain.QuoteFilter{})
 	if err == nil {
 		t.Error("GetRandom on empty repo did not return an error")
 	}
@@ -206,18 +217,21 @@ func testRepositoryGetRandom(t *testing.T, repo repository.QuoteRepository) {
 	if err != nil && err.Error() != expectedErr {
 		t.Errorf("Expected error '%s' on empty repo, got '%s'", expectedErr, err.Error())
 	}
+	if !errors.Is(err, repository.ErrNoQuotes) || !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected error to match ErrNoQuotes and ErrNotFound, got '%v'", err)
+	}
 
 	quote1 := &domain.Quote{ID: "rand-1", Text: "Random Quote 1", Author: "Author 1"}
 	quote2 := &domain.Quote{ID: "rand-2", Text: "Random Quote 2", Author: "Author 2"}
 	quote3 := &domain.Quote{ID: "rand-3", Text: "Random Quote 3", Author: "Author 3"}
 
-	repo.Create(quote1)
-	repo.Create(quote2)
-	repo.Create(quote3)
+	repo.Create(t.Context(), quote1)
+	repo.Create(t.Context(), quote2)
+	repo.Create(t.Context(), quote3)
 
 	foundIDs := make(map[string]bool)
 	for i := 0; i < 100; i++ {
-		quote, err := repo.GetRandom()
+		quote, err := repo.GetRandom(t.Context(), domain.QuoteFilter{})
 		if err != nil {
 			t.Fatalf("GetRandom failed after adding quotes: %v", err)
 		}
@@ -233,4 +247,842 @@ func testRepositoryGetRandom(t *testing.T, repo repository.QuoteRepository) {
 	if len(foundIDs) < 2 {
 		t.Logf("Warning: GetRandom returned only %d unique quotes in 10 tries. This might indicate an issue or just bad luck.", len(foundIDs))
 	}
-} 
\ No newline at end of file
+}
+
+func testRepositorySearch(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	quote1 := &domain.Quote{ID: "search-1", Text: "За свою улетность денег не беру, а за красоту тем более", Author: "Панда По"}
+	quote2 := &domain.Quote{ID: "search-2", Text: "Счастье для всех, даром, и пусть никто не уйдет обиженный!", Author: "Редрик"}
+	quote3 := &domain.Quote{ID: "search-3", Text: "Красоту красоту красоту видит тот, кто ищет", Author: "Someone"}
+
+	repo.Create(t.Context(), quote1)
+	repo.Create(t.Context(), quote2)
+	repo.Create(t.Context(), quote3)
+
+	results, err := repo.Search(t.Context(), "красоту", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results for 'красоту', got %d: %+v", len(results), results)
+	}
+	if results[0].Quote.ID != "search-3" {
+		t.Errorf("Expected the quote with more matches to rank first, got %s", results[0].Quote.ID)
+	}
+	if results[0].Score < results[1].Score {
+		t.Errorf("Results are not ordered by score: %+v", results)
+	}
+	for _, result := range results {
+		if !strings.Contains(result.Snippet, "<mark>") {
+			t.Errorf("Snippet for %s has no highlighted match: %q", result.Quote.ID, result.Snippet)
+		}
+	}
+
+	results, err = repo.Search(t.Context(), "счастье ДАРОМ", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Quote.ID != "search-2" {
+		t.Errorf("Expected only 'search-2' for all-terms match, got %+v", results)
+	}
+
+	results, err = repo.Search(t.Context(), "панда", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Quote.ID != "search-1" {
+		t.Errorf("Expected author match 'search-1', got %+v", results)
+	}
+
+	results, err = repo.Search(t.Context(), "красоту", 1)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected limit of 1 result, got %d", len(results))
+	}
+
+	if err := repo.Delete(t.Context(), "search-3", 0); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	results, err = repo.Search(t.Context(), "красоту", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Quote.ID != "search-1" {
+		t.Errorf("Deleted quote is still returned by search: %+v", results)
+	}
+
+	markup := &domain.Quote{ID: "search-4", Text: `<script>alert("x")</script> & <b>bold</b> > all`, Author: "Mallory"}
+	if err := repo.Create(t.Context(), markup); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	results, err = repo.Search(t.Context(), "alert bold", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected the quote with markup, got %+v", results)
+	}
+	expected := `&lt;script&gt;<mark>alert</mark>(&#34;x&#34;)&lt;/script&gt; &amp; &lt;b&gt;<mark>bold</mark>&lt;/b&gt; &gt; all`
+	if results[0].Snippet != expected {
+		t.Errorf("Expected an escaped snippet %q, got %q", expected, results[0].Snippet)
+	}
+
+	results, err = repo.Search(t.Context(), "nonexistentword", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no results, got %+v", results)
+	}
+}
+
+func collectPages(t *testing.T, repo repository.QuoteRepository, query domain.ListQuery) []string {
+	t.Helper()
+
+	var ids []string
+	for i := 0; ; i++ {
+		if i > 10 {
+			t.Fatalf("List did not terminate for %+v", query)
+		}
+		page, err := repo.List(t.Context(), query)
+		if err != nil {
+			t.Fatalf("List(%+v) failed: %v", query, err)
+		}
+		if len(page.Quotes) > query.Limit {
+			t.Fatalf("List returned %d quotes, more than limit %d", len(page.Quotes), query.Limit)
+		}
+		for _, q := range page.Quotes {
+			ids = append(ids, q.ID)
+		}
+		if page.NextCursor == "" {
+			return ids
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+func testRepositoryList(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	quotes := []*domain.Quote{
+		{ID: "list-c", Text: "Quote 1", Author: "Author B"},
+		{ID: "list-a", Text: "Quote 2", Author: "Author A"},
+		{ID: "list-e", Text: "Quote 3", Author: "Author B"},
+		{ID: "list-b", Text: "Quote 4", Author: "Author C"},
+		{ID: "list-d", Text: "Quote 5", Author: "Author A"},
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, q := range quotes {
+		q.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+		if err := repo.Create(t.Context(), q); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query domain.ListQuery
+		want  []string
+	}{
+		{"ByID", domain.ListQuery{SortBy: domain.SortByID, Limit: 2}, []string{"list-a", "list-b", "list-c", "list-d", "list-e"}},
+		{"ByIDDesc", domain.ListQuery{SortBy: domain.SortByID, Descending: true, Limit: 2}, []string{"list-e", "list-d", "list-c", "list-b", "list-a"}},
+		{"ByAuthor", domain.ListQuery{SortBy: domain.SortByAuthor, Limit: 2}, []string{"list-a", "list-d", "list-c", "list-e", "list-b"}},
+		{"ByAuthorDesc", domain.ListQuery{SortBy: domain.SortByAuthor, Descending: true, Limit: 3}, []string{"list-b", "list-e", "list-c", "list-d", "list-a"}},
+		{"ByCreated", domain.ListQuery{SortBy: domain.SortByCreated, Limit: 2}, []string{"list-c", "list-a", "list-e", "list-b", "list-d"}},
+		{"ByCreatedDesc", domain.ListQuery{SortBy: domain.SortByCreated, Descending: true, Limit: 4}, []string{"list-d", "list-b", "list-e", "list-a", "list-c"}},
+		{"AuthorFilter", domain.ListQuery{QuoteFilter: domain.QuoteFilter{Author: "Author B"}, SortBy: domain.SortByID, Limit: 1}, []string{"list-c", "list-e"}},
+		{"CreatedAfter", domain.ListQuery{QuoteFilter: domain.QuoteFilter{CreatedAfter: base.Add(2 * time.Hour)}, SortBy: domain.SortByCreated, Limit: 1}, []string{"list-b", "list-d"}},
+		{"CreatedRange", domain.ListQuery{QuoteFilter: domain.QuoteFilter{CreatedAfter: base, CreatedBefore: base.Add(3 * time.Hour)}, SortBy: domain.SortByID, Limit: 1}, []string{"list-a", "list-e"}},
+		{"ExactPage", domain.ListQuery{SortBy: domain.SortByID, Limit: 5}, []string{"list-a", "list-b", "list-c", "list-d", "list-e"}},
+	}
+	for _, tt := range tests {
+		got := collectPages(t, repo, tt.query)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	page, err := repo.List(t.Context(), domain.ListQuery{SortBy: domain.SortByID, Limit: 2})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	_, err = repo.List(t.Context(), domain.ListQuery{SortBy: domain.SortByAuthor, Limit: 2, Cursor: page.NextCursor})
+	if err == nil {
+		t.Error("List accepted a cursor issued for a different sort")
+	}
+	_, err = repo.List(t.Context(), domain.ListQuery{SortBy: domain.SortByID, Limit: 2, Cursor: "not-a-cursor"})
+	if err == nil {
+		t.Error("List accepted a malformed cursor")
+	}
+}
+
+func testRepositoryUpdate(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	repo.Create(t.Context(), &domain.Quote{ID: "upd-1", Text: "Original typo text", Author: "Author A"})
+	repo.Create(t.Context(), &domain.Quote{ID: "upd-2", Text: "Another quote", Author: "Author B"})
+
+	updated := &domain.Quote{ID: "upd-1", Text: "Corrected text", Author: "Author C"}
+	if err := repo.Update(t.Context(), updated); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	retrieved, err := repo.GetByID(t.Context(), "upd-1")
+	if err != nil {
+		t.Fatalf("GetByID failed after update: %v", err)
+	}
+	if retrieved.Text != "Corrected text" || retrieved.Author != "Author C" {
+		t.Errorf("Update was not persisted, got %+v", retrieved)
+	}
+
+	byOldAuthor, err := repo.GetByAuthor(t.Context(), "Author A")
+	if err != nil {
+		t.Fatalf("GetByAuthor failed: %v", err)
+	}
+	if len(byOldAuthor) != 0 {
+		t.Errorf("Expected no quotes for the previous author, got %+v", byOldAuthor)
+	}
+
+	ids := collectPages(t, repo, domain.ListQuery{SortBy: domain.SortByAuthor, Limit: 10})
+	if strings.Join(ids, ",") != "upd-2,upd-1" {
+		t.Errorf("Sort by author does not reflect the update, got %v", ids)
+	}
+
+	results, err := repo.Search(t.Context(), "typo", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Search still matches the old text: %+v", results)
+	}
+	results, err = repo.Search(t.Context(), "corrected", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Quote.ID != "upd-1" {
+		t.Errorf("Search does not match the new text: %+v", results)
+	}
+
+	err = repo.Update(t.Context(), &domain.Quote{ID: "non-existent", Text: "Text", Author: "Author"})
+	expectedErr := "quote not found"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected error '%s' for non-existent update, got '%v'", expectedErr, err)
+	}
+}
+
+func testRepositoryVersioning(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	quote := &domain.Quote{ID: "ver-1", Text: "Text", Author: "Author"}
+	if err := repo.Create(t.Context(), quote); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if quote.Version != 1 {
+		t.Errorf("Expected new quote to have version 1, got %d", quote.Version)
+	}
+
+	first := &domain.Quote{ID: "ver-1", Text: "First edit", Author: "Author", Version: 1}
+	if err := repo.Update(t.Context(), first); err != nil {
+		t.Fatalf("Update with current version failed: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("Expected version 2 after update, got %d", first.Version)
+	}
+
+	stale := &domain.Quote{ID: "ver-1", Text: "Stale edit", Author: "Author", Version: 1}
+	err := repo.Update(t.Context(), stale)
+	expectedErr := "quote version conflict"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected error '%s' for stale update, got '%v'", expectedErr, err)
+	}
+	if !errors.Is(err, repository.ErrVersionConflict) {
+		t.Errorf("Expected error to match ErrVersionConflict, got '%v'", err)
+	}
+
+	retrieved, err := repo.GetByID(t.Context(), "ver-1")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if retrieved.Text != "First edit" || retrieved.Version != 2 {
+		t.Errorf("Stale update overwrote the quote: %+v", retrieved)
+	}
+
+	unconditional := &domain.Quote{ID: "ver-1", Text: "Forced edit", Author: "Author"}
+	if err := repo.Update(t.Context(), unconditional); err != nil {
+		t.Fatalf("Unconditional update failed: %v", err)
+	}
+	if unconditional.Version != 3 {
+		t.Errorf("Expected version 3 after unconditional update, got %d", unconditional.Version)
+	}
+
+	err = repo.Delete(t.Context(), "ver-1", 2)
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected error '%s' for stale delete, got '%v'", expectedErr, err)
+	}
+	if err := repo.Delete(t.Context(), "ver-1", 3); err != nil {
+		t.Fatalf("Delete with current version failed: %v", err)
+	}
+	err = repo.Delete(t.Context(), "ver-1", 3)
+	if err == nil || err.Error() != "quote not found" {
+		t.Errorf("Expected 'quote not found' after delete, got '%v'", err)
+	}
+}
+
+func testRepositoryAuditMetadata(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	before := time.Now().UTC()
+	defaulted := &domain.Quote{ID: "audit-1", Text: "First", Author: "Author"}
+	if err := repo.Create(t.Context(), defaulted); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if defaulted.CreatedAt.Before(before) || !defaulted.UpdatedAt.Equal(defaulted.CreatedAt) {
+		t.Errorf("Expected Create to stamp CreatedAt and UpdatedAt, got %v and %v", defaulted.CreatedAt, defaulted.UpdatedAt)
+	}
+
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	quote := &domain.Quote{ID: "audit-2", Text: "Second", Author: "Author", CreatedAt: createdAt, UpdatedAt: createdAt, CreatedBy: "alice"}
+	if err := repo.Create(t.Context(), quote); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	retrieved, err := repo.GetByID(t.Context(), "audit-2")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if !retrieved.CreatedAt.Equal(createdAt) || !retrieved.UpdatedAt.Equal(createdAt) || retrieved.CreatedBy != "alice" {
+		t.Errorf("Audit metadata did not round-trip: %+v", retrieved)
+	}
+
+	updatedAt := createdAt.Add(time.Hour)
+	update := &domain.Quote{ID: "audit-2", Text: "Edited", Author: "Author", UpdatedAt: updatedAt}
+	if err := repo.Update(t.Context(), update); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if !update.CreatedAt.Equal(createdAt) || update.CreatedBy != "alice" {
+		t.Errorf("Update did not return the stored creation metadata: %+v", update)
+	}
+	retrieved, err = repo.GetByID(t.Context(), "audit-2")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if !retrieved.CreatedAt.Equal(createdAt) || retrieved.CreatedBy != "alice" || !retrieved.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("Update overwrote creation metadata or lost UpdatedAt: %+v", retrieved)
+	}
+}
+
+func testRepositoryTags(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	quotes := []*domain.Quote{
+		{ID: "tag-1", Text: "Quote 1", Author: "Author", Tags: []string{"Humor", "film"}},
+		{ID: "tag-2", Text: "Quote 2", Author: "Author", Tags: []string{"humor"}},
+		{ID: "tag-3", Text: "Quote 3", Author: "Author", Tags: []string{"motivation", "film"}},
+		{ID: "tag-4", Text: "Quote 4", Author: "Author"},
+	}
+	for _, q := range quotes {
+		if err := repo.Create(t.Context(), q); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	retrieved, err := repo.GetByID(t.Context(), "tag-1")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if strings.Join(retrieved.Tags, ",") != "film,humor" {
+		t.Errorf("Expected tags [film humor], got %v", retrieved.Tags)
+	}
+
+	counts, err := repo.ListTags(t.Context())
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	want := []domain.TagCount{{Tag: "film", Count: 2}, {Tag: "humor", Count: 2}, {Tag: "motivation", Count: 1}}
+	if fmt.Sprint(counts) != fmt.Sprint(want) {
+		t.Errorf("Expected tag counts %v, got %v", want, counts)
+	}
+
+	filters := []struct {
+		name   string
+		filter domain.QuoteFilter
+		want   []string
+	}{
+		{"Any", domain.QuoteFilter{Tags: []string{"humor", "motivation"}, TagMode: domain.TagModeAny}, []string{"tag-1", "tag-2", "tag-3"}},
+		{"All", domain.QuoteFilter{Tags: []string{"humor", "film"}, TagMode: domain.TagModeAll}, []string{"tag-1"}},
+		{"Unknown", domain.QuoteFilter{Tags: []string{"poetry"}, TagMode: domain.TagModeAny}, nil},
+	}
+	for _, tt := range filters {
+		got := collectPages(t, repo, domain.ListQuery{QuoteFilter: tt.filter, SortBy: domain.SortByID, Limit: 2})
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+
+		quote, err := repo.GetRandom(t.Context(), tt.filter)
+		if len(tt.want) == 0 {
+			if !errors.Is(err, repository.ErrNoQuotes) {
+				t.Errorf("%s: expected ErrNoQuotes from GetRandom, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: GetRandom failed: %v", tt.name, err)
+		}
+		if !strings.Contains(strings.Join(tt.want, ","), quote.ID) {
+			t.Errorf("%s: GetRandom returned %s, expected one of %v", tt.name, quote.ID, tt.want)
+		}
+	}
+
+	update := &domain.Quote{ID: "tag-2", Text: "Quote 2", Author: "Author", Tags: []string{"poetry"}}
+	if err := repo.Update(t.Context(), update); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := repo.Delete(t.Context(), "tag-3", 0); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	counts, err = repo.ListTags(t.Context())
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	want = []domain.TagCount{{Tag: "film", Count: 1}, {Tag: "humor", Count: 1}, {Tag: "poetry", Count: 1}}
+	if fmt.Sprint(counts) != fmt.Sprint(want) {
+		t.Errorf("Expected tag counts %v after update and delete, got %v", want, counts)
+	}
+}
+
+func testRepositoryRandomFilter(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	quotes := []*domain.Quote{
+		{ID: "rnd-1", Text: "Short", Author: "Author A", Tags: []string{"humor"}},
+		{ID: "rnd-2", Text: "Краткость", Author: "Author A"},
+		{ID: "rnd-3", Text: strings.Repeat("Long quote text. ", 12), Author: "Author A", Tags: []string{"humor"}},
+		{ID: "rnd-4", Text: "Tiny", Author: "Author B", Tags: []string{"humor"}},
+	}
+	for _, q := range quotes {
+		if err := repo.Create(t.Context(), q); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.QuoteFilter
+		want   []string
+	}{
+		{"Author", domain.QuoteFilter{Author: "Author A"}, []string{"rnd-1", "rnd-2", "rnd-3"}},
+		// "Краткость" is 9 characters but 18 bytes, so lengths must be counted in characters.
+		{"MaxLength", domain.QuoteFilter{MaxLength: 10}, []string{"rnd-1", "rnd-2", "rnd-4"}},
+		{"MinLength", domain.QuoteFilter{MinLength: 140}, []string{"rnd-3"}},
+		{"Combined", domain.QuoteFilter{Author: "Author A", MaxLength: 140, Tags: []string{"humor"}, TagMode: domain.TagModeAny}, []string{"rnd-1"}},
+		{"NoMatch", domain.QuoteFilter{Author: "Author B", MinLength: 10}, nil},
+	}
+	for _, tt := range tests {
+		seen := make(map[string]bool)
+		for i := 0; i < 100 && len(seen) < len(tt.want); i++ {
+			quote, err := repo.GetRandom(t.Context(), tt.filter)
+			if len(tt.want) == 0 {
+				if !errors.Is(err, repository.ErrNoQuotes) {
+					t.Errorf("%s: expected ErrNoQuotes, got %v", tt.name, err)
+				}
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: GetRandom failed: %v", tt.name, err)
+			}
+			if !strings.Contains(strings.Join(tt.want, ","), quote.ID) {
+				t.Fatalf("%s: GetRandom returned %s, expected one of %v", tt.name, quote.ID, tt.want)
+			}
+			seen[quote.ID] = true
+		}
+		if len(seen) != len(tt.want) {
+			t.Errorf("%s: expected every match to be picked eventually, saw %v of %v", tt.name, seen, tt.want)
+		}
+	}
+}
+
+func testRepositoryDailyPins(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	if err := repo.Create(t.Context(), &domain.Quote{ID: "pin-1", Text: "First", Author: "Author"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := repo.Create(t.Context(), &domain.Quote{ID: "pin-2", Text: "Second", Author: "Author"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := repo.GetDailyPin(t.Context(), "2024-03-01"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a date without a pin, got %v", err)
+	}
+	if err := repo.SetDailyPin(t.Context(), "2024-03-01", "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when pinning a missing quote, got %v", err)
+	}
+
+	if err := repo.SetDailyPin(t.Context(), "2024-03-01", "pin-1"); err != nil {
+		t.Fatalf("SetDailyPin failed: %v", err)
+	}
+	if err := repo.SetDailyPin(t.Context(), "2024-03-01", "pin-2"); err != nil {
+		t.Fatalf("SetDailyPin failed to replace a pin: %v", err)
+	}
+	quoteID, err := repo.GetDailyPin(t.Context(), "2024-03-01")
+	if err != nil || quoteID != "pin-2" {
+		t.Errorf("Expected pin-2 for 2024-03-01, got %q (%v)", quoteID, err)
+	}
+
+	if err := repo.DeleteDailyPin(t.Context(), "2024-03-01"); err != nil {
+		t.Fatalf("DeleteDailyPin failed: %v", err)
+	}
+	if err := repo.DeleteDailyPin(t.Context(), "2024-03-01"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when deleting a missing pin, got %v", err)
+	}
+}
+
+func testRepositoryCanceledContext(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	if err := repo.Create(t.Context(), &domain.Quote{ID: "ctx-1", Text: "Text", Author: "Author"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	if _, err := repo.GetAll(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected GetAll to fail with context.Canceled, got %v", err)
+	}
+	if _, err := repo.List(ctx, domain.ListQuery{SortBy: domain.SortByID, Limit: 10}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected List to fail with context.Canceled, got %v", err)
+	}
+	if _, err := repo.GetRandom(ctx, domain.QuoteFilter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected GetRandom to fail with context.Canceled, got %v", err)
+	}
+}
+
+func testRepositoryCreateBatch(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	if err := repo.Create(t.Context(), &domain.Quote{ID: "batch-existing", Text: "Text", Author: "Author"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	batch := func(ids ...string) []*domain.Quote {
+		quotes := make([]*domain.Quote, len(ids))
+		for i, id := range ids {
+			quotes[i] = &domain.Quote{ID: id, Text: fmt.Sprintf("Batch %d", i), Author: "Author", Tags: []string{"Batch"}}
+		}
+		return quotes
+	}
+
+	rowErrs, err := repo.CreateBatch(t.Context(), batch("batch-1", "batch-existing", "batch-2"), true)
+	if err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+	if len(rowErrs) != 3 || rowErrs[0] != nil || !errors.Is(rowErrs[1], repository.ErrAlreadyExists) || rowErrs[2] != nil {
+		t.Fatalf("Unexpected row errors for atomic batch: %v", rowErrs)
+	}
+	if _, err := repo.GetByID(t.Context(), "batch-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected atomic batch to be rolled back, got %v", err)
+	}
+
+	quotes := batch("batch-1", "batch-existing", "batch-1", "batch-2")
+	rowErrs, err = repo.CreateBatch(t.Context(), quotes, false)
+	if err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+	if rowErrs[0] != nil || !errors.Is(rowErrs[1], repository.ErrAlreadyExists) ||
+		!errors.Is(rowErrs[2], repository.ErrAlreadyExists) || rowErrs[3] != nil {
+		t.Fatalf("Unexpected row errors for best-effort batch: %v", rowErrs)
+	}
+	for _, id := range []string{"batch-1", "batch-2"} {
+		quote, err := repo.GetByID(t.Context(), id)
+		if err != nil {
+			t.Fatalf("Expected %s to be stored: %v", id, err)
+		}
+		if quote.Version != 1 || len(quote.Tags) != 1 || quote.Tags[0] != "batch" {
+			t.Errorf("Unexpected stored quote: %+v", quote)
+		}
+	}
+	if quotes[0].Version != 1 || quotes[1].Version != 0 {
+		t.Errorf("Expected versions to be set only on stored quotes, got %d and %d", quotes[0].Version, quotes[1].Version)
+	}
+
+	all, err := repo.GetAll(t.Context())
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected 3 quotes, got %d", len(all))
+	}
+}
+
+func testRepositoryIterate(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	// More than one internal page, so iteration has to follow cursors.
+	const total = 1203
+	quotes := make([]*domain.Quote, total)
+	for i := range quotes {
+		author := "Author"
+		if i%3 == 0 {
+			author = "Other"
+		}
+		quotes[i] = &domain.Quote{ID: fmt.Sprintf("iter-%05d", i), Text: fmt.Sprintf("Text %d", i), Author: author}
+	}
+	if _, err := repo.CreateBatch(t.Context(), quotes, true); err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+
+	count := 0
+	previous := ""
+	for quote, err := range repo.Iterate(t.Context(), domain.QuoteFilter{}) {
+		if err != nil {
+			t.Fatalf("Iterate failed: %v", err)
+		}
+		if quote.ID <= previous {
+			t.Fatalf("Expected IDs in ascending order, got %s after %s", quote.ID, previous)
+		}
+		previous = quote.ID
+		count++
+	}
+	if count != total {
+		t.Errorf("Expected %d quotes, got %d", total, count)
+	}
+
+	count = 0
+	for quote, err := range repo.Iterate(t.Context(), domain.QuoteFilter{Author: "Other"}) {
+		if err != nil {
+			t.Fatalf("Iterate failed: %v", err)
+		}
+		if quote.Author != "Other" {
+			t.Errorf("Expected only quotes by Other, got %s", quote.Author)
+		}
+		count++
+	}
+	if count != (total+2)/3 {
+		t.Errorf("Expected %d filtered quotes, got %d", (total+2)/3, count)
+	}
+
+	count = 0
+	for range repo.Iterate(t.Context(), domain.QuoteFilter{}) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	var iterErr error
+	for _, err := range repo.Iterate(ctx, domain.QuoteFilter{}) {
+		iterErr = err
+	}
+	if !errors.Is(iterErr, context.Canceled) {
+		t.Errorf("Expected context.Canceled from Iterate, got %v", iterErr)
+	}
+}
+
+func testRepositoryDuplicates(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	original := &domain.Quote{ID: "dup-1", Text: "Don't panic.", Author: "Douglas Adams"}
+	if err := repo.Create(t.Context(), original); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	variants := []string{"DON’T  PANIC", "don't panic!!!", "Ｄｏｎ'ｔ panic"}
+	for _, text := range variants {
+		err := repo.Create(t.Context(), &domain.Quote{ID: "dup-2", Text: text, Author: "douglas  adams"})
+		var duplicate *repository.DuplicateError
+		if !errors.As(err, &duplicate) || !errors.Is(err, repository.ErrDuplicate) {
+			t.Fatalf("Expected %q to be a duplicate, got %v", text, err)
+		}
+		if duplicate.ExistingID != "dup-1" || duplicate.Similarity != 1 {
+			t.Errorf("Unexpected duplicate error: %+v", duplicate)
+		}
+	}
+
+	if err := repo.Create(t.Context(), &domain.Quote{ID: "dup-2", Text: "Don't panic.", Author: "Someone Else"}); err != nil {
+		t.Fatalf("Expected the same text by another author to be accepted, got %v", err)
+	}
+	if err := repo.Update(t.Context(), &domain.Quote{ID: "dup-2", Text: "Don't panic", Author: "Douglas Adams"}); !errors.Is(err, repository.ErrDuplicate) {
+		t.Errorf("Expected update into a duplicate to fail, got %v", err)
+	}
+	if err := repo.Update(t.Context(), &domain.Quote{ID: "dup-1", Text: "DON'T PANIC.", Author: "Douglas Adams"}); err != nil {
+		t.Errorf("Expected a quote to be updated to a variant of itself, got %v", err)
+	}
+
+	rowErrs, err := repo.CreateBatch(t.Context(), []*domain.Quote{
+		{ID: "dup-3", Text: "don't panic", Author: "Douglas Adams"},
+		{ID: "dup-4", Text: "Mostly harmless", Author: "Douglas Adams"},
+	}, false)
+	if err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+	if !errors.Is(rowErrs[0], repository.ErrDuplicate) || rowErrs[1] != nil {
+		t.Errorf("Unexpected row errors: %v", rowErrs)
+	}
+
+	if err := repo.Delete(t.Context(), "dup-1", 0); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.Create(t.Context(), &domain.Quote{ID: "dup-5", Text: "Don't panic", Author: "Douglas Adams"}); err != nil {
+		t.Errorf("Expected the text to be free again after delete, got %v", err)
+	}
+}
+
+func testRepositoryAuthors(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	first := &domain.Quote{ID: "author-1", Text: "Yesterday is history", Author: "Панда По"}
+	second := &domain.Quote{ID: "author-2", Text: "Tomorrow is a mystery", Author: "панда  по"}
+	for _, quote := range []*domain.Quote{first, second} {
+		if err := repo.Create(t.Context(), quote); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if first.AuthorID == "" || second.AuthorID != first.AuthorID || second.Author != "Панда По" {
+		t.Fatalf("Expected both quotes to share the canonical author, got %+v and %+v", first, second)
+	}
+	stored, err := repo.GetByID(t.Context(), "author-2")
+	if err != nil || stored.AuthorID != first.AuthorID || stored.Author != "Панда По" {
+		t.Errorf("Expected stored quote to reference author %s, got %+v, %v", first.AuthorID, stored, err)
+	}
+
+	shifu := &domain.Author{Name: "Мастер Шифу", Bio: "Учитель", Aliases: []string{"Shifu", "шифу", "SHIFU"}}
+	if err := repo.CreateAuthor(t.Context(), shifu); err != nil {
+		t.Fatalf("CreateAuthor failed: %v", err)
+	}
+	if shifu.ID == "" || len(shifu.Aliases) != 2 {
+		t.Errorf("Expected an ID and aliases deduplicated by key, got %+v", shifu)
+	}
+	err = repo.CreateAuthor(t.Context(), &domain.Author{Name: "Someone", Aliases: []string{"shifu"}})
+	var exists *repository.AuthorExistsError
+	if !errors.As(err, &exists) || exists.ExistingID != shifu.ID || !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Errorf("Expected a taken alias to be rejected, got %v", err)
+	}
+
+	byAlias := &domain.Quote{ID: "author-3", Text: "There is no secret ingredient", Author: "shifu"}
+	if err := repo.Create(t.Context(), byAlias); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if byAlias.AuthorID != shifu.ID || byAlias.Author != "Мастер Шифу" {
+		t.Errorf("Expected the alias to resolve to %s, got %+v", shifu.ID, byAlias)
+	}
+
+	authors, err := repo.ListAuthors(t.Context())
+	if err != nil || len(authors) != 2 {
+		t.Fatalf("Expected two authors, got %+v, %v", authors, err)
+	}
+	if authors[0].ID != shifu.ID || authors[0].QuoteCount != 1 || authors[1].QuoteCount != 2 {
+		t.Errorf("Unexpected authors: %+v", authors)
+	}
+
+	page, err := repo.List(t.Context(), domain.ListQuery{
+		QuoteFilter: domain.QuoteFilter{AuthorID: first.AuthorID},
+		SortBy:      domain.SortByID,
+		Limit:       10,
+	})
+	if err != nil || len(page.Quotes) != 2 {
+		t.Errorf("Expected two quotes by author, got %+v, %v", page, err)
+	}
+
+	// A quote that merging turns into a duplicate is kept.
+	repeated := &domain.Quote{ID: "author-4", Text: "Yesterday is history", Author: "Po"}
+	if err := repo.Create(t.Context(), repeated); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	merged, err := repo.MergeAuthors(t.Context(), first.AuthorID, repeated.AuthorID)
+	if err != nil {
+		t.Fatalf("MergeAuthors failed: %v", err)
+	}
+	if merged.QuoteCount != 3 || len(merged.Aliases) != 1 || merged.Aliases[0] != "Po" {
+		t.Errorf("Unexpected merged author: %+v", merged)
+	}
+	moved, err := repo.GetByID(t.Context(), "author-4")
+	if err != nil || moved.AuthorID != first.AuthorID || moved.Author != "Панда По" || moved.Version != 2 {
+		t.Errorf("Expected moved quote under the canonical name with a new version, got %+v, %v", moved, err)
+	}
+	if _, err := repo.GetAuthor(t.Context(), repeated.AuthorID); !errors.Is(err, repository.ErrAuthorNotFound) || !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected merged author to be gone, got %v", err)
+	}
+	if _, err := repo.MergeAuthors(t.Context(), first.AuthorID, "missing"); !errors.Is(err, repository.ErrAuthorNotFound) {
+		t.Errorf("Expected merging a missing author to fail, got %v", err)
+	}
+
+	byOldAlias := &domain.Quote{ID: "author-5", Text: "Skadoosh", Author: "po"}
+	if err := repo.Create(t.Context(), byOldAlias); err != nil || byOldAlias.AuthorID != first.AuthorID {
+		t.Errorf("Expected the merged name to resolve to %s, got %+v, %v", first.AuthorID, byOldAlias, err)
+	}
+	if err := repo.Delete(t.Context(), "author-4", 0); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	err = repo.Create(t.Context(), &domain.Quote{ID: "author-6", Text: "Yesterday is history", Author: "Po"})
+	if !errors.Is(err, repository.ErrDuplicate) {
+		t.Errorf("Expected the original quote to keep its fingerprint, got %v", err)
+	}
+
+	// Names without letters or digits still tell authors apart.
+	fox := &domain.Quote{ID: "author-7", Text: "Skadoosh", Author: "🦊"}
+	panda := &domain.Quote{ID: "author-8", Text: "Skadoosh", Author: "🐼"}
+	for _, quote := range []*domain.Quote{fox, panda} {
+		if err := repo.Create(t.Context(), quote); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if fox.AuthorID == "" || panda.AuthorID == fox.AuthorID || panda.Author != "🐼" {
+		t.Errorf("Expected symbol-only names to get their own authors, got %+v and %+v", fox, panda)
+	}
+}
+
+func testRepositoryAuthorMatch(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+
+	if err := repo.CreateAuthor(t.Context(), &domain.Author{Name: "Мастер Шифу", Aliases: []string{"Shifu"}}); err != nil {
+		t.Fatalf("CreateAuthor failed: %v", err)
+	}
+	for i, author := range []string{"Panda Po", "Pandora", "Steve Jobs", "Мастер Шифу"} {
+		quote := &domain.Quote{ID: fmt.Sprintf("match-%d", i+1), Text: "Text " + author, Author: author}
+		if err := repo.Create(t.Context(), quote); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		author string
+		match  domain.AuthorMatch
+		want   string
+	}{
+		{"panda po", domain.AuthorMatchExact, ""},
+		{"Panda Po", domain.AuthorMatchExact, "match-1"},
+		{"PANDA  PO", domain.AuthorMatchCaseInsensitive, "match-1"},
+		{"shifu", domain.AuthorMatchCaseInsensitive, "match-4"},
+		{"pand", domain.AuthorMatchPrefix, "match-1,match-2"},
+		{"мастер", domain.AuthorMatchPrefix, "match-4"},
+		{"Steve Jbos", domain.AuthorMatchFuzzy, "match-3"},
+		{"panda", domain.AuthorMatchFuzzy, ""},
+		{"shfu", domain.AuthorMatchFuzzy, "match-4"},
+	}
+	for _, tt := range tests {
+		page, err := repo.List(t.Context(), domain.ListQuery{
+			QuoteFilter: domain.QuoteFilter{Author: tt.author, AuthorMatch: tt.match},
+			SortBy:      domain.SortByID,
+			Limit:       10,
+		})
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		var ids []string
+		for _, quote := range page.Quotes {
+			ids = append(ids, quote.ID)
+		}
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("Author %q with %s: expected [%s], got [%s]", tt.author, tt.match, tt.want, got)
+		}
+	}
+
+	quote, err := repo.GetRandom(t.Context(), domain.QuoteFilter{Author: "steve", AuthorMatch: domain.AuthorMatchPrefix})
+	if err != nil || quote.ID != "match-3" {
+		t.Errorf("Expected random quote match-3, got %+v, %v", quote, err)
+	}
+}
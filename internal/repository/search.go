@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"test-task-scout-go/internal/domain"
+)
+
+const (
+	highlightOpen   = "<mark>"
+	highlightClose  = "</mark>"
+	snippetOpen     = "\ue000"
+	snippetClose    = "\ue001"
+	snippetEllipsis = "…"
+	snippetTokens   = 12
+
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+type tokenSpan struct {
+	term       string
+	start, end int
+}
+
+// tokenSpans splits s into lowercase letter/digit runs, keeping byte offsets so
+// that matches can be highlighted in the original text. It mirrors the
+// unicode61 tokenizer used by the SQLite FTS5 index.
+func tokenSpans(s string) []tokenSpan {
+	var spans []tokenSpan
+	start := -1
+	for i, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			spans = append(spans, tokenSpan{term: strings.ToLower(s[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, tokenSpan{term: strings.ToLower(s[start:]), start: start, end: len(s)})
+	}
+	return spans
+}
+
+func tokenize(s string) []string {
+	spans := tokenSpans(s)
+	terms := make([]string, len(spans))
+	for i, span := range spans {
+		terms[i] = span.term
+	}
+	return terms
+}
+
+func queryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, term := range tokenize(query) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// highlightSnippet HTML-escapes a snippet the database marked with
+// snippetOpen and snippetClose, and only then turns the marks into <mark>
+// tags, so that markup in a quote cannot reach the client unescaped.
+func highlightSnippet(marked string) string {
+	return strings.NewReplacer(snippetOpen, highlightOpen, snippetClose, highlightClose).Replace(html.EscapeString(marked))
+}
+
+// snippet returns a window of at most snippetTokens tokens around the densest
+// cluster of matched terms, with the matches wrapped in <mark> tags and the
+// rest of the text HTML-escaped.
+func snippet(text string, terms []string) string {
+	spans := tokenSpans(text)
+	if len(spans) == 0 {
+		return html.EscapeString(text)
+	}
+
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	bestStart, bestHits := 0, -1
+	for start := 0; start < len(spans); start++ {
+		hits := 0
+		for i := start; i < len(spans) && i < start+snippetTokens; i++ {
+			if wanted[spans[i].term] {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			bestStart, bestHits = start, hits
+		}
+		if start+snippetTokens >= len(spans) {
+			break
+		}
+	}
+	bestEnd := bestStart + snippetTokens
+	if bestEnd > len(spans) {
+		bestEnd = len(spans)
+	}
+
+	from, to := spans[bestStart].start, spans[bestEnd-1].end
+	if bestStart == 0 {
+		from = 0
+	}
+	if bestEnd == len(spans) {
+		to = len(text)
+	}
+
+	var b strings.Builder
+	if bestStart > 0 {
+		b.WriteString(snippetEllipsis)
+	}
+	pos := from
+	for _, span := range spans[bestStart:bestEnd] {
+		if !wanted[span.term] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:span.start]))
+		b.WriteString(highlightOpen)
+		b.WriteString(html.EscapeString(text[span.start:span.end]))
+		b.WriteString(highlightClose)
+		pos = span.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if bestEnd < len(spans) {
+		b.WriteString(snippetEllipsis)
+	}
+	return b.String()
+}
+
+type scoredDoc struct {
+	id    string
+	score float64
+}
+
+// searchIndex is a tokenized inverted index scored with Okapi BM25, the same
+// ranking function FTS5 uses, so both backends order results alike.
+type searchIndex struct {
+	postings    map[string]map[string]int
+	docTerms    map[string]map[string]int
+	docLengths  map[string]int
+	totalLength int
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings:   make(map[string]map[string]int),
+		docTerms:   make(map[string]map[string]int),
+		docLengths: make(map[string]int),
+	}
+}
+
+func (idx *searchIndex) add(quote domain.Quote) {
+	idx.remove(quote.ID)
+
+	terms := append(tokenize(quote.Text), tokenize(quote.Author)...)
+	freqs := make(map[string]int)
+	for _, term := range terms {
+		freqs[term]++
+	}
+
+	for term, freq := range freqs {
+		docs, ok := idx.postings[term]
+		if !ok {
+			docs = make(map[string]int)
+			idx.postings[term] = docs
+		}
+		docs[quote.ID] = freq
+	}
+	idx.docTerms[quote.ID] = freqs
+	idx.docLengths[quote.ID] = len(terms)
+	idx.totalLength += len(terms)
+}
+
+func (idx *searchIndex) remove(id string) {
+	freqs, ok := idx.docTerms[id]
+	if !ok {
+		return
+	}
+	for term := range freqs {
+		docs := idx.postings[term]
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	idx.totalLength -= idx.docLengths[id]
+	delete(idx.docTerms, id)
+	delete(idx.docLengths, id)
+}
+
+// search returns documents containing every term, best match first.
+func (idx *searchIndex) search(terms []string, limit int) []scoredDoc {
+	if len(terms) == 0 || len(idx.docLengths) == 0 {
+		return nil
+	}
+
+	// Start from the rarest term so the intersection stays small.
+	sorted := append([]string(nil), terms...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(idx.postings[sorted[i]]) < len(idx.postings[sorted[j]])
+	})
+
+	n := float64(len(idx.docLengths))
+	avgLength := float64(idx.totalLength) / n
+
+	var results []scoredDoc
+	for id := range idx.postings[sorted[0]] {
+		score := 0.0
+		matched := true
+		for _, term := range sorted {
+			docs := idx.postings[term]
+			freq, ok := docs[id]
+			if !ok {
+				matched = false
+				break
+			}
+			df := float64(len(docs))
+			idf := math.Log((n-df+0.5)/(df+0.5) + 1)
+			tf := float64(freq)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(idx.docLengths[id])/avgLength)
+			score += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+		if matched {
+			results = append(results, scoredDoc{id: id, score: score})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].id < results[j].id
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// ftsMatchExpression turns free-form user input into an FTS5 query that
+// requires every term, quoting each one so operators in the input are inert.
+func ftsMatchExpression(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + term + `"`
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// memorySnapshot is the complete state of an InMemoryRepository.
+type memorySnapshot struct {
+	Quotes    []domain.Quote    `json:"quotes"`
+	Authors   []domain.Author   `json:"authors"`
+	DailyPins map[string]string `json:"daily_pins"`
+}
+
+// snapshot copies the state of the repository, quotes and authors in ID order.
+func (r *InMemoryRepository) snapshot() memorySnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.snapshotLocked()
+}
+
+// snapshotLocked is snapshot for a caller that holds the lock.
+func (r *InMemoryRepository) snapshotLocked() memorySnapshot {
+	s := memorySnapshot{
+		Quotes:    make([]domain.Quote, 0, len(r.quotes)),
+		Authors:   make([]domain.Author, 0, len(r.authors)),
+		DailyPins: maps.Clone(r.pins),
+	}
+	for _, quote := range r.quotes {
+		quote.Tags = append([]string{}, quote.Tags...)
+		s.Quotes = append(s.Quotes, quote)
+	}
+	for _, author := range r.authors {
+		s.Authors = append(s.Authors, withQuoteCount(author, nil))
+	}
+	sort.Slice(s.Quotes, func(i, j int) bool { return s.Quotes[i].ID < s.Quotes[j].ID })
+	sort.Slice(s.Authors, func(i, j int) bool { return s.Authors[i].ID < s.Authors[j].ID })
+	return s
+}
+
+// restore loads a snapshot into an empty repository. Quotes keep their
+// versions and timestamps. A quote whose author is not in the snapshot, as in
+// a file edited by hand, is linked to an author by name like a new quote.
+func (r *InMemoryRepository) restore(s memorySnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, author := range s.Authors {
+		if _, exists := r.authors[author.ID]; exists {
+			return fmt.Errorf("duplicate author ID %s", author.ID)
+		}
+		r.insertAuthor(&author)
+	}
+	for _, quote := range s.Quotes {
+		if _, exists := r.quotes[quote.ID]; exists {
+			return fmt.Errorf("duplicate quote ID %s", quote.ID)
+		}
+		if author, exists := r.authors[quote.AuthorID]; exists {
+			quote.Author = author.Name
+		} else {
+			r.resolveAuthor(&quote)
+		}
+		if quote.Version == 0 {
+			quote.Version = 1
+		}
+		quote.Tags = domain.NormalizeTags(quote.Tags)
+		stampCreated(&quote)
+		r.store(quote)
+	}
+	maps.Copy(r.pins, s.DailyPins)
+	return nil
+}
@@ -1,20 +1,39 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
+	"log"
+	"sort"
+	"strings"
 	"test-task-scout-go/internal/domain"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 type SQLiteRepository struct {
-	db *sql.DB
+	db         *sql.DB
+	ftsEnabled bool
 }
 
-func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+// sqliteDriverName is go-sqlite3 with the application's SQL functions:
+// edit_distance(a, b) computes domain.EditDistance for fuzzy author matching.
+const sqliteDriverName = "sqlite3_quotes"
+
+func init() {
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("edit_distance", domain.EditDistance, true)
+		},
+	})
+}
+
+func OpenSQLiteDB(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open(sqliteDriverName, dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -24,6 +43,15 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	return db, nil
+}
+
+func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
+	db, err := OpenSQLiteDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
 	repo := &SQLiteRepository{db: db}
 
 	if err = repo.initDB(); err != nil {
@@ -35,37 +63,71 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 }
 
 func (r *SQLiteRepository) initDB() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS quotes (
-		id TEXT PRIMARY KEY,
-		text TEXT NOT NULL,
-		author TEXT NOT NULL
-	);`
-	_, err := r.db.Exec(query)
-	return err
-}
-
-func (r *SQLiteRepository) Close() error {
-	return r.db.Close()
+	migrator, err := NewSQLiteMigrator(r.db)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up()
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		log.Printf("Applied %d database migration(s)", applied)
+	}
+	if err := r.backfillAuthors(); err != nil {
+		return err
+	}
+	if err := r.backfillFingerprints(); err != nil {
+		return err
+	}
+	return r.initSearch()
 }
 
-func (r *SQLiteRepository) Create(quote *domain.Quote) error {
-	query := "INSERT INTO quotes (id, text, author) VALUES (?, ?, ?)"
-	_, err := r.db.Exec(query, quote.ID, quote.Text, quote.Author)
+// backfillFingerprints fills in fingerprints for quotes stored before they
+// were introduced, oldest first. A quote that duplicates an earlier one keeps
+// a NULL fingerprint: duplicates already stored are left alone, and only new
+// ones are refused.
+func (r *SQLiteRepository) backfillFingerprints() error {
+	pending, err := queryContents(context.Background(), r.db, "SELECT id, text, author FROM quotes WHERE fingerprint IS NULL ORDER BY created_at, id")
 	if err != nil {
-		if err.Error() == "UNIQUE constraint failed: quotes.id" {
-			return errors.New("quote with this ID already exists")
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	duplicates := 0
+	err = r.withTx(context.Background(), func(tx *sql.Tx) error {
+		for _, quote := range pending {
+			_, err := tx.Exec("UPDATE quotes SET fingerprint = ? WHERE id = ?", domain.Fingerprint(quote.Text, quote.Author), quote.ID)
+			if isConstraintError(err, constraintUnique) {
+				duplicates++
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to set fingerprint for quote %s: %w", quote.ID, err)
+			}
 		}
-		return fmt.Errorf("failed to create quote: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if duplicates > 0 {
+		log.Printf("%d stored quote(s) duplicate earlier ones and are not checked for duplicates", duplicates)
 	}
 	return nil
 }
 
-func (r *SQLiteRepository) GetAll() ([]domain.Quote, error) {
-	query := "SELECT id, text, author FROM quotes"
-	rows, err := r.db.Query(query)
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// queryContents reads just the ID, text and author of the selected quotes.
+func queryContents(ctx context.Context, db queryer, query string, args ...any) ([]domain.Quote, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all quotes: %w", err)
+		return nil, fmt.Errorf("failed to query quotes: %w", err)
 	}
 	defer rows.Close()
 
@@ -77,42 +139,118 @@ func (r *SQLiteRepository) GetAll() ([]domain.Quote, error) {
 		}
 		quotes = append(quotes, quote)
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error during rows iteration: %w", err)
 	}
-
 	return quotes, nil
 }
 
-func (r *SQLiteRepository) GetByID(id string) (*domain.Quote, error) {
-	query := "SELECT id, text, author FROM quotes WHERE id = ?"
-	row := r.db.QueryRow(query, id)
+// initSearch sets up the FTS5 index kept in sync with quotes by triggers.
+// go-sqlite3 only ships FTS5 when built with the sqlite_fts5 tag; without it
+// Search falls back to scanning the table with the in-process index. That is
+// why the index lives outside the versioned migrations: it is derived data that
+// may or may not be available depending on the build.
+func (r *SQLiteRepository) initSearch() error {
+	var exists int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'quotes_fts'").Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check search index: %w", err)
+	}
 
-	var quote domain.Quote
-	err := row.Scan(&quote.ID, &quote.Text, &quote.Author)
+	_, err = r.db.Exec(`
+	CREATE VIRTUAL TABLE IF NOT EXISTS quotes_fts USING fts5(
+		id UNINDEXED,
+		text,
+		author,
+		tokenize = 'unicode61 remove_diacritics 0'
+	);`)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("quote not found")
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			log.Println("SQLite is built without FTS5, full-text search will scan the quotes table")
+			return nil
 		}
-		return nil, fmt.Errorf("failed to get quote by ID: %w", err)
+		return fmt.Errorf("failed to create search index: %w", err)
 	}
 
-	return &quote, nil
+	triggers := `
+	CREATE TRIGGER IF NOT EXISTS quotes_fts_insert AFTER INSERT ON quotes BEGIN
+		INSERT INTO quotes_fts (id, text, author) VALUES (new.id, new.text, new.author);
+	END;
+	CREATE TRIGGER IF NOT EXISTS quotes_fts_delete AFTER DELETE ON quotes BEGIN
+		DELETE FROM quotes_fts WHERE id = old.id;
+	END;
+	CREATE TRIGGER IF NOT EXISTS quotes_fts_update AFTER UPDATE ON quotes BEGIN
+		DELETE FROM quotes_fts WHERE id = old.id;
+		INSERT INTO quotes_fts (id, text, author) VALUES (new.id, new.text, new.author);
+	END;`
+	if _, err := r.db.Exec(triggers); err != nil {
+		return fmt.Errorf("failed to create search index triggers: %w", err)
+	}
+
+	if exists == 0 {
+		_, err := r.db.Exec("INSERT INTO quotes_fts (id, text, author) SELECT id, text, author FROM quotes")
+		if err != nil {
+			return fmt.Errorf("failed to populate search index: %w", err)
+		}
+	}
+
+	r.ftsEnabled = true
+	return nil
+}
+
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteRepository) CheckHealth(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// quoteColumns selects a quote from "quotes q" together with its tags, which
+// are joined with tagSeparator; tags cannot contain that character.
+const quoteColumns = `q.id, q.text, q.author, COALESCE(q.author_id, ''), q.version, q.created_at, q.updated_at, q.created_by,
+	(SELECT group_concat(t.name, char(31)) FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id WHERE qt.quote_id = q.id)`
+
+const tagSeparator = "\x1f"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanQuote(scanner rowScanner, extra ...any) (domain.Quote, error) {
+	var quote domain.Quote
+	var createdAt, updatedAt string
+	var tags sql.NullString
+	dest := append([]any{&quote.ID, &quote.Text, &quote.Author, &quote.AuthorID, &quote.Version, &createdAt, &updatedAt, &quote.CreatedBy, &tags}, extra...)
+	if err := scanner.Scan(dest...); err != nil {
+		return quote, err
+	}
+	quote.Tags = []string{}
+	if tags.Valid {
+		quote.Tags = strings.Split(tags.String, tagSeparator)
+		sort.Strings(quote.Tags)
+	}
+	var err error
+	if quote.CreatedAt, err = time.Parse(timestampLayout, createdAt); err != nil {
+		return quote, fmt.Errorf("invalid created_at for quote %s: %w", quote.ID, err)
+	}
+	if quote.UpdatedAt, err = time.Parse(timestampLayout, updatedAt); err != nil {
+		return quote, fmt.Errorf("invalid updated_at for quote %s: %w", quote.ID, err)
+	}
+	return quote, nil
 }
 
-func (r *SQLiteRepository) GetByAuthor(author string) ([]domain.Quote, error) {
-	query := "SELECT id, text, author FROM quotes WHERE author = ?"
-	rows, err := r.db.Query(query, author)
+func (r *SQLiteRepository) queryQuotes(ctx context.Context, query string, args ...any) ([]domain.Quote, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get quotes by author: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
 	var quotes []domain.Quote
 	for rows.Next() {
-		var quote domain.Quote
-		if err := rows.Scan(&quote.ID, &quote.Text, &quote.Author); err != nil {
+		quote, err := scanQuote(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan quote row: %w", err)
 		}
 		quotes = append(quotes, quote)
@@ -125,37 +263,559 @@ func (r *SQLiteRepository) GetByAuthor(author string) ([]domain.Quote, error) {
 	return quotes, nil
 }
 
-func (r *SQLiteRepository) Delete(id string) error {
-	query := "DELETE FROM quotes WHERE id = ?"
-	result, err := r.db.Exec(query, id)
+// withTx runs fn in a transaction, committing only if fn succeeds.
+func (r *SQLiteRepository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete quote: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// setQuoteTags replaces the tags attached to a quote.
+func setQuoteTags(ctx context.Context, tx *sql.Tx, quoteID string, tags []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM quote_tags WHERE quote_id = ?", quoteID); err != nil {
+		return fmt.Errorf("failed to clear quote tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO tags (name) VALUES (?) ON CONFLICT (name) DO NOTHING", tag); err != nil {
+			return fmt.Errorf("failed to create tag: %w", err)
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO quote_tags (quote_id, tag_id) SELECT ?, id FROM tags WHERE name = ?", quoteID, tag)
+		if err != nil {
+			return fmt.Errorf("failed to tag quote: %w", err)
+		}
+	}
+	return nil
+}
+
+// duplicateOf looks up the quote that already has fingerprint, after a write
+// failed on the unique fingerprint index.
+func duplicateOf(ctx context.Context, tx *sql.Tx, fingerprint string) error {
+	var id string
+	err := tx.QueryRowContext(ctx, "SELECT id FROM quotes WHERE fingerprint = ?", fingerprint).Scan(&id)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to find duplicate quote: %w", err)
 	}
+	return &DuplicateError{ExistingID: id, Similarity: 1}
+}
 
-	if rowsAffected == 0 {
-		return errors.New("quote not found")
+func insertQuote(ctx context.Context, tx *sql.Tx, quote *domain.Quote) error {
+	stampCreated(quote)
+	quote.Tags = domain.NormalizeTags(quote.Tags)
+	if err := resolveAuthor(ctx, tx, quote); err != nil {
+		return err
+	}
+	fingerprint := domain.Fingerprint(quote.Text, quote.Author)
+	query := `
+	INSERT INTO quotes (id, text, author, author_id, version, created_at, updated_at, created_by, fingerprint)
+	VALUES (?, ?, ?, ?, 1, ?, ?, ?, ?)`
+	_, err := tx.ExecContext(ctx, query, quote.ID, quote.Text, quote.Author, quote.AuthorID,
+		formatTimestamp(quote.CreatedAt), formatTimestamp(quote.UpdatedAt), quote.CreatedBy, fingerprint)
+	if err != nil {
+		if isConstraintError(err, constraintPrimaryKey) {
+			return ErrAlreadyExists
+		}
+		if isConstraintError(err, constraintUnique) {
+			return duplicateOf(ctx, tx, fingerprint)
+		}
+		return fmt.Errorf("failed to create quote: %w", err)
 	}
+	return setQuoteTags(ctx, tx, quote.ID, quote.Tags)
+}
 
+func (r *SQLiteRepository) Create(ctx context.Context, quote *domain.Quote) error {
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		return insertQuote(ctx, tx, quote)
+	})
+	if err != nil {
+		return err
+	}
+	quote.Version = 1
 	return nil
 }
 
-func (r *SQLiteRepository) GetRandom() (*domain.Quote, error) {
-	query := "SELECT id, text, author FROM quotes ORDER BY RANDOM() LIMIT 1"
-	row := r.db.QueryRow(query)
+// errBatchRejected rolls back an atomic batch in which some quote failed.
+var errBatchRejected = errors.New("batch rejected")
 
-	var quote domain.Quote
-	err := row.Scan(&quote.ID, &quote.Text, &quote.Author)
+// CreateBatch runs the whole batch in one transaction. In best-effort mode
+// each quote gets its own savepoint, so a failed quote is undone without
+// losing the ones before it. Only per-quote conflicts, a taken ID or a
+// duplicate, are reported per row; any other error aborts the batch.
+func (r *SQLiteRepository) CreateBatch(ctx context.Context, quotes []*domain.Quote, atomic bool) ([]error, error) {
+	rowErrs := make([]error, len(quotes))
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		failed := false
+		for i, quote := range quotes {
+			if !atomic {
+				if _, err := tx.ExecContext(ctx, "SAVEPOINT batch_row"); err != nil {
+					return fmt.Errorf("failed to create savepoint: %w", err)
+				}
+			}
+			err := insertQuote(ctx, tx, quote)
+			if err != nil && !errors.Is(err, ErrAlreadyExists) && !errors.Is(err, ErrDuplicate) {
+				return err
+			}
+			if err != nil {
+				rowErrs[i] = err
+				failed = true
+				if !atomic {
+					if _, err := tx.ExecContext(ctx, "ROLLBACK TO batch_row"); err != nil {
+						return fmt.Errorf("failed to roll back to savepoint: %w", err)
+					}
+				}
+			}
+			if !atomic {
+				if _, err := tx.ExecContext(ctx, "RELEASE batch_row"); err != nil {
+					return fmt.Errorf("failed to release savepoint: %w", err)
+				}
+			}
+		}
+		if atomic && failed {
+			return errBatchRejected
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRejected) {
+		return nil, err
+	}
+	if err == nil {
+		for i, quote := range quotes {
+			if rowErrs[i] == nil {
+				quote.Version = 1
+			}
+		}
+	}
+	return rowErrs, nil
+}
+
+func (r *SQLiteRepository) GetAll(ctx context.Context) ([]domain.Quote, error) {
+	quotes, err := r.queryQuotes(ctx, "SELECT "+quoteColumns+" FROM quotes q")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all quotes: %w", err)
+	}
+	return quotes, nil
+}
+
+func (r *SQLiteRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM quotes").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count quotes: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SQLiteRepository) GetByID(ctx context.Context, id string) (*domain.Quote, error) {
+	query := "SELECT " + quoteColumns + " FROM quotes q WHERE q.id = ?"
+	quote, err := scanQuote(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get quote by ID: %w", err)
+	}
+
+	return &quote, nil
+}
+
+func (r *SQLiteRepository) GetByAuthor(ctx context.Context, author string) ([]domain.Quote, error) {
+	quotes, err := r.queryQuotes(ctx, "SELECT "+quoteColumns+" FROM quotes q WHERE q.author = ?", author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quotes by author: %w", err)
+	}
+	return quotes, nil
+}
+
+// Update stores the quote and bumps its version. A non-zero quote.Version must
+// match the stored one, otherwise the write is rejected as a conflict. Creation
+// metadata is never overwritten; the stored values are copied back into quote.
+func (r *SQLiteRepository) Update(ctx context.Context, quote *domain.Quote) error {
+	stampUpdated(quote)
+	quote.Tags = domain.NormalizeTags(quote.Tags)
+	var version int64
+	var createdAt, createdBy string
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := resolveAuthor(ctx, tx, quote); err != nil {
+			return err
+		}
+		fingerprint := domain.Fingerprint(quote.Text, quote.Author)
+		query := `
+		UPDATE quotes SET text = ?, author = ?, author_id = ?, fingerprint = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND (? = 0 OR version = ?)
+		RETURNING version, created_at, created_by`
+		err := tx.QueryRowContext(ctx, query, quote.Text, quote.Author, quote.AuthorID, fingerprint, formatTimestamp(quote.UpdatedAt),
+			quote.ID, quote.Version, quote.Version).Scan(&version, &createdAt, &createdBy)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return missingOrConflict(ctx, tx, quote.ID)
+			}
+			if isConstraintError(err, constraintUnique) {
+				return duplicateOf(ctx, tx, fingerprint)
+			}
+			return fmt.Errorf("failed to update quote: %w", err)
+		}
+		return setQuoteTags(ctx, tx, quote.ID, quote.Tags)
+	})
+	if err != nil {
+		return err
+	}
+
+	quote.Version = version
+	quote.CreatedBy = createdBy
+	if quote.CreatedAt, err = time.Parse(timestampLayout, createdAt); err != nil {
+		return fmt.Errorf("invalid created_at for quote %s: %w", quote.ID, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) Delete(ctx context.Context, id string, version int64) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		query := "DELETE FROM quotes WHERE id = ? AND (? = 0 OR version = ?)"
+		result, err := tx.ExecContext(ctx, query, id, version, version)
+		if err != nil {
+			return fmt.Errorf("failed to delete quote: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return missingOrConflict(ctx, tx, id)
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM quote_tags WHERE quote_id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete quote tags: %w", err)
+		}
+		return nil
+	})
+}
+
+// missingOrConflict explains why a conditional write touched no rows.
+func missingOrConflict(ctx context.Context, tx *sql.Tx, id string) error {
+	var exists int
+	err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM quotes WHERE id = ?", id).Scan(&exists)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("no quotes available")
+		return fmt.Errorf("failed to check quote existence: %w", err)
+	}
+	if exists == 0 {
+		return ErrNotFound
+	}
+	return ErrVersionConflict
+}
+
+// filterConditions translates a filter into WHERE conditions over "quotes q".
+// Tags are expected to be normalized already.
+func filterConditions(filter domain.QuoteFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	if filter.Author != "" {
+		condition, authorArgs := authorCondition(filter)
+		conditions = append(conditions, condition)
+		args = append(args, authorArgs...)
+	}
+	if filter.AuthorID != "" {
+		conditions = append(conditions, "q.author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, "q.created_at > ?")
+		args = append(args, formatTimestamp(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, "q.created_at < ?")
+		args = append(args, formatTimestamp(filter.CreatedBefore))
+	}
+	// length() counts characters, not bytes, for TEXT values.
+	if filter.MinLength > 0 {
+		conditions = append(conditions, "length(q.text) >= ?")
+		args = append(args, filter.MinLength)
+	}
+	if filter.MaxLength > 0 {
+		conditions = append(conditions, "length(q.text) <= ?")
+		args = append(args, filter.MaxLength)
+	}
+	if len(filter.Tags) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filter.Tags)), ", ")
+		subquery := "SELECT qt.quote_id FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id WHERE t.name IN (" + placeholders + ")"
+		for _, tag := range filter.Tags {
+			args = append(args, tag)
+		}
+		if filter.TagMode == domain.TagModeAll {
+			subquery += " GROUP BY qt.quote_id HAVING COUNT(*) = ?"
+			args = append(args, len(filter.Tags))
+		}
+		conditions = append(conditions, "q.id IN ("+subquery+")")
+	}
+	return conditions, args
+}
+
+// authorCondition matches filter.Author against the quote's author, or with a
+// mode other than exact, against the name keys of every author.
+func authorCondition(filter domain.QuoteFilter) (string, []any) {
+	key := domain.AuthorKey(filter.Author)
+	var match string
+	var args []any
+	switch filter.AuthorMatch {
+	case domain.AuthorMatchCaseInsensitive:
+		match, args = "n.name_key = ?", []any{key}
+	case domain.AuthorMatchPrefix:
+		// Keys hold only letters, digits, marks and spaces, so they never
+		// contain LIKE wildcards; they are also case folded already.
+		match, args = "n.name_key LIKE ?", []any{key + "%"}
+	case domain.AuthorMatchFuzzy:
+		match, args = "edit_distance(n.name_key, ?) <= ?", []any{key, domain.FuzzyAuthorDistance(key)}
+	default:
+		return "q.author = ?", []any{filter.Author}
+	}
+	return "q.author_id IN (SELECT n.author_id FROM author_names n WHERE " + match + ")", args
+}
+
+func (r *SQLiteRepository) GetRandom(ctx context.Context, filter domain.QuoteFilter) (*domain.Quote, error) {
+	query := "SELECT " + quoteColumns + " FROM quotes q"
+	conditions, args := filterConditions(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY RANDOM() LIMIT 1"
+	quote, err := scanQuote(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoQuotes
 		}
 		return nil, fmt.Errorf("failed to get random quote: %w", err)
 	}
 
 	return &quote, nil
 }
+
+func (r *SQLiteRepository) Search(ctx context.Context, query string, limit int) ([]domain.SearchResult, error) {
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if !r.ftsEnabled {
+		return r.searchByScan(ctx, terms, limit)
+	}
+
+	// bm25() is negative with better matches being smaller, hence the sign flip.
+	sqlQuery := `
+	SELECT ` + quoteColumns + `,
+		-bm25(quotes_fts) AS score,
+		snippet(quotes_fts, 1, ?, ?, ?, ?)
+	FROM quotes_fts
+	JOIN quotes q ON q.id = quotes_fts.id
+	WHERE quotes_fts MATCH ?
+	ORDER BY score DESC, q.id
+	LIMIT ?`
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := r.db.QueryContext(ctx, sqlQuery, snippetOpen, snippetClose, snippetEllipsis, snippetTokens, ftsMatchExpression(terms), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.SearchResult
+	for rows.Next() {
+		var result domain.SearchResult
+		result.Quote, err = scanQuote(rows, &result.Score, &result.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result row: %w", err)
+		}
+		result.Snippet = highlightSnippet(result.Snippet)
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *SQLiteRepository) searchByScan(ctx context.Context, terms []string, limit int) ([]domain.SearchResult, error) {
+	quotes, err := r.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search quotes: %w", err)
+	}
+
+	index := newSearchIndex()
+	byID := make(map[string]domain.Quote, len(quotes))
+	for _, quote := range quotes {
+		index.add(quote)
+		byID[quote.ID] = quote
+	}
+
+	var results []domain.SearchResult
+	for _, doc := range index.search(terms, limit) {
+		quote := byID[doc.id]
+		results = append(results, domain.SearchResult{
+			Quote:   quote,
+			Score:   doc.score,
+			Snippet: snippet(quote.Text, terms),
+		})
+	}
+	return results, nil
+}
+
+// sqliteSortColumns maps sort fields to the column used for keyset paging.
+var sqliteSortColumns = map[domain.SortField]string{
+	domain.SortByID:      "id",
+	domain.SortByAuthor:  "author",
+	domain.SortByCreated: "created_at",
+}
+
+func (r *SQLiteRepository) List(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error) {
+	cursor, err := decodeCursor(query.Cursor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	column, ok := sqliteSortColumns[query.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort field: %s", query.SortBy)
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions, args := filterConditions(query.QuoteFilter)
+	if cursor != nil {
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison))
+		args = append(args, cursor.Key, cursor.ID)
+	}
+
+	sqlQuery := fmt.Sprintf("SELECT %s, %s FROM quotes q", quoteColumns, column)
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT ?", column, direction, direction)
+	args = append(args, query.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotes: %w", err)
+	}
+	defer rows.Close()
+
+	page := &domain.QuotePage{Quotes: []domain.Quote{}}
+	var lastKey string
+	for rows.Next() {
+		var key string
+		quote, err := scanQuote(rows, &key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote row: %w", err)
+		}
+		if len(page.Quotes) == query.Limit {
+			last := page.Quotes[len(page.Quotes)-1]
+			page.NextCursor = encodeCursor(pageCursor{
+				SortBy:     query.SortBy,
+				Descending: query.Descending,
+				Key:        lastKey,
+				ID:         last.ID,
+			})
+			break
+		}
+		page.Quotes = append(page.Quotes, quote)
+		lastKey = key
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return page, nil
+}
+
+func (r *SQLiteRepository) Iterate(ctx context.Context, filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+	return iteratePages(ctx, r.List, filter)
+}
+
+func (r *SQLiteRepository) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT t.name, COUNT(*) AS uses
+	FROM quote_tags qt
+	JOIN tags t ON t.id = qt.tag_id
+	GROUP BY t.id
+	ORDER BY uses DESC, t.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.TagCount{}
+	for rows.Next() {
+		var count domain.TagCount
+		if err := rows.Scan(&count.Tag, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *SQLiteRepository) GetDailyPin(ctx context.Context, date string) (string, error) {
+	var quoteID string
+	err := r.db.QueryRowContext(ctx, "SELECT quote_id FROM daily_pins WHERE date = ?", date).Scan(&quoteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get daily pin: %w", err)
+	}
+	return quoteID, nil
+}
+
+// SetDailyPin pins quoteID to date, replacing any earlier pin for that date.
+func (r *SQLiteRepository) SetDailyPin(ctx context.Context, date, quoteID string) error {
+	query := `
+	INSERT INTO daily_pins (date, quote_id, pinned_at)
+	SELECT ?, id, ? FROM quotes WHERE id = ?
+	ON CONFLICT (date) DO UPDATE SET quote_id = excluded.quote_id, pinned_at = excluded.pinned_at`
+	result, err := r.db.ExecContext(ctx, query, date, formatTimestamp(time.Now()), quoteID)
+	if err != nil {
+		return fmt.Errorf("failed to set daily pin: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) DeleteDailyPin(ctx context.Context, date string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM daily_pins WHERE date = ?", date)
+	if err != nil {
+		return fmt.Errorf("failed to delete daily pin: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// backfillAuthors links quotes stored before authors were introduced to an
+// author, oldest first, so the earliest spelling of a name becomes canonical.
+func (r *SQLiteRepository) backfillAuthors() error {
+	ctx := context.Background()
+	pending, err := queryContents(ctx, r.db, "SELECT id, text, author FROM quotes WHERE author_id IS NULL ORDER BY created_at, id")
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		for _, quote := range pending {
+			if err := resolveAuthor(ctx, tx, &quote); err != nil {
+				return err
+			}
+			if err := reassignQuote(ctx, tx, quote.ID, quote.Text, quote.AuthorID, quote.Author, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// resolveAuthor points quote at the author its Author name belongs to,
+// creating the author if the name is new, and replaces the name with the
+// author's canonical one.
+func resolveAuthor(ctx context.Context, tx *sql.Tx, quote *domain.Quote) error {
+	query := `
+	SELECT a.id, a.name FROM author_names n JOIN authors a ON a.id = n.author_id
+	WHERE n.name_key = ?`
+	err := tx.QueryRowContext(ctx, query, domain.AuthorKey(quote.Author)).Scan(&quote.AuthorID, &quote.Author)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to resolve author: %w", err)
+	}
+
+	author := &domain.Author{ID: authorIDs.NewID(), Name: quote.Author, CreatedAt: time.Now().UTC()}
+	if err := insertAuthor(ctx, tx, author); err != nil {
+		return err
+	}
+	quote.AuthorID = author.ID
+	return nil
+}
+
+func insertAuthor(ctx context.Context, tx *sql.Tx, author *domain.Author) error {
+	normalizeAliases(author)
+	names := append([]string{author.Name}, author.Aliases...)
+	for _, name := range names {
+		var existingID string
+		err := tx.QueryRowContext(ctx, "SELECT author_id FROM author_names WHERE name_key = ?", domain.AuthorKey(name)).Scan(&existingID)
+		if err == nil {
+			return &AuthorExistsError{ExistingID: existingID, Name: name}
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to check author name: %w", err)
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, "INSERT INTO authors (id, name, bio, created_at) VALUES (?, ?, ?, ?)",
+		author.ID, author.Name, author.Bio, formatTimestamp(author.CreatedAt))
+	if err != nil {
+		if isConstraintError(err, constraintPrimaryKey) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("failed to create author: %w", err)
+	}
+	for _, name := range names {
+		_, err := tx.ExecContext(ctx, "INSERT INTO author_names (name_key, author_id, name) VALUES (?, ?, ?)",
+			domain.AuthorKey(name), author.ID, name)
+		if err != nil {
+			return fmt.Errorf("failed to add author name: %w", err)
+		}
+	}
+	return nil
+}
+
+// reassignQuote moves a quote to another author under that author's name. A
+// quote that then repeats another one keeps no fingerprint, like the
+// duplicates found by backfillFingerprints. A non-empty updatedAt also bumps
+// the version; the startup backfill leaves both alone.
+func reassignQuote(ctx context.Context, tx *sql.Tx, id, text, authorID, author, updatedAt string) error {
+	set := "author_id = ?, author = ?, fingerprint = ?"
+	args := []any{authorID, author, domain.Fingerprint(text, author)}
+	if updatedAt != "" {
+		set += ", updated_at = ?, version = version + 1"
+		args = append(args, updatedAt)
+	}
+	query := "UPDATE quotes SET " + set + " WHERE id = ?"
+	args = append(args, id)
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	if isConstraintError(err, constraintUnique) {
+		args[2] = nil
+		_, err = tx.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reassign quote %s: %w", id, err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	author.ID = authorIDs.NewID()
+	if author.CreatedAt.IsZero() {
+		author.CreatedAt = time.Now().UTC()
+	}
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		return insertAuthor(ctx, tx, author)
+	})
+}
+
+// authorColumns selects an author from "authors a" with its quote count and
+// all of its names joined with tagSeparator.
+const authorColumns = `a.id, a.name, a.bio, a.created_at,
+	(SELECT COUNT(*) FROM quotes q WHERE q.author_id = a.id),
+	(SELECT group_concat(n.name, char(31)) FROM author_names n WHERE n.author_id = a.id)`
+
+func queryAuthors(ctx context.Context, db queryer, query string, args ...any) ([]domain.Author, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	authors := []domain.Author{}
+	for rows.Next() {
+		var author domain.Author
+		var createdAt string
+		var names sql.NullString
+		if err := rows.Scan(&author.ID, &author.Name, &author.Bio, &createdAt, &author.QuoteCount, &names); err != nil {
+			return nil, fmt.Errorf("failed to scan author row: %w", err)
+		}
+		if author.CreatedAt, err = time.Parse(timestampLayout, createdAt); err != nil {
+			return nil, fmt.Errorf("invalid created_at for author %s: %w", author.ID, err)
+		}
+		if names.Valid {
+			author.Aliases = strings.Split(names.String, tagSeparator)
+		}
+		normalizeAliases(&author)
+		authors = append(authors, author)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return authors, nil
+}
+
+func (r *SQLiteRepository) GetAuthor(ctx context.Context, id string) (*domain.Author, error) {
+	authors, err := queryAuthors(ctx, r.db, "SELECT "+authorColumns+" FROM authors a WHERE a.id = ?", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+	if len(authors) == 0 {
+		return nil, ErrAuthorNotFound
+	}
+	return &authors[0], nil
+}
+
+func (r *SQLiteRepository) ListAuthors(ctx context.Context) ([]domain.Author, error) {
+	authors, err := queryAuthors(ctx, r.db, "SELECT "+authorColumns+" FROM authors a ORDER BY a.name, a.id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+	return authors, nil
+}
+
+func (r *SQLiteRepository) MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error) {
+	if targetID == sourceID {
+		return r.GetAuthor(ctx, targetID)
+	}
+
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var targetName, targetBio, sourceBio string
+		err := tx.QueryRowContext(ctx, "SELECT name, bio FROM authors WHERE id = ?", targetID).Scan(&targetName, &targetBio)
+		if err == nil {
+			err = tx.QueryRowContext(ctx, "SELECT bio FROM authors WHERE id = ?", sourceID).Scan(&sourceBio)
+		}
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrAuthorNotFound
+			}
+			return fmt.Errorf("failed to get author: %w", err)
+		}
+
+		moved, err := queryContents(ctx, tx, "SELECT id, text, author FROM quotes WHERE author_id = ? ORDER BY created_at, id", sourceID)
+		if err != nil {
+			return err
+		}
+		updatedAt := formatTimestamp(time.Now())
+		for _, quote := range moved {
+			if err := reassignQuote(ctx, tx, quote.ID, quote.Text, targetID, targetName, updatedAt); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE author_names SET author_id = ? WHERE author_id = ?", targetID, sourceID); err != nil {
+			return fmt.Errorf("failed to move author names: %w", err)
+		}
+		if targetBio == "" && sourceBio != "" {
+			if _, err := tx.ExecContext(ctx, "UPDATE authors SET bio = ? WHERE id = ?", sourceBio, targetID); err != nil {
+				return fmt.Errorf("failed to update author: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM authors WHERE id = ?", sourceID); err != nil {
+			return fmt.Errorf("failed to delete author: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetAuthor(ctx, targetID)
+}
@@ -0,0 +1,19 @@
+//go:build cgo
+
+package repository
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	constraintPrimaryKey = sqlite3.ErrConstraintPrimaryKey
+	constraintUnique     = sqlite3.ErrConstraintUnique
+)
+
+func isConstraintError(err error, code sqlite3.ErrNoExtended) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == code
+}
@@ -0,0 +1,18 @@
+//go:build !cgo
+
+package repository
+
+// Without cgo go-sqlite3 is a stub that fails to open any database, so the
+// SQLite repository reports that at startup and there are no constraint errors
+// to classify. The file repository works in such builds.
+
+type constraintCode int
+
+const (
+	constraintPrimaryKey constraintCode = iota
+	constraintUnique
+)
+
+func isConstraintError(err error, code constraintCode) bool {
+	return false
+}
@@ -3,6 +3,7 @@ package repository_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"test-task-scout-go/internal/repository"
 )
@@ -67,4 +68,105 @@ func TestSQLiteRepository(t *testing.T) {
 		defer cleanup()
 		testRepositoryGetRandom(t, repo)
 	})
-} 
+
+	t.Run("Search", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositorySearch(t, repo)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryList(t, repo)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryUpdate(t, repo)
+	})
+
+	t.Run("Versioning", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryVersioning(t, repo)
+	})
+
+	t.Run("AuditMetadata", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryAuditMetadata(t, repo)
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryTags(t, repo)
+	})
+
+	t.Run("RandomFilter", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryRandomFilter(t, repo)
+	})
+
+	t.Run("DailyPins", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryDailyPins(t, repo)
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryCanceledContext(t, repo)
+	})
+
+	t.Run("CreateBatch", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryCreateBatch(t, repo)
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryIterate(t, repo)
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryDuplicates(t, repo)
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryAuthors(t, repo)
+	})
+
+	t.Run("AuthorMatch", func(t *testing.T) {
+		repo, cleanup := newTestSQLiteRepository(t)
+		defer cleanup()
+		testRepositoryAuthorMatch(t, repo)
+	})
+}
+
+func TestSQLiteRepository_CheckHealth(t *testing.T) {
+	repo, cleanup := newTestSQLiteRepository(t)
+	defer cleanup()
+	wrapped := repository.NewInstrumentedRepository(
+		repository.NewCachingRepository(repo, repository.CacheOptions{Size: 10, TTL: time.Minute}),
+		func(string, time.Duration, error) {},
+	)
+
+	if err := repository.CheckHealth(t.Context(), wrapped); err != nil {
+		t.Fatalf("CheckHealth failed: %v", err)
+	}
+	repo.Close()
+	if err := repository.CheckHealth(t.Context(), wrapped); err == nil {
+		t.Error("Expected CheckHealth on a closed database to fail")
+	}
+}
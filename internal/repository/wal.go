@@ -0,0 +1,499 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// SyncPolicy says when the write-ahead log is flushed to stable storage.
+type SyncPolicy string
+
+const (
+	// SyncAlways flushes every change before it is acknowledged.
+	SyncAlways SyncPolicy = "always"
+	// SyncInterval flushes in the background, so a power loss can take the
+	// changes of the last interval with it.
+	SyncInterval SyncPolicy = "interval"
+	// SyncNever leaves flushing to the operating system. Changes survive a
+	// crash of the process but not necessarily of the machine.
+	SyncNever SyncPolicy = "never"
+)
+
+func (p SyncPolicy) Valid() bool {
+	switch p {
+	case SyncAlways, SyncInterval, SyncNever:
+		return true
+	}
+	return false
+}
+
+const (
+	DefaultSyncInterval  = time.Second
+	DefaultSnapshotEvery = 1000
+)
+
+type DurabilityOptions struct {
+	// Dir holds the snapshot, the log and a lock file that keeps a second
+	// process out.
+	Dir  string
+	Sync SyncPolicy
+	// SyncInterval is how often the log is flushed under SyncInterval.
+	SyncInterval time.Duration
+	// SnapshotEvery is the number of logged writes after which the log is
+	// compacted into a new snapshot.
+	SnapshotEvery int
+}
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+	walLockFileName  = "LOCK"
+)
+
+var errRepositoryClosed = errors.New("repository is closed")
+
+type walOp string
+
+const (
+	walPutQuote     walOp = "put_quote"
+	walDeleteQuote  walOp = "delete_quote"
+	walPutAuthor    walOp = "put_author"
+	walDeleteAuthor walOp = "delete_author"
+	walSetPin       walOp = "set_pin"
+	walDeletePin    walOp = "delete_pin"
+)
+
+// walRecord is the new state of one quote, author or pin rather than the
+// operation that produced it, so replaying a record needs no ID generation or
+// author lookup and replaying it twice does no harm.
+type walRecord struct {
+	Op      walOp          `json:"op"`
+	Quote   *domain.Quote  `json:"quote,omitempty"`
+	Author  *domain.Author `json:"author,omitempty"`
+	ID      string         `json:"id,omitempty"`
+	Date    string         `json:"date,omitempty"`
+	QuoteID string         `json:"quote_id,omitempty"`
+}
+
+// walEntry is one line of the log: the records of a single write, which are
+// replayed together or not at all. A line is the CRC-32 of the JSON entry in
+// hex, a space and the entry, so a write torn by a crash is recognized.
+type walEntry struct {
+	Seq     uint64      `json:"seq"`
+	Records []walRecord `json:"records"`
+}
+
+// walSnapshot is the FileRepository data format plus the sequence number of
+// the last write it contains; log entries up to it are skipped on recovery.
+type walSnapshot struct {
+	Seq uint64 `json:"seq"`
+	fileData
+}
+
+type writeAheadLog struct {
+	opts DurabilityOptions
+	lock *fileLock
+
+	// mu guards the fields below against the background flush.
+	mu    sync.Mutex
+	file  *os.File
+	dirty bool
+	seq   uint64
+	// writes counts the entries logged since the last snapshot.
+	writes int
+	// err is set once the log cannot be trusted to hold every change, after
+	// which the repository refuses changes.
+	err  error
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewDurableInMemoryRepository returns an InMemoryRepository whose changes
+// survive a restart. Every write is appended to a log in opts.Dir and flushed
+// according to opts.Sync; the log is compacted into a snapshot every
+// opts.SnapshotEvery writes and on Close. On startup the snapshot is loaded
+// and the log replayed, ignoring a last entry that a crash left incomplete.
+//
+// Reads are served from memory as before. If the log cannot be written, the
+// failed change stays in memory, its error is returned and every later change
+// is refused until the repository is reopened from disk.
+func NewDurableInMemoryRepository(opts DurabilityOptions) (*InMemoryRepository, error) {
+	if opts.Sync == "" {
+		opts.Sync = SyncAlways
+	}
+	if !opts.Sync.Valid() {
+		return nil, fmt.Errorf("unknown sync policy: %s", opts.Sync)
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = DefaultSyncInterval
+	}
+	if opts.SnapshotEvery <= 0 {
+		opts.SnapshotEvery = DefaultSnapshotEvery
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	lock, err := lockFile(filepath.Join(opts.Dir, walLockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", opts.Dir, err)
+	}
+
+	r := NewInMemoryRepository()
+	if err := r.recoverState(opts, lock); err != nil {
+		lock.release()
+		return nil, err
+	}
+	if opts.Sync == SyncInterval {
+		go r.wal.flushLoop()
+	}
+	return r, nil
+}
+
+// recoverState loads the snapshot, replays the log written after it and
+// starts a new log from a fresh snapshot.
+func (r *InMemoryRepository) recoverState(opts DurabilityOptions, lock *fileLock) error {
+	seq, err := r.loadSnapshot(filepath.Join(opts.Dir, snapshotFileName))
+	if err != nil {
+		return err
+	}
+	walPath := filepath.Join(opts.Dir, walFileName)
+	if seq, err = r.replay(walPath, seq); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(walPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.wal = &writeAheadLog{
+		opts: opts,
+		lock: lock,
+		file: file,
+		seq:  seq,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if err := r.compact(); err != nil {
+		file.Close()
+		r.wal = nil
+		return err
+	}
+	return nil
+}
+
+func (r *InMemoryRepository) loadSnapshot(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot walSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return 0, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	if snapshot.Version > fileFormatVersion {
+		return 0, fmt.Errorf("snapshot %s has format version %d, this build supports up to %d", path, snapshot.Version, fileFormatVersion)
+	}
+	if err := r.restore(snapshot.memorySnapshot); err != nil {
+		return 0, fmt.Errorf("failed to load snapshot %s: %w", path, err)
+	}
+	return snapshot.Seq, nil
+}
+
+// replay applies the log entries after seq and returns the last sequence
+// number. Reading stops at the first entry that is incomplete or fails its
+// checksum: entries are only acknowledged once written in full, so that can
+// only be a write cut short by a crash.
+func (r *InMemoryRepository) replay(path string, seq uint64) (uint64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return seq, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open write-ahead log: %w", err)
+	}
+	defer file.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reader := bufio.NewReader(file)
+	replayed := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				log.Printf("Ignoring an incomplete entry at the end of the write-ahead log (%d bytes)", len(line))
+			}
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read write-ahead log: %w", err)
+		}
+		entry, ok := parseWALEntry(line)
+		if !ok {
+			log.Printf("Ignoring a damaged entry in the write-ahead log after sequence %d and everything after it", seq)
+			break
+		}
+		if entry.Seq <= seq {
+			continue
+		}
+		for _, record := range entry.Records {
+			if err := r.apply(record); err != nil {
+				return 0, fmt.Errorf("failed to replay write-ahead log entry %d: %w", entry.Seq, err)
+			}
+		}
+		seq = entry.Seq
+		replayed++
+	}
+	if replayed > 0 {
+		log.Printf("Recovered %d writes from the write-ahead log", replayed)
+	}
+	return seq, nil
+}
+
+func formatWALEntry(entry walEntry) ([]byte, error) {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	line := strconv.AppendUint(nil, uint64(crc32.ChecksumIEEE(payload)), 16)
+	line = append(line, ' ')
+	line = append(line, payload...)
+	return append(line, '\n'), nil
+}
+
+func parseWALEntry(line []byte) (walEntry, bool) {
+	var entry walEntry
+	checksum, payload, found := bytes.Cut(bytes.TrimSuffix(line, []byte("\n")), []byte(" "))
+	if !found {
+		return entry, false
+	}
+	sum, err := strconv.ParseUint(string(checksum), 16, 32)
+	if err != nil || uint32(sum) != crc32.ChecksumIEEE(payload) {
+		return entry, false
+	}
+	if err := json.Unmarshal(payload, &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+// apply replays one record. The caller holds the write lock.
+func (r *InMemoryRepository) apply(record walRecord) error {
+	switch record.Op {
+	case walPutQuote:
+		if record.Quote == nil {
+			return errors.New("put_quote record without a quote")
+		}
+		if existing, exists := r.quotes[record.Quote.ID]; exists {
+			r.replace(existing, *record.Quote)
+		} else {
+			r.store(*record.Quote)
+		}
+	case walDeleteQuote:
+		if quote, exists := r.quotes[record.ID]; exists {
+			r.remove(quote)
+		}
+	case walPutAuthor:
+		if record.Author == nil {
+			return errors.New("put_author record without an author")
+		}
+		r.deleteAuthor(record.Author.ID)
+		r.insertAuthor(record.Author)
+	case walDeleteAuthor:
+		r.deleteAuthor(record.ID)
+	case walSetPin:
+		r.pins[record.Date] = record.QuoteID
+	case walDeletePin:
+		delete(r.pins, record.Date)
+	default:
+		return fmt.Errorf("unknown record %q", record.Op)
+	}
+	return nil
+}
+
+// deleteAuthor forgets an author and the names that still point to it. The
+// caller holds the write lock.
+func (r *InMemoryRepository) deleteAuthor(id string) {
+	for key, owner := range r.authorNames {
+		if owner == id {
+			delete(r.authorNames, key)
+		}
+	}
+	delete(r.authors, id)
+}
+
+// record notes a change for the log of a durable repository. The caller
+// holds the write lock.
+func (r *InMemoryRepository) record(record walRecord) {
+	if r.wal != nil {
+		r.pending = append(r.pending, record)
+	}
+}
+
+// writable reports why the repository cannot take changes, if it cannot.
+// The caller holds the write lock.
+func (r *InMemoryRepository) writable() error {
+	if r.wal == nil {
+		return nil
+	}
+	r.wal.mu.Lock()
+	defer r.wal.mu.Unlock()
+	return r.wal.err
+}
+
+// commit logs the changes recorded since the last commit as one entry and
+// compacts the log when it is due. The caller holds the write lock.
+func (r *InMemoryRepository) commit() error {
+	if r.wal == nil || len(r.pending) == 0 {
+		return nil
+	}
+	records := r.pending
+	r.pending = nil
+	if err := r.wal.append(records); err != nil {
+		return err
+	}
+	if r.wal.writes >= r.wal.opts.SnapshotEvery {
+		// The write is already durable, so a failed compaction is only
+		// reported; it is retried after the next write.
+		if err := r.compact(); err != nil {
+			log.Printf("Failed to compact write-ahead log: %v", err)
+		}
+	}
+	return nil
+}
+
+// compact writes a snapshot of the current state and empties the log. The
+// caller holds the write lock, so no write falls between the two.
+func (r *InMemoryRepository) compact() error {
+	content, err := json.Marshal(walSnapshot{
+		Seq:      r.wal.seq,
+		fileData: fileData{Version: fileFormatVersion, memorySnapshot: r.snapshotLocked()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(r.wal.opts.Dir, snapshotFileName), content); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return r.wal.reset()
+}
+
+// Close writes a final snapshot of a durable repository and releases its
+// directory; later changes are refused. It does nothing for a repository
+// without durability.
+func (r *InMemoryRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w := r.wal
+	if w == nil {
+		return nil
+	}
+	if errors.Is(w.failure(), errRepositoryClosed) {
+		return nil
+	}
+	if w.opts.Sync == SyncInterval {
+		close(w.stop)
+		<-w.done
+	}
+
+	var err error
+	if w.failure() == nil {
+		err = r.compact()
+	}
+	w.mu.Lock()
+	w.err = errRepositoryClosed
+	w.mu.Unlock()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	if releaseErr := w.lock.release(); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+func (w *writeAheadLog) failure() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *writeAheadLog) append(records []walRecord) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+
+	line, err := formatWALEntry(walEntry{Seq: w.seq + 1, Records: records})
+	if err != nil {
+		return fmt.Errorf("failed to encode write-ahead log entry: %w", err)
+	}
+	_, err = w.file.Write(line)
+	if err == nil && w.opts.Sync == SyncAlways {
+		err = w.file.Sync()
+	}
+	if err != nil {
+		w.err = fmt.Errorf("write-ahead log failed, changes are refused until restart: %w", err)
+		return w.err
+	}
+	w.seq++
+	w.writes++
+	w.dirty = true
+	return nil
+}
+
+func (w *writeAheadLog) reset() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate write-ahead log: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("failed to truncate write-ahead log: %w", err)
+	}
+	w.writes = 0
+	w.dirty = false
+	return nil
+}
+
+func (w *writeAheadLog) flushLoop() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if w.dirty && w.err == nil {
+				if err := w.file.Sync(); err != nil {
+					w.err = fmt.Errorf("write-ahead log failed, changes are refused until restart: %w", err)
+				}
+				w.dirty = false
+			}
+			w.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package repository_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/repository"
+)
+
+func newTestDurableRepository(t *testing.T, opts repository.DurabilityOptions) *repository.InMemoryRepository {
+	t.Helper()
+	if opts.Dir == "" {
+		opts.Dir = t.TempDir()
+	}
+	repo, err := repository.NewDurableInMemoryRepository(opts)
+	if err != nil {
+		t.Fatalf("Failed to initialize durable in-memory repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestDurableInMemoryRepository(t *testing.T) {
+	// Flushing is irrelevant to the behaviour under test and only slows it down.
+	opts := repository.DurabilityOptions{Sync: repository.SyncNever}
+	t.Run("CreateAndGet", func(t *testing.T) {
+		testRepositoryCreateAndGet(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		testRepositoryCreateDuplicate(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		testRepositoryGetAll(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("GetByAuthor", func(t *testing.T) {
+		testRepositoryGetByAuthor(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		testRepositoryDelete(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("GetRandom", func(t *testing.T) {
+		testRepositoryGetRandom(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		testRepositorySearch(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		testRepositoryList(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		testRepositoryUpdate(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("Versioning", func(t *testing.T) {
+		testRepositoryVersioning(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("AuditMetadata", func(t *testing.T) {
+		testRepositoryAuditMetadata(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		testRepositoryTags(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("RandomFilter", func(t *testing.T) {
+		testRepositoryRandomFilter(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("DailyPins", func(t *testing.T) {
+		testRepositoryDailyPins(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		testRepositoryCanceledContext(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("CreateBatch", func(t *testing.T) {
+		testRepositoryCreateBatch(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		testRepositoryIterate(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		testRepositoryDuplicates(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("Authors", func(t *testing.T) {
+		testRepositoryAuthors(t, newTestDurableRepository(t, opts))
+	})
+
+	t.Run("AuthorMatch", func(t *testing.T) {
+		testRepositoryAuthorMatch(t, newTestDurableRepository(t, opts))
+	})
+}
+
+// crashCopy copies a data directory as it is, like the state left on disk by
+// a process that dies without closing its repository.
+func crashCopy(t *testing.T, dir string) string {
+	t.Helper()
+	copyDir := t.TempDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == "LOCK" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(copyDir, entry.Name()), content, 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	return copyDir
+}
+
+// assertSameState compares everything a client can read from two
+// repositories.
+func assertSameState(t *testing.T, want, got repository.QuoteRepository) {
+	t.Helper()
+	dump := func(repo repository.QuoteRepository) string {
+		quotes, err := repo.GetAll(t.Context())
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		sort.Slice(quotes, func(i, j int) bool { return quotes[i].ID < quotes[j].ID })
+		authors, err := repo.ListAuthors(t.Context())
+		if err != nil {
+			t.Fatalf("ListAuthors failed: %v", err)
+		}
+		pin, _ := repo.GetDailyPin(t.Context(), "2024-03-01")
+		content, err := json.Marshal(map[string]any{"quotes": quotes, "authors": authors, "pin": pin})
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		return string(content)
+	}
+	if wantState, gotState := dump(want), dump(got); wantState != gotState {
+		t.Errorf("Recovered state differs.\nExpected: %s\nGot:      %s", wantState, gotState)
+	}
+}
+
+func writeDurableHistory(t *testing.T, repo repository.QuoteRepository) {
+	t.Helper()
+	ctx := t.Context()
+
+	author := &domain.Author{Name: "Logged Author", Aliases: []string{"L. Author"}}
+	if err := repo.CreateAuthor(ctx, author); err != nil {
+		t.Fatalf("CreateAuthor failed: %v", err)
+	}
+	for i, name := range []string{"L. Author", "Second Author", "Second Author", "Third Author"} {
+		quote := &domain.Quote{ID: "wal-" + string(rune('a'+i)), Text: "Logged quote " + string(rune('a'+i)), Author: name, Tags: []string{"wal"}}
+		if err := repo.Create(ctx, quote); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if err := repo.Update(ctx, &domain.Quote{ID: "wal-a", Text: "Logged quote a, edited", Author: "Logged Author"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := repo.Delete(ctx, "wal-d", 0); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.SetDailyPin(ctx, "2024-03-01", "wal-b"); err != nil {
+		t.Fatalf("SetDailyPin failed: %v", err)
+	}
+	second, err := repo.GetByID(ctx, "wal-b")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if _, err := repo.MergeAuthors(ctx, author.ID, second.AuthorID); err != nil {
+		t.Fatalf("MergeAuthors failed: %v", err)
+	}
+	batch := []*domain.Quote{
+		{ID: "wal-e", Text: "Batched quote one", Author: "Batch Author"},
+		{ID: "wal-f", Text: "Batched quote two", Author: "Batch Author"},
+	}
+	if _, err := repo.CreateBatch(ctx, batch, true); err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+}
+
+func TestDurableInMemoryRepository_Recovery(t *testing.T) {
+	for _, policy := range []repository.SyncPolicy{repository.SyncAlways, repository.SyncInterval, repository.SyncNever} {
+		t.Run(string(policy), func(t *testing.T) {
+			dir := t.TempDir()
+			repo := newTestDurableRepository(t, repository.DurabilityOptions{Dir: dir, Sync: policy, SyncInterval: 10 * time.Millisecond})
+			writeDurableHistory(t, repo)
+
+			recovered := newTestDurableRepository(t, repository.DurabilityOptions{Dir: crashCopy(t, dir)})
+			assertSameState(t, repo, recovered)
+
+			// Recovery starts a new log, which keeps working.
+			if err := recovered.Create(t.Context(), &domain.Quote{ID: "wal-g", Text: "After recovery", Author: "L. Author"}); err != nil {
+				t.Fatalf("Create after recovery failed: %v", err)
+			}
+			quote, err := recovered.GetByID(t.Context(), "wal-g")
+			if err != nil || quote.Author != "Logged Author" {
+				t.Errorf("Expected the recovered aliases to resolve, got %+v (%v)", quote, err)
+			}
+		})
+	}
+}
+
+func TestDurableInMemoryRepository_TornWrite(t *testing.T) {
+	dir := t.TempDir()
+	repo := newTestDurableRepository(t, repository.DurabilityOptions{Dir: dir})
+	writeDurableHistory(t, repo)
+
+	for name, tail := range map[string]string{
+		"Incomplete":  `1a2b3c4d {"seq":99,"records":[{"op":"put_q`,
+		"BadChecksum": `0 {"seq":99,"records":[{"op":"delete_quote","id":"wal-a"}]}` + "\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			crashDir := crashCopy(t, dir)
+			walFile, err := os.OpenFile(filepath.Join(crashDir, "wal.log"), os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				t.Fatalf("OpenFile failed: %v", err)
+			}
+			walFile.WriteString(tail)
+			walFile.Close()
+
+			recovered := newTestDurableRepository(t, repository.DurabilityOptions{Dir: crashDir})
+			assertSameState(t, repo, recovered)
+		})
+	}
+}
+
+func TestDurableInMemoryRepository_Compaction(t *testing.T) {
+	dir := t.TempDir()
+	repo := newTestDurableRepository(t, repository.DurabilityOptions{Dir: dir, SnapshotEvery: 3})
+	for i := range 7 {
+		id := "compact-" + string(rune('a'+i))
+		if err := repo.Create(t.Context(), &domain.Quote{ID: id, Text: "Text of " + id, Author: "Author"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "wal.log"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if lines := strings.Count(string(content), "\n"); lines != 1 {
+		t.Errorf("Expected the log to hold the 1 write since the last snapshot, got %d", lines)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "snapshot.json")); err != nil {
+		t.Errorf("Expected a snapshot: %v", err)
+	}
+
+	recovered := newTestDurableRepository(t, repository.DurabilityOptions{Dir: crashCopy(t, dir)})
+	assertSameState(t, repo, recovered)
+}
+
+func TestDurableInMemoryRepository_Close(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := repository.NewDurableInMemoryRepository(repository.DurabilityOptions{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewDurableInMemoryRepository failed: %v", err)
+	}
+	writeDurableHistory(t, repo)
+
+	if _, err := repository.NewDurableInMemoryRepository(repository.DurabilityOptions{Dir: dir}); err == nil {
+		t.Fatal("Expected a second repository on a locked directory to fail")
+	}
+
+	if err := repo.CheckHealth(t.Context()); err != nil {
+		t.Errorf("CheckHealth failed: %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := repo.CheckHealth(t.Context()); err == nil {
+		t.Error("Expected CheckHealth after Close to fail")
+	}
+	if err := repo.Create(t.Context(), &domain.Quote{ID: "closed", Text: "After close", Author: "Author"}); err == nil {
+		t.Error("Expected Create after Close to fail")
+	}
+	if info, err := os.Stat(filepath.Join(dir, "wal.log")); err != nil || info.Size() != 0 {
+		t.Errorf("Expected Close to leave an empty log, got %v (%v)", info, err)
+	}
+
+	reopened := newTestDurableRepository(t, repository.DurabilityOptions{Dir: dir})
+	assertSameState(t, repo, reopened)
+}
@@ -0,0 +1,166 @@
+package router
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Scope is what a caller may do: read; read and write; or, as an admin, also
+// pin the quote of the day. Each scope includes the ones before it, and the
+// empty scope allows nothing.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+	ScopeAdmin Scope = "admin"
+)
+
+var scopeRank = map[Scope]int{ScopeRead: 1, ScopeWrite: 2, ScopeAdmin: 3}
+
+// APIKeyHeader carries the API key of a request.
+const APIKeyHeader = "X-API-Key"
+
+// APIKey is a key the API accepts, known only by its SHA-256 hash.
+type APIKey struct {
+	Name  string
+	Scope Scope
+	Hash  []byte
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Name is the name of the API key or the subject of the token, and is
+	// recorded as the creator of the quotes the caller adds.
+	Name  string
+	Scope Scope
+}
+
+func (p *Principal) allows(scope Scope) bool {
+	return scopeRank[p.Scope] >= scopeRank[scope]
+}
+
+type principalKey struct{}
+
+// principalFrom returns the caller WithAuth identified, or nil when the
+// request was let through without credentials.
+func principalFrom(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// creator names the caller as the creator of the quotes it adds. Without
+// authentication it is empty, and the service records an anonymous creator.
+func creator(req *http.Request) string {
+	if principal := principalFrom(req.Context()); principal != nil {
+		return principal.Name
+	}
+	return ""
+}
+
+// Authenticator identifies the caller of a request by one kind of
+// credentials.
+type Authenticator interface {
+	// Authenticate returns nil and no error when the request carries no
+	// credentials of its kind. The error text is shown to the client, so it
+	// must say no more than why the credentials were refused.
+	Authenticate(req *http.Request) (*Principal, error)
+	// Challenge is the WWW-Authenticate value asking for the credentials.
+	Challenge() string
+}
+
+var errInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyAuth authenticates requests by the API key in APIKeyHeader.
+type APIKeyAuth struct {
+	keys []APIKey
+}
+
+func NewAPIKeyAuth(keys []APIKey) *APIKeyAuth {
+	return &APIKeyAuth{keys: keys}
+}
+
+// Authenticate compares the hash of the key with every known hash in
+// constant time, so that neither the comparison nor the number of
+// comparisons made tells how close a guess was.
+func (a *APIKeyAuth) Authenticate(req *http.Request) (*Principal, error) {
+	key := req.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+	sum := sha256.Sum256([]byte(key))
+	var principal *Principal
+	for _, known := range a.keys {
+		if subtle.ConstantTimeCompare(sum[:], known.Hash) == 1 && principal == nil {
+			principal = &Principal{Name: known.Name, Scope: known.Scope}
+		}
+	}
+	if principal == nil {
+		return nil, errInvalidAPIKey
+	}
+	return principal, nil
+}
+
+func (a *APIKeyAuth) Challenge() string {
+	return `APIKey header="` + APIKeyHeader + `"`
+}
+
+// requiredScope is the scope needed for the request. The API only reads on
+// safe methods, and pinning or unpinning the quote of the day is for admins.
+func requiredScope(req *http.Request) Scope {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	}
+	if strings.HasPrefix(req.URL.Path, "/quotes/daily/") {
+		return ScopeAdmin
+	}
+	return ScopeWrite
+}
+
+// WithAuth identifies the caller with the first of authenticators that finds
+// credentials in the request, and passes it on to the handlers in the
+// request context. Requests without valid credentials get 401, except reads
+// when publicRead is set, and callers lacking the scope get 403.
+func WithAuth(next http.Handler, publicRead bool, authenticators ...Authenticator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		scope := requiredScope(req)
+		var principal *Principal
+		for _, auth := range authenticators {
+			var err error
+			principal, err = auth.Authenticate(req)
+			if err != nil {
+				writeUnauthorized(w, req, authenticators, "Credentials were refused: "+err.Error())
+				return
+			}
+			if principal != nil {
+				break
+			}
+		}
+
+		if principal == nil {
+			if scope == ScopeRead && publicRead {
+				next.ServeHTTP(w, req)
+				return
+			}
+			writeUnauthorized(w, req, authenticators, "Credentials are required")
+			return
+		}
+		if !principal.allows(scope) {
+			writeProblem(w, req, http.StatusForbidden, codeForbidden, "Credentials do not allow this operation")
+			return
+		}
+		next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), principalKey{}, principal)))
+	})
+}
+
+func writeUnauthorized(w http.ResponseWriter, req *http.Request, authenticators []Authenticator, detail string) {
+	for _, auth := range authenticators {
+		w.Header().Add("WWW-Authenticate", auth.Challenge())
+	}
+	writeProblem(w, req, http.StatusUnauthorized, codeUnauthorized, detail)
+}
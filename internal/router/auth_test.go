@@ -0,0 +1,149 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"test-task-scout-go/internal/domain"
+)
+
+func testAPIKey(name string, scope Scope) APIKey {
+	hash := sha256.Sum256([]byte(name + "-secret"))
+	return APIKey{Name: name, Scope: scope, Hash: hash[:]}
+}
+
+// allowed answers 204 to every request WithAuth lets through.
+var allowed = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+})
+
+var writeMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+func TestWithAuth_APIKeys(t *testing.T) {
+	auth := NewAPIKeyAuth([]APIKey{testAPIKey("dashboard", ScopeRead), testAPIKey("ci", ScopeWrite)})
+	handler := WithAuth(allowed, false, auth)
+
+	t.Run("MissingKey", func(t *testing.T) {
+		rec := serve(handler, http.MethodGet, "/quotes", "")
+		p := decodeProblem(t, rec, http.StatusUnauthorized, codeUnauthorized)
+		if p.Detail != "Credentials are required" {
+			t.Errorf("Unexpected detail: %q", p.Detail)
+		}
+		if challenge := rec.Header().Get("WWW-Authenticate"); challenge != `APIKey header="X-API-Key"` {
+			t.Errorf("Expected an API key challenge, got %q", challenge)
+		}
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		rec := serve(handler, http.MethodGet, "/quotes", "", APIKeyHeader, "guess")
+		p := decodeProblem(t, rec, http.StatusUnauthorized, codeUnauthorized)
+		if p.Detail != "Credentials were refused: invalid API key" {
+			t.Errorf("Unexpected detail: %q", p.Detail)
+		}
+		if rec.Header().Get("WWW-Authenticate") == "" {
+			t.Error("Expected a challenge")
+		}
+	})
+
+	t.Run("ReadScope", func(t *testing.T) {
+		for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+			if rec := serve(handler, method, "/quotes", "", APIKeyHeader, "dashboard-secret"); rec.Code != http.StatusNoContent {
+				t.Errorf("%s: expected the request to be let through, got %d", method, rec.Code)
+			}
+		}
+		for _, method := range writeMethods {
+			rec := serve(handler, method, "/quotes/q-1", "", APIKeyHeader, "dashboard-secret")
+			decodeProblem(t, rec, http.StatusForbidden, codeForbidden)
+		}
+	})
+
+	t.Run("WriteScope", func(t *testing.T) {
+		for _, method := range append([]string{http.MethodGet}, writeMethods...) {
+			if rec := serve(handler, method, "/quotes/q-1", "", APIKeyHeader, "ci-secret"); rec.Code != http.StatusNoContent {
+				t.Errorf("%s: expected the request to be let through, got %d", method, rec.Code)
+			}
+		}
+	})
+}
+
+func TestWithAuth_PublicRead(t *testing.T) {
+	handler := WithAuth(allowed, true, NewAPIKeyAuth([]APIKey{testAPIKey("dashboard", ScopeRead)}))
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		if rec := serve(handler, method, "/quotes/q-1", ""); rec.Code != http.StatusNoContent {
+			t.Errorf("%s: expected the request to be let through without credentials, got %d", method, rec.Code)
+		}
+	}
+	for _, method := range writeMethods {
+		rec := serve(handler, method, "/quotes/q-1", "")
+		decodeProblem(t, rec, http.StatusUnauthorized, codeUnauthorized)
+	}
+
+	rec := serve(handler, http.MethodGet, "/quotes/q-1", "", APIKeyHeader, "guess")
+	decodeProblem(t, rec, http.StatusUnauthorized, codeUnauthorized)
+}
+
+func TestWithAuth_Creator(t *testing.T) {
+	r := newTestRouter()
+	handler := WithAuth(r, false, NewAPIKeyAuth([]APIKey{testAPIKey("ci", ScopeWrite)}))
+
+	rec := serve(handler, http.MethodPost, "/quotes", `{"text":"Text","author":"Author"}`, APIKeyHeader, "ci-secret")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var quote domain.Quote
+	if err := json.NewDecoder(rec.Body).Decode(&quote); err != nil {
+		t.Fatalf("Failed to decode quote: %v", err)
+	}
+	if quote.CreatedBy != "ci" {
+		t.Errorf("Expected the key name as creator, got %q", quote.CreatedBy)
+	}
+
+	if name := creator(httptest.NewRequest(http.MethodPost, "/quotes", nil)); name != "" {
+		t.Errorf("Expected no creator without authentication, got %q", name)
+	}
+}
+
+func TestWithAuth_Challenges(t *testing.T) {
+	handler := WithAuth(newTestRouter(), false, NewAPIKeyAuth(nil), &TokenAuth{})
+	rec := serve(handler, http.MethodGet, "/quotes", "")
+	decodeProblem(t, rec, http.StatusUnauthorized, codeUnauthorized)
+	expected := []string{`APIKey header="X-API-Key"`, `Bearer realm="quotes"`}
+	if challenges := rec.Header().Values("WWW-Authenticate"); !slices.Equal(challenges, expected) {
+		t.Errorf("Expected challenges %q, got %q", expected, challenges)
+	}
+}
+
+func TestWithAuth_AdminScope(t *testing.T) {
+	r := newTestRouter()
+	auth := NewAPIKeyAuth([]APIKey{testAPIKey("writer", ScopeWrite), testAPIKey("editor", ScopeAdmin)})
+	handler := WithAuth(r, false, auth)
+	quote := createTestQuote(t, r, "Text", "Author")
+	pin := `{"quote_id":"` + quote.ID + `"}`
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		rec := serve(handler, method, "/quotes/daily/2024-03-01", pin, APIKeyHeader, "writer-secret")
+		decodeProblem(t, rec, http.StatusForbidden, codeForbidden)
+	}
+
+	rec := serve(handler, http.MethodPut, "/quotes/daily/2024-03-01", pin, APIKeyHeader, "editor-secret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected an admin to pin the quote, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = serve(handler, http.MethodGet, "/quotes/daily?date=2024-03-01", "", APIKeyHeader, "writer-secret")
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected a writer to read the quote of the day, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = serve(handler, http.MethodPost, "/quotes", `{"text":"Other","author":"Author"}`, APIKeyHeader, "editor-secret")
+	if rec.Code != http.StatusCreated {
+		t.Errorf("Expected an admin to write, got %d: %s", rec.Code, rec.Body)
+	}
+	rec = serve(handler, http.MethodDelete, "/quotes/daily/2024-03-01", "", APIKeyHeader, "editor-secret")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected an admin to unpin the quote, got %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,81 @@
+package router
+
+import "net/http"
+
+type authorRequest struct {
+	Name    string   `json:"name"`
+	Bio     string   `json:"bio"`
+	Aliases []string `json:"aliases"`
+}
+
+func (r *Router) createAuthorHandler(w http.ResponseWriter, req *http.Request) {
+	var authorData authorRequest
+	if !decodeJSONBody(w, req, &authorData) {
+		return
+	}
+
+	author, err := r.service.CreateAuthor(req.Context(), authorData.Name, authorData.Bio, authorData.Aliases)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to create author")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, author)
+}
+
+func (r *Router) listAuthorsHandler(w http.ResponseWriter, req *http.Request) {
+	authors, err := r.service.ListAuthors(req.Context())
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to retrieve authors")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, authors)
+}
+
+func (r *Router) getAuthorHandler(w http.ResponseWriter, req *http.Request, id string) {
+	author, err := r.service.GetAuthor(req.Context(), id)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to retrieve author")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, author)
+}
+
+// listAuthorQuotesHandler pages through an author's quotes with the same
+// parameters as GET /quotes.
+func (r *Router) listAuthorQuotesHandler(w http.ResponseWriter, req *http.Request, id string) {
+	query, ok := parseListQuery(w, req)
+	if !ok {
+		return
+	}
+
+	page, err := r.service.ListAuthorQuotes(req.Context(), id, query)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to retrieve quotes")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+type mergeRequest struct {
+	// AuthorID is the author merged into the one named in the path.
+	AuthorID string `json:"author_id"`
+}
+
+func (r *Router) mergeAuthorsHandler(w http.ResponseWriter, req *http.Request, id string) {
+	var merge mergeRequest
+	if !decodeJSONBody(w, req, &merge) {
+		return
+	}
+
+	author, err := r.service.MergeAuthors(req.Context(), id, merge.AuthorID)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to merge authors")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, author)
+}
@@ -0,0 +1,59 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"test-task-scout-go/internal/domain"
+)
+
+func decodeDaily(t *testing.T, rec *httptest.ResponseRecorder) domain.DailyQuote {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var daily domain.DailyQuote
+	if err := json.NewDecoder(rec.Body).Decode(&daily); err != nil {
+		t.Fatalf("Failed to decode daily quote: %v", err)
+	}
+	return daily
+}
+
+func TestRouter_DailyQuotePinning(t *testing.T) {
+	r := newTestRouter()
+	first := createTestQuote(t, r, "First", "Author")
+	second := createTestQuote(t, r, "Second", "Author")
+
+	picked := decodeDaily(t, serve(r, http.MethodGet, "/quotes/daily?date=2024-03-01", ""))
+	if picked.Pinned || picked.Date != "2024-03-01" {
+		t.Fatalf("Expected an unpinned quote for 2024-03-01, got %+v", picked)
+	}
+	other := first
+	if picked.Quote.ID == first.ID {
+		other = second
+	}
+
+	pinned := decodeDaily(t, serve(r, http.MethodPut, "/quotes/daily/2024-03-01", `{"quote_id":"`+other.ID+`"}`))
+	if !pinned.Pinned || pinned.Quote.ID != other.ID {
+		t.Errorf("Expected %s to be pinned, got %+v", other.ID, pinned)
+	}
+	if daily := decodeDaily(t, serve(r, http.MethodGet, "/quotes/daily/2024-03-01", "")); daily.Quote.ID != other.ID {
+		t.Errorf("Expected the pinned quote to be served, got %+v", daily)
+	}
+
+	if rec := serve(r, http.MethodDelete, "/quotes/daily/2024-03-01", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", rec.Code, rec.Body)
+	}
+	if daily := decodeDaily(t, serve(r, http.MethodGet, "/quotes/daily?date=2024-03-01", "")); daily.Pinned || daily.Quote.ID != picked.Quote.ID {
+		t.Errorf("Expected the computed quote after unpinning, got %+v", daily)
+	}
+
+	rec := serve(r, http.MethodDelete, "/quotes/daily/2024-03-01", "")
+	decodeProblem(t, rec, http.StatusNotFound, codeNotFound)
+	rec = serve(r, http.MethodPut, "/quotes/daily/2024-03-01", `{"quote_id":""}`)
+	decodeProblem(t, rec, http.StatusBadRequest, codeValidationFailed)
+	rec = serve(r, http.MethodPut, "/quotes/daily/01.03.2024", `{"quote_id":"`+first.ID+`"}`)
+	decodeProblem(t, rec, http.StatusBadRequest, codeValidationFailed)
+}
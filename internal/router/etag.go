@@ -0,0 +1,89 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+func parseETagList(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseETagVersion finds the version a strong entity tag was issued for.
+// Entity tags are opaque, so only the exact string formatETag gives matches:
+// "03" and "+3" are different tags from "3".
+func parseETagVersion(tag string) (int64, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version <= 0 || tag != formatETag(version) {
+		return 0, false
+	}
+	return version, true
+}
+
+// noneMatch reports whether an If-None-Match header matches the version using
+// the weak comparison required for GET.
+func noneMatch(header string, version int64) bool {
+	etag := formatETag(version)
+	for _, tag := range parseETagList(header) {
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion turns the If-Match header into the version a write must be
+// conditional on; 0 means unconditional. Entity tags are compared strongly, so
+// weak tags never match. With several candidate tags the current version is
+// looked up to pick the one the write should be pinned to.
+func (r *Router) ifMatchVersion(w http.ResponseWriter, req *http.Request, id string) (int64, bool) {
+	header := req.Header.Get("If-Match")
+	if header == "" {
+		return 0, true
+	}
+
+	var versions []int64
+	for _, tag := range parseETagList(header) {
+		if tag == "*" {
+			return 0, true
+		}
+		if v, ok := parseETagVersion(tag); ok {
+			versions = append(versions, v)
+		}
+	}
+
+	switch len(versions) {
+	case 0:
+		writeProblem(w, req, http.StatusPreconditionFailed, codePreconditionFailed, "If-Match does not match the quote")
+		return 0, false
+	case 1:
+		return versions[0], true
+	}
+
+	quote, err := r.service.GetByID(req.Context(), id)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to retrieve quote")
+		return 0, false
+	}
+	for _, v := range versions {
+		if v == quote.Version {
+			return v, true
+		}
+	}
+	writeProblem(w, req, http.StatusPreconditionFailed, codePreconditionFailed, "If-Match does not match the quote")
+	return 0, false
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestParseETagList(t *testing.T) {
+	for header, expected := range map[string][]string{
+		``:                    nil,
+		`"1"`:                 {`"1"`},
+		`*`:                   {`*`},
+		` "1" ,W/"2",, "3" `:  {`"1"`, `W/"2"`, `"3"`},
+		`"1", *`:              {`"1"`, `*`},
+		`W/"7",  W/"8"`:       {`W/"7"`, `W/"8"`},
+		`,`:                   nil,
+		`"a b", "c"`:          {`"a b"`, `"c"`},
+		` "12" `:              {`"12"`},
+		`"1","2","3","4","5"`: {`"1"`, `"2"`, `"3"`, `"4"`, `"5"`},
+	} {
+		if tags := parseETagList(header); !slices.Equal(tags, expected) {
+			t.Errorf("parseETagList(%q): expected %q, got %q", header, expected, tags)
+		}
+	}
+}
+
+func TestParseETagVersion(t *testing.T) {
+	for tag, expected := range map[string]int64{
+		`"1"`:     1,
+		`"42"`:    42,
+		`W/"42"`:  0,
+		`42`:      0,
+		`"0"`:     0,
+		`"-3"`:    0,
+		`"+3"`:    0,
+		`"03"`:    0,
+		`"abc"`:   0,
+		`""`:      0,
+		`"`:       0,
+		`*`:       0,
+		`"1" "2"`: 0,
+	} {
+		version, ok := parseETagVersion(tag)
+		if version != expected || ok != (expected > 0) {
+			t.Errorf("parseETagVersion(%q): expected %d, got %d (ok %v)", tag, expected, version, ok)
+		}
+	}
+	if tag := formatETag(42); tag != `"42"` {
+		t.Errorf(`Expected formatETag to give "42", got %s`, tag)
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	for header, expected := range map[string]bool{
+		`"3"`:             true,
+		`W/"3"`:           true,
+		`*`:               true,
+		`"1", "2"`:        false,
+		`"1", W/"3"`:      true,
+		`"2", *`:          true,
+		`"03"`:            false,
+		`"+3"`:            false,
+		`3`:               false,
+		`W/"2"`:           false,
+		`"garbage", "3" `: true,
+	} {
+		if matched := noneMatch(header, 3); matched != expected {
+			t.Errorf("noneMatch(%q, 3): expected %v, got %v", header, expected, matched)
+		}
+	}
+}
+
+func TestRouter_ConditionalGet(t *testing.T) {
+	r := newTestRouter()
+	quote := createTestQuote(t, r, "Text", "Author")
+	target := "/quotes/" + quote.ID
+	etag := formatETag(quote.Version)
+
+	rec := serve(r, http.MethodGet, target, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != etag {
+		t.Fatalf("Expected 200 with ETag %s, got %d with %q", etag, rec.Code, rec.Header().Get("ETag"))
+	}
+
+	for _, header := range []string{etag, "W/" + etag, "*", `"999", ` + etag} {
+		rec := serve(r, http.MethodGet, target, "", "If-None-Match", header)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("If-None-Match %s: expected 304, got %d", header, rec.Code)
+		}
+		if rec.Header().Get("ETag") != etag || rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: expected ETag and no body, got %q and %q", header, rec.Header().Get("ETag"), rec.Body)
+		}
+	}
+
+	rec = serve(r, http.MethodGet, target, "", "If-None-Match", `"999"`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a different ETag, got %d", rec.Code)
+	}
+}
+
+func TestRouter_ConditionalPut(t *testing.T) {
+	r := newTestRouter()
+	quote := createTestQuote(t, r, "Text", "Author")
+	target := "/quotes/" + quote.ID
+	stale := formatETag(quote.Version)
+
+	rec := serve(r, http.MethodPut, target, `{"text":"Updated","author":"Author"}`, "If-Match", stale)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for the current ETag, got %d: %s", rec.Code, rec.Body)
+	}
+	current := rec.Header().Get("ETag")
+	if current == stale {
+		t.Fatalf("Expected the update to change the ETag %s", stale)
+	}
+
+	rec = serve(r, http.MethodPut, target, `{"text":"Lost update","author":"Author"}`, "If-Match", stale)
+	decodeProblem(t, rec, http.StatusPreconditionFailed, codeVersionConflict)
+
+	for _, header := range []string{"W/" + current, `"not-a-version"`, `"0` + current[1:]} {
+		rec := serve(r, http.MethodPut, target, `{"text":"Lost update","author":"Author"}`, "If-Match", header)
+		decodeProblem(t, rec, http.StatusPreconditionFailed, codePreconditionFailed)
+	}
+
+	rec = serve(r, http.MethodPut, target, `{"text":"Lost update","author":"Author"}`, "If-Match", stale+", "+`"999"`)
+	decodeProblem(t, rec, http.StatusPreconditionFailed, codePreconditionFailed)
+
+	rec = serve(r, http.MethodPut, target, `{"text":"Again","author":"Author"}`, "If-Match", stale+", "+current)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected a list holding the current ETag to match, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(r, http.MethodPut, target, `{"text":"Forced","author":"Author"}`, "If-Match", "*")
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected If-Match * to match, got %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,232 @@
+package router
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/service"
+)
+
+// exportTimeout replaces the server-wide request timeout for exports, which
+// stream for as long as the collection takes to write. A client that goes away
+// is noticed through the failing write instead of the request context.
+const exportTimeout = 10 * time.Minute
+
+// exportFlushInterval is how many quotes are written between flushes, so the
+// client receives data steadily instead of in one burst at the end.
+const exportFlushInterval = 100
+
+// exportWriter encodes a stream of quotes in one of the export formats.
+type exportWriter interface {
+	begin() error
+	write(quote domain.Quote) error
+	end() error
+}
+
+var exportContentTypes = map[fileFormat]string{
+	formatJSON:   "application/json",
+	formatNDJSON: "application/x-ndjson",
+	formatCSV:    "text/csv; charset=utf-8",
+}
+
+func newExportWriter(format fileFormat, w io.Writer) exportWriter {
+	switch format {
+	case formatNDJSON:
+		return &ndjsonExportWriter{encoder: json.NewEncoder(w)}
+	case formatCSV:
+		return &csvExportWriter{writer: csv.NewWriter(w)}
+	default:
+		return &jsonExportWriter{w: w}
+	}
+}
+
+// exportQuotesHandler streams the quotes matching the usual filters as a
+// download. Output is in ID order and each format can be fed back to
+// POST /quotes/import.
+func (r *Router) exportQuotesHandler(w http.ResponseWriter, req *http.Request) {
+	format := fileFormat(req.URL.Query().Get("format"))
+	if format == "" {
+		format = formatJSON
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		writeFieldProblem(w, req, "format", "format must be json, ndjson or csv")
+		return
+	}
+	filter, ok := parseQuoteFilter(w, req)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(req.Context()), exportTimeout)
+	defer cancel()
+	quotes, err := r.service.ExportQuotes(ctx, filter)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to export quotes")
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Now().Add(exportTimeout)); err != nil {
+		log.Printf("Error extending export write deadline: %v", err)
+	}
+
+	encoder := newExportWriter(format, w)
+	started := false
+	start := func() error {
+		filename := "quotes-" + time.Now().UTC().Format(service.DailyDateLayout) + "." + string(format)
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.WriteHeader(http.StatusOK)
+		started = true
+		return encoder.begin()
+	}
+
+	written := 0
+	for quote, err := range quotes {
+		if err != nil {
+			if !started {
+				writeServiceError(w, req, err, "Failed to export quotes")
+				return
+			}
+			// The status line is already sent, so the only way left to signal
+			// a truncated export is to abort the connection.
+			log.Printf("Error exporting quotes after %d rows: %v", written, err)
+			panic(http.ErrAbortHandler)
+		}
+		if !started {
+			if err := start(); err != nil {
+				return
+			}
+		}
+		if err := encoder.write(quote); err != nil {
+			return
+		}
+		written++
+		if written%exportFlushInterval == 0 {
+			if err := controller.Flush(); err != nil {
+				return
+			}
+		}
+	}
+	if !started {
+		if err := start(); err != nil {
+			return
+		}
+	}
+	encoder.end()
+}
+
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonExportWriter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExportWriter) write(quote domain.Quote) error {
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExportWriter) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonExportWriter) begin() error { return nil }
+
+func (e *ndjsonExportWriter) write(quote domain.Quote) error {
+	return e.encoder.Encode(quote)
+}
+
+func (e *ndjsonExportWriter) end() error { return nil }
+
+// csvExportColumns starts with the columns import reads, so an exported file
+// can be imported as is.
+var csvExportColumns = []string{"text", "author", "tags", "id", "version", "created_at", "updated_at", "created_by", "author_id"}
+
+// csvFormulaPrefixes are the characters that make a spreadsheet evaluate a
+// cell as a formula.
+const csvFormulaPrefixes = "=+-@"
+
+func isCSVFormula(cell string) bool {
+	rest := strings.TrimLeft(cell, "'")
+	return rest != "" && strings.IndexByte(csvFormulaPrefixes, rest[0]) >= 0
+}
+
+// escapeCSVFormula prefixes a cell that a spreadsheet would evaluate as a
+// formula with an apostrophe, which makes it plain text. A cell that already
+// starts with apostrophes before such a character gets one more, so that
+// unescapeCSVFormula restores every value on import.
+func escapeCSVFormula(cell string) string {
+	if isCSVFormula(cell) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func unescapeCSVFormula(cell string) string {
+	if strings.HasPrefix(cell, "'") && isCSVFormula(cell) {
+		return cell[1:]
+	}
+	return cell
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func (e *csvExportWriter) begin() error {
+	return e.writer.Write(csvExportColumns)
+}
+
+func (e *csvExportWriter) write(quote domain.Quote) error {
+	err := e.writer.Write([]string{
+		escapeCSVFormula(quote.Text),
+		escapeCSVFormula(quote.Author),
+		escapeCSVFormula(strings.Join(quote.Tags, csvTagSeparator)),
+		quote.ID,
+		strconv.FormatInt(quote.Version, 10),
+		quote.CreatedAt.Format(time.RFC3339Nano),
+		quote.UpdatedAt.Format(time.RFC3339Nano),
+		quote.CreatedBy,
+		quote.AuthorID,
+	})
+	if err != nil {
+		return err
+	}
+	// Flush into the response so periodic flushes reach the client.
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvExportWriter) end() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
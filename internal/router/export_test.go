@@ -0,0 +1,58 @@
+package router
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEscapeCSVFormula(t *testing.T) {
+	for cell, expected := range map[string]string{
+		"":                  "",
+		"Plain text":        "Plain text",
+		"=1+1":              "'=1+1",
+		"+7 days":           "'+7 days",
+		"- Who's there?":    "'- Who's there?",
+		"@channel":          "'@channel",
+		"'=1+1":             "''=1+1",
+		"'quoted'":          "'quoted'",
+		"a=b":               "a=b",
+		`=HYPERLINK("x")`:   `'=HYPERLINK("x")`,
+		"''@already hidden": "'''@already hidden",
+	} {
+		escaped := escapeCSVFormula(cell)
+		if escaped != expected {
+			t.Errorf("escapeCSVFormula(%q): expected %q, got %q", cell, expected, escaped)
+		}
+		if restored := unescapeCSVFormula(escaped); restored != cell {
+			t.Errorf("unescapeCSVFormula(%q): expected %q, got %q", escaped, cell, restored)
+		}
+	}
+}
+
+func TestRouter_ExportCSVFormulas(t *testing.T) {
+	r := newTestRouter()
+	createTestQuote(t, r, `=HYPERLINK("http://evil.example","click")`, "@attacker")
+
+	rec := serve(r, http.MethodGet, "/quotes/export?format=csv", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	exported := rec.Body.String()
+	records, err := csv.NewReader(strings.NewReader(exported)).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("Expected a header and one row, got %q, %v", records, err)
+	}
+	if text, author := records[1][0], records[1][1]; text != `'=HYPERLINK("http://evil.example","click")` || author != "'@attacker" {
+		t.Errorf("Expected formula cells to be escaped, got %q and %q", text, author)
+	}
+
+	rows, err := decodeCSV(strings.NewReader(exported))
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("Expected the export to decode, got %+v, %v", rows, err)
+	}
+	if rows[0].Text != `=HYPERLINK("http://evil.example","click")` || rows[0].Author != "@attacker" {
+		t.Errorf("Expected the import to restore the cells, got %+v", rows[0])
+	}
+}
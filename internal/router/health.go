@@ -0,0 +1,87 @@
+package router
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// readinessTimeout bounds the dependency check behind /readyz, so that a hung
+// database makes the probe fail instead of time out.
+const readinessTimeout = 2 * time.Second
+
+// BuildInfo identifies the running build in probe responses.
+type BuildInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	Modified   bool   `json:"modified,omitempty"`
+	GoVersion  string `json:"go_version"`
+}
+
+// Health serves the liveness probe /healthz, which succeeds while the process
+// can handle requests at all, and the readiness probe /readyz, which also
+// checks the dependencies and fails once the server starts shutting down.
+type Health struct {
+	check    func(ctx context.Context) error
+	build    BuildInfo
+	started  time.Time
+	draining atomic.Bool
+}
+
+type healthResponse struct {
+	Status        string    `json:"status"`
+	UptimeSeconds float64   `json:"uptime_seconds"`
+	Build         BuildInfo `json:"build"`
+}
+
+func NewHealth(check func(ctx context.Context) error, build BuildInfo) *Health {
+	return &Health{check: check, build: build, started: time.Now()}
+}
+
+// Drain makes /readyz fail from now on, so that the orchestrator stops sending
+// traffic while the requests in flight complete.
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+func (h *Health) Liveness(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		methodNotAllowed(w, req, "GET, HEAD")
+		return
+	}
+	h.writeStatus(w, http.StatusOK, "ok")
+}
+
+// Readiness reports why the service is not ready only in the log; the
+// response just says it is not.
+func (h *Health) Readiness(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		methodNotAllowed(w, req, "GET, HEAD")
+		return
+	}
+	if h.draining.Load() {
+		h.writeStatus(w, http.StatusServiceUnavailable, "draining")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), readinessTimeout)
+	defer cancel()
+	if err := h.check(ctx); err != nil {
+		log.Printf("Readiness check failed: %v", err)
+		h.writeStatus(w, http.StatusServiceUnavailable, "unavailable")
+		return
+	}
+	h.writeStatus(w, http.StatusOK, "ready")
+}
+
+func (h *Health) writeStatus(w http.ResponseWriter, status int, state string) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, healthResponse{
+		Status:        state,
+		UptimeSeconds: time.Since(h.started).Truncate(time.Second).Seconds(),
+		Build:         h.build,
+	})
+}
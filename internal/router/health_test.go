@@ -0,0 +1,90 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testBuild = BuildInfo{Version: "v1.2.3", Commit: "abc123", GoVersion: "go1.24.2"}
+
+func decodeHealth(t *testing.T, rec *httptest.ResponseRecorder, status int, state string) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("Expected status %d, got %d: %s", status, rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected the probe not to be cached, got %q", rec.Header().Get("Cache-Control"))
+	}
+	var response healthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Status != state || response.Build != testBuild || response.UptimeSeconds < 0 {
+		t.Errorf("Expected %s with build %+v, got %+v", state, testBuild, response)
+	}
+}
+
+func TestHealth_Liveness(t *testing.T) {
+	health := NewHealth(func(ctx context.Context) error { return errors.New("database is down") }, testBuild)
+	health.Drain()
+
+	decodeHealth(t, serve(http.HandlerFunc(health.Liveness), http.MethodGet, "/healthz", ""), http.StatusOK, "ok")
+	rec := serve(http.HandlerFunc(health.Liveness), http.MethodPost, "/healthz", "")
+	decodeProblem(t, rec, http.StatusMethodNotAllowed, codeMethodNotAllowed)
+}
+
+func TestHealth_Readiness(t *testing.T) {
+	t.Run("Ready", func(t *testing.T) {
+		var deadline time.Time
+		health := NewHealth(func(ctx context.Context) error {
+			deadline, _ = ctx.Deadline()
+			return nil
+		}, testBuild)
+
+		decodeHealth(t, serve(http.HandlerFunc(health.Readiness), http.MethodGet, "/readyz", ""), http.StatusOK, "ready")
+		if deadline.IsZero() || time.Until(deadline) > readinessTimeout {
+			t.Errorf("Expected the check to be bounded by %v, got deadline %v", readinessTimeout, deadline)
+		}
+		rec := serve(http.HandlerFunc(health.Readiness), http.MethodDelete, "/readyz", "")
+		decodeProblem(t, rec, http.StatusMethodNotAllowed, codeMethodNotAllowed)
+	})
+
+	t.Run("Draining", func(t *testing.T) {
+		checked := false
+		health := NewHealth(func(ctx context.Context) error {
+			checked = true
+			return nil
+		}, testBuild)
+		health.Drain()
+
+		decodeHealth(t, serve(http.HandlerFunc(health.Readiness), http.MethodGet, "/readyz", ""), http.StatusServiceUnavailable, "draining")
+		if checked {
+			t.Error("Expected a draining server to skip the check")
+		}
+	})
+
+	t.Run("CheckFails", func(t *testing.T) {
+		health := NewHealth(func(ctx context.Context) error { return errors.New("database is down") }, testBuild)
+
+		decodeHealth(t, serve(http.HandlerFunc(health.Readiness), http.MethodGet, "/readyz", ""), http.StatusServiceUnavailable, "unavailable")
+	})
+
+	t.Run("CheckTimesOut", func(t *testing.T) {
+		health := NewHealth(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, testBuild)
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/readyz", nil)
+		rec := httptest.NewRecorder()
+
+		health.Readiness(rec, req)
+		decodeHealth(t, rec, http.StatusServiceUnavailable, "unavailable")
+	})
+}
@@ -0,0 +1,293 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// maxImportBodySize bounds an import upload; the service separately caps the
+// number of rows.
+const maxImportBodySize = 32 << 20
+
+// fileFormat is a serialization shared by import and export.
+type fileFormat string
+
+const (
+	formatJSON   fileFormat = "json"
+	formatNDJSON fileFormat = "ndjson"
+	formatCSV    fileFormat = "csv"
+)
+
+var importMediaTypes = map[string]fileFormat{
+	"application/json":     formatJSON,
+	"application/x-ndjson": formatNDJSON,
+	"application/ndjson":   formatNDJSON,
+	"application/jsonl":    formatNDJSON,
+	"text/csv":             formatCSV,
+}
+
+var importExtensions = map[string]fileFormat{
+	".json":   formatJSON,
+	".ndjson": formatNDJSON,
+	".jsonl":  formatNDJSON,
+	".csv":    formatCSV,
+}
+
+// importError is malformed input that prevents reading the upload at all, as
+// opposed to a well-formed row that fails validation.
+type importError struct {
+	code   string
+	detail string
+}
+
+func (e *importError) Error() string { return e.detail }
+
+func malformedRow(code string, row int, err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return err
+	}
+	return &importError{code: code, detail: fmt.Sprintf("Row %d is malformed: %v", row, err)}
+}
+
+// importQuotesHandler accepts a JSON array, NDJSON stream or CSV file, either
+// as the request body or as the "file" field of a multipart form.
+func (r *Router) importQuotesHandler(w http.ResponseWriter, req *http.Request) {
+	params := req.URL.Query()
+	opts := domain.ImportOptions{Mode: domain.ImportMode(params.Get("mode"))}
+	if value := params.Get("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			writeFieldProblem(w, req, "dry_run", "dry_run must be true or false")
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	if req.Body == nil || req.ContentLength == 0 {
+		writeProblem(w, req, http.StatusBadRequest, codeEmptyBody, "Request body is empty")
+		return
+	}
+	req.Body = http.MaxBytesReader(w, req.Body, maxImportBodySize)
+
+	body, format, err := importSource(req)
+	if err != nil {
+		writeImportError(w, req, err)
+		return
+	}
+	rows, err := decodeImport(format, body)
+	if err != nil {
+		writeImportError(w, req, err)
+		return
+	}
+
+	opts.CreatedBy = creator(req)
+	report, err := r.service.ImportQuotes(req.Context(), rows, opts)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to import quotes")
+		return
+	}
+
+	status := http.StatusOK
+	if report.Mode == domain.ImportAtomic && report.Failed > 0 && !report.DryRun {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, report)
+}
+
+// importSource finds the upload and its format from the Content-Type, or for
+// multipart forms from the file part's own type or extension.
+func importSource(req *http.Request) (io.Reader, fileFormat, error) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err == nil && mediaType != "multipart/form-data" {
+		if format, ok := importMediaTypes[mediaType]; ok {
+			return req.Body, format, nil
+		}
+	}
+	if err != nil || mediaType != "multipart/form-data" {
+		return nil, "", &importError{
+			code:   codeUnsupportedMediaType,
+			detail: "Content-Type must be application/json, application/x-ndjson, text/csv or multipart/form-data",
+		}
+	}
+
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, "", &importError{code: codeInvalidMultipart, detail: "Invalid multipart form"}
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, "", &importError{code: codeEmptyBody, detail: "Multipart form has no file field"}
+		}
+		if err != nil {
+			return nil, "", malformedMultipart(err)
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if format, ok := importMediaTypes[partType]; ok {
+			return part, format, nil
+		}
+		if format, ok := importExtensions[strings.ToLower(filepath.Ext(part.FileName()))]; ok {
+			return part, format, nil
+		}
+		return nil, "", &importError{
+			code:   codeUnsupportedMediaType,
+			detail: "Uploaded file must be JSON, NDJSON or CSV",
+		}
+	}
+}
+
+func malformedMultipart(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return err
+	}
+	return &importError{code: codeInvalidMultipart, detail: "Invalid multipart form"}
+}
+
+func writeImportError(w http.ResponseWriter, req *http.Request, err error) {
+	var importErr *importError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &importErr):
+		status := http.StatusBadRequest
+		if importErr.code == codeUnsupportedMediaType {
+			status = http.StatusUnsupportedMediaType
+		}
+		writeProblem(w, req, status, importErr.code, importErr.detail)
+	case errors.As(err, &maxBytesErr):
+		writeProblem(w, req, http.StatusRequestEntityTooLarge, codeBodyTooLarge, "Request body too large")
+	default:
+		log.Printf("Error reading import body: %v", err)
+		writeProblem(w, req, http.StatusInternalServerError, codeInternal, "Failed to read request body")
+	}
+}
+
+func decodeImport(format fileFormat, body io.Reader) ([]domain.QuoteInput, error) {
+	switch format {
+	case formatNDJSON:
+		return decodeNDJSON(body)
+	case formatCSV:
+		return decodeCSV(body)
+	default:
+		return decodeJSONArray(body)
+	}
+}
+
+func decodeJSONArray(body io.Reader) ([]domain.QuoteInput, error) {
+	decoder := json.NewDecoder(body)
+	token, err := decoder.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil, &importError{code: codeEmptyBody, detail: "Request body is empty"}
+		}
+		return nil, malformedRow(codeInvalidJSON, 1, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, &importError{code: codeInvalidJSON, detail: "Expected a JSON array of quotes"}
+	}
+
+	var rows []domain.QuoteInput
+	for decoder.More() {
+		var row domain.QuoteInput
+		if err := decoder.Decode(&row); err != nil {
+			return nil, malformedRow(codeInvalidJSON, len(rows)+1, err)
+		}
+		rows = append(rows, row)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, malformedRow(codeInvalidJSON, len(rows)+1, err)
+	}
+	return rows, nil
+}
+
+// decodeNDJSON reads one quote per line. Blank lines are skipped and do not
+// count as rows.
+func decodeNDJSON(body io.Reader) ([]domain.QuoteInput, error) {
+	reader := bufio.NewReader(body)
+	var rows []domain.QuoteInput
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, malformedRow(codeInvalidJSON, len(rows)+1, err)
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var row domain.QuoteInput
+			if err := json.Unmarshal(line, &row); err != nil {
+				return nil, malformedRow(codeInvalidJSON, len(rows)+1, err)
+			}
+			rows = append(rows, row)
+		}
+		if err == io.EOF {
+			return rows, nil
+		}
+	}
+}
+
+// csvTagSeparator splits the tags column, since commas already separate fields.
+const csvTagSeparator = ";"
+
+// decodeCSV reads a CSV file whose header names the text, author and optional
+// tags columns, in any order. The apostrophe export puts before formula-like
+// cells is removed.
+func decodeCSV(body io.Reader) ([]domain.QuoteInput, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, &importError{code: codeEmptyBody, detail: "CSV file is empty"}
+	}
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, err
+		}
+		return nil, &importError{code: codeInvalidCSV, detail: "CSV header is malformed"}
+	}
+	columns := map[string]int{"text": -1, "author": -1, "tags": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	if columns["text"] < 0 || columns["author"] < 0 {
+		return nil, &importError{code: codeInvalidCSV, detail: "CSV header must include text and author columns"}
+	}
+
+	var rows []domain.QuoteInput
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, malformedRow(codeInvalidCSV, len(rows)+1, err)
+		}
+		row := domain.QuoteInput{
+			Text:   unescapeCSVFormula(record[columns["text"]]),
+			Author: unescapeCSVFormula(record[columns["author"]]),
+		}
+		if i := columns["tags"]; i >= 0 && strings.TrimSpace(record[i]) != "" {
+			row.Tags = strings.Split(unescapeCSVFormula(record[i]), csvTagSeparator)
+		}
+		rows = append(rows, row)
+	}
+}
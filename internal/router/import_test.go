@@ -0,0 +1,254 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strings"
+	"testing"
+
+	"test-task-scout-go/internal/domain"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	rows, err := decodeCSV(strings.NewReader("\ufeffAuthor, Tags ,TEXT,notes\nSeneca,stoic;time,\"Time, and again\",x\nLao Tzu,,Be water,\n"))
+	if err != nil {
+		t.Fatalf("decodeCSV failed: %v", err)
+	}
+	expected := []domain.QuoteInput{
+		{Text: "Time, and again", Author: "Seneca", Tags: []string{"stoic", "time"}},
+		{Text: "Be water", Author: "Lao Tzu"},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %+v", len(expected), rows)
+	}
+	for i, row := range rows {
+		if row.Text != expected[i].Text || row.Author != expected[i].Author || !slices.Equal(row.Tags, expected[i].Tags) {
+			t.Errorf("Row %d: expected %+v, got %+v", i+1, expected[i], row)
+		}
+	}
+
+	for name, body := range map[string]string{
+		"empty":          "",
+		"missing author": "text,tags\nA,b\n",
+		"ragged row":     "text,author\nA,B,C\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeCSV(strings.NewReader(body)); err == nil {
+				t.Error("Expected decodeCSV to fail")
+			}
+		})
+	}
+}
+
+func TestDecodeNDJSON(t *testing.T) {
+	rows, err := decodeNDJSON(strings.NewReader("\n{\"text\":\"A\",\"author\":\"B\"}\r\n  \n{\"text\":\"C\",\"author\":\"D\",\"tags\":[\"x\"]}"))
+	if err != nil {
+		t.Fatalf("decodeNDJSON failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Text != "A" || rows[1].Author != "D" || len(rows[1].Tags) != 1 {
+		t.Errorf("Expected two rows without the blank lines, got %+v", rows)
+	}
+
+	_, err = decodeNDJSON(strings.NewReader("{\"text\":\"A\",\"author\":\"B\"}\n\n{broken\n"))
+	if err == nil || !strings.Contains(err.Error(), "Row 2") {
+		t.Errorf("Expected the second row to be reported as malformed, got %v", err)
+	}
+}
+
+func decodeImportReport(t *testing.T, status int, body *bytes.Buffer) domain.ImportReport {
+	t.Helper()
+	var report domain.ImportReport
+	if err := json.NewDecoder(body).Decode(&report); err != nil {
+		t.Fatalf("Failed to decode report (status %d): %v", status, err)
+	}
+	return report
+}
+
+func multipartBody(t *testing.T, filename, contentType, contents string) (string, string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("comment", "ignored"); err != nil {
+		t.Fatalf("WriteField failed: %v", err)
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatalf("CreatePart failed: %v", err)
+	}
+	part.Write([]byte(contents))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return body.String(), writer.FormDataContentType()
+}
+
+func TestRouter_ImportFormats(t *testing.T) {
+	const (
+		jsonBody   = `[{"text":"First","author":"Seneca","tags":["stoic"]},{"text":"Second","author":"Lao Tzu"}]`
+		ndjsonBody = "{\"text\":\"First\",\"author\":\"Seneca\",\"tags\":[\"stoic\"]}\n\n{\"text\":\"Second\",\"author\":\"Lao Tzu\"}\n"
+		csvBody    = "\ufeffauthor,text,tags\nSeneca,First,stoic\nLao Tzu,Second,\n"
+	)
+	multipartCSV, csvForm := multipartBody(t, "quotes.bin", "text/csv", csvBody)
+	multipartNDJSON, ndjsonForm := multipartBody(t, "QUOTES.JSONL", "application/octet-stream", ndjsonBody)
+	multipartJSON, jsonForm := multipartBody(t, "quotes.json", "", jsonBody)
+
+	for name, test := range map[string]struct {
+		contentType string
+		body        string
+	}{
+		"JSON":                   {"application/json; charset=utf-8", jsonBody},
+		"NDJSON":                 {"application/x-ndjson", ndjsonBody},
+		"JSON Lines":             {"application/jsonl", ndjsonBody},
+		"CSV":                    {"text/csv", csvBody},
+		"multipart part type":    {csvForm, multipartCSV},
+		"multipart extension":    {ndjsonForm, multipartNDJSON},
+		"multipart no part type": {jsonForm, multipartJSON},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := newTestRouter()
+			rec := serve(r, http.MethodPost, "/quotes/import", test.body, "Content-Type", test.contentType)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+			}
+			report := decodeImportReport(t, rec.Code, rec.Body)
+			if report.Mode != domain.ImportAtomic || report.Total != 2 || report.Imported != 2 || report.Failed != 0 {
+				t.Fatalf("Unexpected report: %+v", report)
+			}
+
+			rec = serve(r, http.MethodGet, "/quotes/"+report.Rows[0].ID, "")
+			var quote domain.Quote
+			if err := json.NewDecoder(rec.Body).Decode(&quote); err != nil {
+				t.Fatalf("Failed to decode quote: %v", err)
+			}
+			if quote.Text != "First" || quote.Author != "Seneca" || !slices.Equal(quote.Tags, []string{"stoic"}) {
+				t.Errorf("Unexpected imported quote: %+v", quote)
+			}
+		})
+	}
+}
+
+func TestRouter_ImportRejects(t *testing.T) {
+	unknownPart, unknownForm := multipartBody(t, "quotes.xml", "application/xml", "<quotes/>")
+	noFile, noFileForm := multipartBody(t, "quotes.csv", "text/csv", "text,author\n")
+	noFile = strings.ReplaceAll(noFile, `name="file"`, `name="upload"`)
+
+	for name, test := range map[string]struct {
+		contentType string
+		body        string
+		status      int
+		code        string
+	}{
+		"unsupported type":      {"application/xml", "<quotes/>", http.StatusUnsupportedMediaType, codeUnsupportedMediaType},
+		"missing type":          {"", "[]", http.StatusUnsupportedMediaType, codeUnsupportedMediaType},
+		"unsupported part":      {unknownForm, unknownPart, http.StatusUnsupportedMediaType, codeUnsupportedMediaType},
+		"no file field":         {noFileForm, noFile, http.StatusBadRequest, codeEmptyBody},
+		"broken multipart":      {"multipart/form-data; boundary=x", "garbage", http.StatusBadRequest, codeInvalidMultipart},
+		"empty body":            {"application/json", "", http.StatusBadRequest, codeEmptyBody},
+		"not an array":          {"application/json", `{"text":"A","author":"B"}`, http.StatusBadRequest, codeInvalidJSON},
+		"malformed NDJSON":      {"application/x-ndjson", "{broken\n", http.StatusBadRequest, codeInvalidJSON},
+		"CSV without author":    {"text/csv", "text\nA\n", http.StatusBadRequest, codeInvalidCSV},
+		"too large":             {"application/json", "[" + strings.Repeat(" ", maxImportBodySize) + "]", http.StatusRequestEntityTooLarge, codeBodyTooLarge},
+		"too large NDJSON line": {"application/x-ndjson", strings.Repeat("x", maxImportBodySize+1), http.StatusRequestEntityTooLarge, codeBodyTooLarge},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := serve(newTestRouter(), http.MethodPost, "/quotes/import", test.body, "Content-Type", test.contentType)
+			decodeProblem(t, rec, test.status, test.code)
+		})
+	}
+
+	rec := serve(newTestRouter(), http.MethodPost, "/quotes/import?dry_run=maybe", "[]", "Content-Type", "application/json")
+	if p := decodeProblem(t, rec, http.StatusBadRequest, codeValidationFailed); p.Field != "dry_run" {
+		t.Errorf("Expected the dry_run field to be named, got %+v", p)
+	}
+}
+
+func TestRouter_ImportModes(t *testing.T) {
+	const body = `[{"text":"First","author":"Seneca"},{"text":"","author":"Seneca"},{"text":"Third","author":"Seneca"}]`
+	count := func(t *testing.T, r *Router) int {
+		t.Helper()
+		var page domain.QuotePage
+		if err := json.NewDecoder(serve(r, http.MethodGet, "/quotes", "").Body).Decode(&page); err != nil {
+			t.Fatalf("Failed to decode quotes: %v", err)
+		}
+		return len(page.Quotes)
+	}
+
+	for name, test := range map[string]struct {
+		query    string
+		status   int
+		statuses []domain.ImportRowStatus
+		stored   int
+	}{
+		"atomic": {
+			"", http.StatusUnprocessableEntity,
+			[]domain.ImportRowStatus{domain.ImportRowSkipped, domain.ImportRowInvalid, domain.ImportRowSkipped}, 0,
+		},
+		"atomic dry run": {
+			"?dry_run=true", http.StatusOK,
+			[]domain.ImportRowStatus{domain.ImportRowValid, domain.ImportRowInvalid, domain.ImportRowValid}, 0,
+		},
+		"best effort": {
+			"?mode=best_effort", http.StatusOK,
+			[]domain.ImportRowStatus{domain.ImportRowCreated, domain.ImportRowInvalid, domain.ImportRowCreated}, 2,
+		},
+		"best effort dry run": {
+			"?mode=best_effort&dry_run=1", http.StatusOK,
+			[]domain.ImportRowStatus{domain.ImportRowValid, domain.ImportRowInvalid, domain.ImportRowValid}, 0,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := newTestRouter()
+			rec := serve(r, http.MethodPost, "/quotes/import"+test.query, body, "Content-Type", "application/json")
+			if rec.Code != test.status {
+				t.Fatalf("Expected status %d, got %d: %s", test.status, rec.Code, rec.Body)
+			}
+			report := decodeImportReport(t, rec.Code, rec.Body)
+			if report.Total != 3 || report.Failed != 1 || len(report.Rows) != 3 {
+				t.Fatalf("Unexpected report: %+v", report)
+			}
+			for i, status := range test.statuses {
+				if report.Rows[i].Status != status {
+					t.Errorf("Row %d: expected %s, got %+v", i+1, status, report.Rows[i])
+				}
+			}
+			if report.Rows[1].Error == "" {
+				t.Errorf("Expected the invalid row to say why, got %+v", report.Rows[1])
+			}
+			if stored := count(t, r); stored != test.stored {
+				t.Errorf("Expected %d stored quotes, got %d", test.stored, stored)
+			}
+		})
+	}
+}
+
+func TestRouter_ImportDryRunDuplicates(t *testing.T) {
+	r := newTestRouter()
+	existing := createTestQuote(t, r, "First", "Seneca")
+	const body = `[{"text":"first!","author":"SENECA"},{"text":"Second","author":"Seneca"}]`
+
+	rec := serve(r, http.MethodPost, "/quotes/import?dry_run=true", body, "Content-Type", "application/json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	report := decodeImportReport(t, rec.Code, rec.Body)
+	if report.Failed != 1 || report.Rows[0].Status != domain.ImportRowFailed || report.Rows[1].Status != domain.ImportRowValid {
+		t.Fatalf("Expected the existing quote to fail the dry run, got %+v", report)
+	}
+	if expected := "duplicate of quote " + existing.ID; report.Rows[0].Error != expected {
+		t.Errorf("Expected error %q, got %q", expected, report.Rows[0].Error)
+	}
+
+	rec = serve(r, http.MethodPost, "/quotes/import", body, "Content-Type", "application/json")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected the real import to fail as predicted, got %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"test-task-scout-go/internal/metrics"
+)
+
+// WithTimeout bounds every request's context by d. net/http does not cancel
+// the request context when the server's WriteTimeout passes, so without this a
+// slow query keeps running long after its response can no longer be written.
+func WithTimeout(next http.Handler, d time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), d)
+		defer cancel()
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// HTTPMetrics counts requests by route, method and status, and times them by
+// route and method.
+type HTTPMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+}
+
+func NewHTTPMetrics(registry *metrics.Registry) *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: registry.NewCounterVec("http_requests_total",
+			"Number of HTTP requests handled, by route, method and status code.", "route", "method", "status"),
+		duration: registry.NewHistogramVec("http_request_duration_seconds",
+			"Time taken to handle HTTP requests, by route and method.", metrics.DefaultBuckets, "route", "method"),
+	}
+}
+
+// WithMetrics records every request to next, which serves the routes of
+// routes, in m. Requests are labelled with the route pattern rather than the
+// path, so quote IDs do not each get a series of their own.
+//
+// Requests whose handler panics are recorded too, including exports aborted
+// with http.ErrAbortHandler; one that panics before writing a status counts
+// as a 500.
+func WithMetrics(next http.Handler, routes *Router, m *HTTPMetrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			if !completed && !recorder.wroteHeader {
+				recorder.status = http.StatusInternalServerError
+			}
+			route, method := routes.Route(req), metricMethod(req.Method)
+			m.requests.Inc(route, method, strconv.Itoa(recorder.status))
+			m.duration.Observe(time.Since(start).Seconds(), route, method)
+		}()
+		next.ServeHTTP(recorder, req)
+		completed = true
+	})
+}
+
+// metricMethod maps methods the API does not use to one label value, since
+// clients can send any method name.
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "OTHER"
+}
+
+// statusRecorder remembers the status code written through it. Unwrap lets
+// http.ResponseController reach the underlying writer, which the streaming
+// export flushes.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"test-task-scout-go/internal/metrics"
+)
+
+func TestWithMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewHTTPMetrics(registry)
+	routes := newTestRouter()
+	handler := WithMetrics(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Query().Get("fail") {
+		case "abort":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("partial"))
+			panic(http.ErrAbortHandler)
+		case "panic":
+			panic(errors.New("handler bug"))
+		}
+		routes.ServeHTTP(w, req)
+	}), routes, m)
+
+	serve(handler, http.MethodGet, "/quotes/export", "")
+	serve(handler, http.MethodGet, "/quotes/q-1", "")
+	for _, target := range []string{"/quotes/export?fail=abort", "/quotes/q-1?fail=panic"} {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered == nil {
+					t.Errorf("%s: expected the panic to reach the server", target)
+				}
+			}()
+			serve(handler, http.MethodGet, target, "")
+		}()
+	}
+
+	gathered := registry.Gather()
+	for _, line := range []string{
+		`http_requests_total{route="/quotes/export",method="GET",status="200"} 2`,
+		`http_requests_total{route="/quotes/{id}",method="GET",status="404"} 1`,
+		`http_requests_total{route="/quotes/{id}",method="GET",status="500"} 1`,
+		`http_request_duration_seconds_count{route="/quotes/export",method="GET"} 2`,
+		`http_request_duration_seconds_count{route="/quotes/{id}",method="GET"} 2`,
+	} {
+		if !strings.Contains(gathered, line+"\n") {
+			t.Errorf("Expected %s in:\n%s", line, gathered)
+		}
+	}
+}
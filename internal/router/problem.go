@@ -0,0 +1,150 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"test-task-scout-go/internal/service"
+)
+
+// Stable machine-readable error codes. Clients should branch on these rather
+// than on the human-readable detail, which may change.
+const (
+	codeNotFound             = "not_found"
+	codeAlreadyExists        = "already_exists"
+	codeDuplicateQuote       = "duplicate_quote"
+	codeValidationFailed     = "validation_failed"
+	codeVersionConflict      = "version_conflict"
+	codePreconditionFailed   = "precondition_failed"
+	codeInvalidJSON          = "invalid_json"
+	codeInvalidCSV           = "invalid_csv"
+	codeInvalidMultipart     = "invalid_multipart"
+	codeEmptyBody            = "empty_body"
+	codeBodyTooLarge         = "body_too_large"
+	codeUnsupportedMediaType = "unsupported_media_type"
+	codeMethodNotAllowed     = "method_not_allowed"
+	codeUnauthorized         = "unauthorized"
+	codeForbidden            = "forbidden"
+	codeTimeout              = "timeout"
+	codeRequestCanceled      = "request_canceled"
+	codeInternal             = "internal_error"
+)
+
+// problem is an RFC 7807 problem details object. Type is left as about:blank,
+// so Title is the HTTP status text and Code carries the specific error.
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	Field    string `json:"field,omitempty"`
+	// ExistingID names the stored quote that a duplicate_quote conflicts with,
+	// or the author that already has a name.
+	ExistingID string `json:"existing_id,omitempty"`
+}
+
+func writeProblem(w http.ResponseWriter, req *http.Request, status int, code, detail string) {
+	writeProblemDetails(w, problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: req.URL.Path,
+		Code:     code,
+	})
+}
+
+// writeFieldProblem reports a 400 validation failure tied to one input field.
+func writeFieldProblem(w http.ResponseWriter, req *http.Request, field, detail string) {
+	writeProblemDetails(w, problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(http.StatusBadRequest),
+		Status:   http.StatusBadRequest,
+		Detail:   detail,
+		Instance: req.URL.Path,
+		Code:     codeValidationFailed,
+		Field:    field,
+	})
+}
+
+func writeDuplicateProblem(w http.ResponseWriter, req *http.Request, duplicate *service.DuplicateError) {
+	detail := "Quote duplicates an existing quote"
+	if duplicate.Similarity < 1 {
+		detail = fmt.Sprintf("Quote is %.0f%% similar to an existing quote by the same author", duplicate.Similarity*100)
+	}
+	writeProblemDetails(w, problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(http.StatusConflict),
+		Status:     http.StatusConflict,
+		Detail:     detail,
+		Instance:   req.URL.Path,
+		Code:       codeDuplicateQuote,
+		ExistingID: duplicate.ExistingID,
+	})
+}
+
+func writeProblemDetails(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+func methodNotAllowed(w http.ResponseWriter, req *http.Request, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeProblem(w, req, http.StatusMethodNotAllowed, codeMethodNotAllowed, "Method not allowed")
+}
+
+// writeServiceError maps errors returned by the service onto problem
+// responses. Anything unrecognised is logged and reported as a 500 with the
+// given detail, so internal error text never reaches the client.
+func writeServiceError(w http.ResponseWriter, req *http.Request, err error, internalDetail string) {
+	var validationErr *service.ValidationError
+	var duplicateErr *service.DuplicateError
+	var authorExistsErr *service.AuthorExistsError
+	switch {
+	case errors.As(err, &validationErr):
+		writeFieldProblem(w, req, validationErr.Field, validationErr.Message)
+	case errors.Is(err, service.ErrValidation):
+		writeProblem(w, req, http.StatusBadRequest, codeValidationFailed, err.Error())
+	case errors.Is(err, service.ErrNoQuotes):
+		writeProblem(w, req, http.StatusNotFound, codeNotFound, "No quotes found")
+	case errors.Is(err, service.ErrAuthorNotFound):
+		writeProblem(w, req, http.StatusNotFound, codeNotFound, "Author not found")
+	case errors.Is(err, service.ErrNotFound):
+		writeProblem(w, req, http.StatusNotFound, codeNotFound, "Quote not found")
+	case errors.As(err, &duplicateErr):
+		writeDuplicateProblem(w, req, duplicateErr)
+	case errors.Is(err, service.ErrDuplicate):
+		writeProblem(w, req, http.StatusConflict, codeDuplicateQuote, "Quote duplicates an existing quote")
+	case errors.As(err, &authorExistsErr):
+		writeProblemDetails(w, problem{
+			Type:       "about:blank",
+			Title:      http.StatusText(http.StatusConflict),
+			Status:     http.StatusConflict,
+			Detail:     fmt.Sprintf("Author name %q is already taken", authorExistsErr.Name),
+			Instance:   req.URL.Path,
+			Code:       codeAlreadyExists,
+			ExistingID: authorExistsErr.ExistingID,
+		})
+	case errors.Is(err, service.ErrAlreadyExists):
+		writeProblem(w, req, http.StatusConflict, codeAlreadyExists, "Quote already exists")
+	case errors.Is(err, service.ErrVersionConflict):
+		writeProblem(w, req, http.StatusPreconditionFailed, codeVersionConflict, "Quote has been modified")
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Printf("%s %s: request timed out: %v", req.Method, req.URL.Path, err)
+		writeProblem(w, req, http.StatusServiceUnavailable, codeTimeout, "Request timed out")
+	case errors.Is(err, context.Canceled):
+		// The client has gone away; the response is written only for completeness.
+		writeProblem(w, req, http.StatusServiceUnavailable, codeRequestCanceled, "Request canceled")
+	default:
+		log.Printf("%s %s: %v", req.Method, req.URL.Path, err)
+		writeProblem(w, req, http.StatusInternalServerError, codeInternal, internalDetail)
+	}
+}
@@ -0,0 +1,58 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"test-task-scout-go/internal/repository"
+	"test-task-scout-go/internal/service"
+)
+
+func TestWriteServiceError(t *testing.T) {
+	for name, test := range map[string]struct {
+		err        error
+		status     int
+		code       string
+		detail     string
+		field      string
+		existingID string
+	}{
+		"not found":          {repository.ErrNotFound, http.StatusNotFound, codeNotFound, "Quote not found", "", ""},
+		"no quotes":          {repository.ErrNoQuotes, http.StatusNotFound, codeNotFound, "No quotes found", "", ""},
+		"author not found":   {repository.ErrAuthorNotFound, http.StatusNotFound, codeNotFound, "Author not found", "", ""},
+		"already exists":     {repository.ErrAlreadyExists, http.StatusConflict, codeAlreadyExists, "Quote already exists", "", ""},
+		"author exists":      {&repository.AuthorExistsError{ExistingID: "a-1", Name: "Seneca"}, http.StatusConflict, codeAlreadyExists, `Author name "Seneca" is already taken`, "", "a-1"},
+		"duplicate":          {repository.ErrDuplicate, http.StatusConflict, codeDuplicateQuote, "Quote duplicates an existing quote", "", ""},
+		"exact duplicate":    {&repository.DuplicateError{ExistingID: "q-1", Similarity: 1}, http.StatusConflict, codeDuplicateQuote, "Quote duplicates an existing quote", "", "q-1"},
+		"near duplicate":     {&repository.DuplicateError{ExistingID: "q-2", Similarity: 0.93}, http.StatusConflict, codeDuplicateQuote, "Quote is 93% similar to an existing quote by the same author", "", "q-2"},
+		"version conflict":   {repository.ErrVersionConflict, http.StatusPreconditionFailed, codeVersionConflict, "Quote has been modified", "", ""},
+		"validation":         {service.ErrValidation, http.StatusBadRequest, codeValidationFailed, "validation failed", "", ""},
+		"validation field":   {&service.ValidationError{Field: "tags", Message: "invalid tag: a b"}, http.StatusBadRequest, codeValidationFailed, "invalid tag: a b", "tags", ""},
+		"deadline exceeded":  {context.DeadlineExceeded, http.StatusServiceUnavailable, codeTimeout, "Request timed out", "", ""},
+		"canceled":           {context.Canceled, http.StatusServiceUnavailable, codeRequestCanceled, "Request canceled", "", ""},
+		"unknown":            {errors.New("disk on fire"), http.StatusInternalServerError, codeInternal, "Failed to do it", "", ""},
+		"wrapped not found":  {fmt.Errorf("failed to get quote from repository: %w", repository.ErrNotFound), http.StatusNotFound, codeNotFound, "Quote not found", "", ""},
+		"wrapped validation": {fmt.Errorf("failed to import: %w", &service.ValidationError{Field: "text", Message: "text is required"}), http.StatusBadRequest, codeValidationFailed, "text is required", "text", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeServiceError(rec, httptest.NewRequest(http.MethodGet, "/quotes/q-1", nil), test.err, "Failed to do it")
+
+			p := decodeProblem(t, rec, test.status, test.code)
+			if p.Detail != test.detail || p.Field != test.field || p.ExistingID != test.existingID {
+				t.Errorf("Expected detail %q, field %q and existing ID %q, got %+v", test.detail, test.field, test.existingID, p)
+			}
+			if p.Instance != "/quotes/q-1" {
+				t.Errorf("Expected instance /quotes/q-1, got %q", p.Instance)
+			}
+			if strings.Contains(rec.Body.String(), "disk on fire") {
+				t.Error("Expected internal error text not to reach the client")
+			}
+		})
+	}
+}
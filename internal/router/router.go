@@ -2,11 +2,16 @@ package router
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"test-task-scout-go/internal/domain"
 	"test-task-scout-go/internal/service"
 )
 
@@ -28,35 +33,135 @@ func NewRouter(service service.QuoteService) *Router {
 		case http.MethodGet:
 			r.getAllQuotesHandler(w, req)
 		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			methodNotAllowed(w, req, "GET, POST")
 		}
 	})
 
 	r.mux.HandleFunc("/quotes/", func(w http.ResponseWriter, req *http.Request) {
 		id := strings.TrimPrefix(req.URL.Path, "/quotes/")
 		if id == "" {
-			http.Error(w, "ID is required", http.StatusNotFound)
+			writeProblem(w, req, http.StatusNotFound, codeNotFound, "ID is required")
 			return
 		}
 
 		switch req.Method {
 		case http.MethodGet:
 			r.getQuoteByIDHandler(w, req, id)
+		case http.MethodPut:
+			r.updateQuoteHandler(w, req, id)
+		case http.MethodPatch:
+			r.patchQuoteHandler(w, req, id)
 		case http.MethodDelete:
 			r.deleteQuoteHandler(w, req, id)
 		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			methodNotAllowed(w, req, "GET, PUT, PATCH, DELETE")
 		}
 	})
 
+	r.mux.HandleFunc("/quotes/import", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			methodNotAllowed(w, req, "POST")
+			return
+		}
+		r.importQuotesHandler(w, req)
+	})
+
+	r.mux.HandleFunc("/quotes/export", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			methodNotAllowed(w, req, "GET")
+			return
+		}
+		r.exportQuotesHandler(w, req)
+	})
+
 	r.mux.HandleFunc("/quotes/random", func(w http.ResponseWriter, req *http.Request) {
 		if req.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			methodNotAllowed(w, req, "GET")
 			return
 		}
 		r.getRandomQuoteHandler(w, req)
 	})
 
+	r.mux.HandleFunc("/quotes/search", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			methodNotAllowed(w, req, "GET")
+			return
+		}
+		r.searchQuotesHandler(w, req)
+	})
+
+	r.mux.HandleFunc("/quotes/daily", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			methodNotAllowed(w, req, "GET")
+			return
+		}
+		r.getDailyQuoteHandler(w, req)
+	})
+
+	r.mux.HandleFunc("/quotes/daily/", func(w http.ResponseWriter, req *http.Request) {
+		date := strings.TrimPrefix(req.URL.Path, "/quotes/daily/")
+		switch req.Method {
+		case http.MethodGet:
+			r.writeDailyQuote(w, req, date)
+		case http.MethodPut:
+			r.pinDailyQuoteHandler(w, req, date)
+		case http.MethodDelete:
+			r.unpinDailyQuoteHandler(w, req, date)
+		default:
+			methodNotAllowed(w, req, "GET, PUT, DELETE")
+		}
+	})
+
+	r.mux.HandleFunc("/authors", func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			r.createAuthorHandler(w, req)
+		case http.MethodGet:
+			r.listAuthorsHandler(w, req)
+		default:
+			methodNotAllowed(w, req, "GET, POST")
+		}
+	})
+
+	r.mux.HandleFunc("/authors/", func(w http.ResponseWriter, req *http.Request) {
+		id, action, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/authors/"), "/")
+		if id == "" {
+			writeProblem(w, req, http.StatusNotFound, codeNotFound, "ID is required")
+			return
+		}
+
+		switch action {
+		case "":
+			if req.Method != http.MethodGet {
+				methodNotAllowed(w, req, "GET")
+				return
+			}
+			r.getAuthorHandler(w, req, id)
+		case "quotes":
+			if req.Method != http.MethodGet {
+				methodNotAllowed(w, req, "GET")
+				return
+			}
+			r.listAuthorQuotesHandler(w, req, id)
+		case "merge":
+			if req.Method != http.MethodPost {
+				methodNotAllowed(w, req, "POST")
+				return
+			}
+			r.mergeAuthorsHandler(w, req, id)
+		default:
+			writeProblem(w, req, http.StatusNotFound, codeNotFound, "Not found")
+		}
+	})
+
+	r.mux.HandleFunc("/tags", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			methodNotAllowed(w, req, "GET")
+			return
+		}
+		r.listTagsHandler(w, req)
+	})
+
 	return r
 }
 
@@ -64,111 +169,417 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	r.mux.ServeHTTP(w, req)
 }
 
-func (r *Router) createQuoteHandler(w http.ResponseWriter, req *http.Request) {
-	if req.Body == nil || req.ContentLength == 0 {
-		http.Error(w, "Request body is empty", http.StatusBadRequest)
-		return
+// Route names the route req is handled by, with path parameters in braces,
+// such as "/quotes/{id}"; requests matching no route give "unmatched".
+func (r *Router) Route(req *http.Request) string {
+	_, pattern := r.mux.Handler(req)
+	switch pattern {
+	case "":
+		return "unmatched"
+	case "/quotes/":
+		return "/quotes/{id}"
+	case "/quotes/daily/":
+		return "/quotes/daily/{date}"
+	case "/authors/":
+		_, action, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/authors/"), "/")
+		switch action {
+		case "":
+			return "/authors/{id}"
+		case "quotes", "merge":
+			return "/authors/{id}/" + action
+		}
+		return "unmatched"
 	}
+	return pattern
+}
 
-	var quoteData struct {
-		Text   string `json:"text"`
-		Author string `json:"author"`
+const maxRequestBodySize = 1048576
+
+// decodeJSONBody reads a single JSON value from the request body, writing the
+// error response itself and reporting false when the body is unusable.
+func decodeJSONBody(w http.ResponseWriter, req *http.Request, dst any) bool {
+	if req.Body == nil || req.ContentLength == 0 {
+		writeProblem(w, req, http.StatusBadRequest, codeEmptyBody, "Request body is empty")
+		return false
 	}
 
+	req.Body = http.MaxBytesReader(w, req.Body, maxRequestBodySize)
 	decoder := json.NewDecoder(req.Body)
-	req.Body = http.MaxBytesReader(w, req.Body, 1048576)
-	err := decoder.Decode(&quoteData)
+	err := decoder.Decode(dst)
 	if err != nil {
-		if err == io.EOF {
-			http.Error(w, "Request body is empty", http.StatusBadRequest)
-		} else if _, ok := err.(*json.SyntaxError); ok {
-			http.Error(w, "Invalid JSON format", http.StatusBadRequest)
-		} else if _, ok := err.(*json.UnmarshalTypeError); ok {
-			http.Error(w, "Invalid JSON data types", http.StatusBadRequest)
-		} else if err.Error() == "http: request body too large" {
-			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
-		} else {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, io.EOF):
+			writeProblem(w, req, http.StatusBadRequest, codeEmptyBody, "Request body is empty")
+		case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+			writeProblem(w, req, http.StatusBadRequest, codeInvalidJSON, "Invalid JSON format")
+		case errors.As(err, &typeErr):
+			writeProblem(w, req, http.StatusBadRequest, codeInvalidJSON, "Invalid JSON data types")
+		case errors.As(err, &maxBytesErr):
+			writeProblem(w, req, http.StatusRequestEntityTooLarge, codeBodyTooLarge, "Request body too large")
+		default:
 			log.Printf("Error decoding request body: %v", err)
-			http.Error(w, "Failed to read request body", http.StatusInternalServerError)
+			writeProblem(w, req, http.StatusInternalServerError, codeInternal, "Failed to read request body")
 		}
+		return false
+	}
+	return true
+}
+
+type quoteRequest struct {
+	Text   string   `json:"text"`
+	Author string   `json:"author"`
+	Tags   []string `json:"tags"`
+}
+
+func (r *Router) createQuoteHandler(w http.ResponseWriter, req *http.Request) {
+	var quoteData quoteRequest
+	if !decodeJSONBody(w, req, &quoteData) {
 		return
 	}
 
-	quote, err := r.service.CreateQuote(quoteData.Text, quoteData.Author)
+	quote, err := r.service.CreateQuote(req.Context(), quoteData.Text, quoteData.Author, quoteData.Tags, creator(req))
 	if err != nil {
-		if strings.Contains(err.Error(), "cannot be empty") {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		} else {
-			log.Printf("Error creating quote: %v", err)
-			http.Error(w, "Failed to create quote", http.StatusInternalServerError)
-		}
+		writeServiceError(w, req, err, "Failed to create quote")
 		return
 	}
 
+	writeQuote(w, http.StatusCreated, quote)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(quote)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeQuote(w http.ResponseWriter, status int, quote *domain.Quote) {
+	w.Header().Set("ETag", formatETag(quote.Version))
+	writeJSON(w, status, quote)
+}
+
+// parseLimit reads the optional limit query parameter; 0 means the default.
+func parseLimit(w http.ResponseWriter, req *http.Request) (int, bool) {
+	rawLimit := req.URL.Query().Get("limit")
+	if rawLimit == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit <= 0 {
+		writeFieldProblem(w, req, "limit", "Limit must be a positive integer")
+		return 0, false
+	}
+	return limit, true
+}
+
+// parseQuoteFilter reads the filter parameters shared by GET /quotes and
+// /quotes/random: author with author_match (exact, case_insensitive, prefix
+// or fuzzy), author_id, created_after, created_before, repeated tag with
+// tag_mode (any or all), min_length and max_length.
+func parseQuoteFilter(w http.ResponseWriter, req *http.Request) (domain.QuoteFilter, bool) {
+	params := req.URL.Query()
+	filter := domain.QuoteFilter{
+		Author:      params.Get("author"),
+		AuthorMatch: domain.AuthorMatch(params.Get("author_match")),
+		AuthorID:    params.Get("author_id"),
+		Tags:        params["tag"],
+		TagMode:     domain.TagMode(params.Get("tag_mode")),
+	}
+
+	var ok bool
+	if filter.CreatedAfter, ok = parseTimeParam(w, req, "created_after"); !ok {
+		return filter, false
+	}
+	if filter.CreatedBefore, ok = parseTimeParam(w, req, "created_before"); !ok {
+		return filter, false
+	}
+	if filter.MinLength, ok = parseIntParam(w, req, "min_length"); !ok {
+		return filter, false
+	}
+	if filter.MaxLength, ok = parseIntParam(w, req, "max_length"); !ok {
+		return filter, false
+	}
+	return filter, true
+}
+
+func parseIntParam(w http.ResponseWriter, req *http.Request, name string) (int, bool) {
+	raw := req.URL.Query().Get(name)
+	if raw == "" {
+		return 0, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		writeFieldProblem(w, req, name, name+" must be an integer")
+		return 0, false
+	}
+	return value, true
+}
+
+// parseTimeParam reads an optional RFC 3339 timestamp from the query string.
+func parseTimeParam(w http.ResponseWriter, req *http.Request, name string) (time.Time, bool) {
+	raw := req.URL.Query().Get(name)
+	if raw == "" {
+		return time.Time{}, true
+	}
+	t, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		writeFieldProblem(w, req, name, name+" must be an RFC 3339 timestamp")
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// parseListQuery reads the filter parameters together with sort (a field,
+// "-" prefixed for descending order), cursor and limit.
+func parseListQuery(w http.ResponseWriter, req *http.Request) (domain.ListQuery, bool) {
+	filter, ok := parseQuoteFilter(w, req)
+	if !ok {
+		return domain.ListQuery{}, false
+	}
+	params := req.URL.Query()
+	query := domain.ListQuery{
+		QuoteFilter: filter,
+		Cursor:      params.Get("cursor"),
+	}
+
+	if sortParam := params.Get("sort"); sortParam != "" {
+		query.Descending = strings.HasPrefix(sortParam, "-")
+		query.SortBy = domain.SortField(strings.TrimPrefix(sortParam, "-"))
+	}
+
+	limit, ok := parseLimit(w, req)
+	if !ok {
+		return domain.ListQuery{}, false
+	}
+	query.Limit = limit
+	return query, true
 }
 
 func (r *Router) getAllQuotesHandler(w http.ResponseWriter, req *http.Request) {
-	authorFilter := req.URL.Query().Get("author")
+	query, ok := parseListQuery(w, req)
+	if !ok {
+		return
+	}
 
-	quotes, err := r.service.GetAllQuotes(authorFilter)
+	page, err := r.service.ListQuotes(req.Context(), query)
 	if err != nil {
-		log.Printf("Error getting all quotes: %v", err)
-		http.Error(w, "Failed to retrieve quotes", http.StatusInternalServerError)
+		writeServiceError(w, req, err, "Failed to retrieve quotes")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(quotes)
+	writeJSON(w, http.StatusOK, page)
 }
 
 func (r *Router) getQuoteByIDHandler(w http.ResponseWriter, req *http.Request, id string) {
-	quote, err := r.service.GetByID(id)
+	quote, err := r.service.GetByID(req.Context(), id)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, "Quote not found", http.StatusNotFound)
-		} else {
-			log.Printf("Error getting quote by ID %s: %v", id, err)
-			http.Error(w, "Failed to retrieve quote", http.StatusInternalServerError)
-		}
+		writeServiceError(w, req, err, "Failed to retrieve quote")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(quote)
+	if header := req.Header.Get("If-None-Match"); header != "" && noneMatch(header, quote.Version) {
+		w.Header().Set("ETag", formatETag(quote.Version))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeQuote(w, http.StatusOK, quote)
 }
 
 func (r *Router) getRandomQuoteHandler(w http.ResponseWriter, req *http.Request) {
-	quote, err := r.service.GetRandomQuote()
+	filter, ok := parseQuoteFilter(w, req)
+	if !ok {
+		return
+	}
+
+	quote, err := r.service.GetRandomQuote(req.Context(), filter)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to retrieve random quote")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, quote)
+}
+
+func (r *Router) updateQuoteHandler(w http.ResponseWriter, req *http.Request, id string) {
+	var quoteData quoteRequest
+	if !decodeJSONBody(w, req, &quoteData) {
+		return
+	}
+
+	version, ok := r.ifMatchVersion(w, req, id)
+	if !ok {
+		return
+	}
+
+	quote, err := r.service.UpdateQuote(req.Context(), id, quoteData.Text, quoteData.Author, quoteData.Tags, version)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, "No quotes found", http.StatusNotFound)
-		} else {
-			log.Printf("Error getting random quote: %v", err)
-			http.Error(w, "Failed to retrieve random quote", http.StatusInternalServerError)
+		writeServiceError(w, req, err, "Failed to update quote")
+		return
+	}
+
+	writeQuote(w, http.StatusOK, quote)
+}
+
+// patchQuoteHandler applies an RFC 7396 JSON Merge Patch. A null member removes
+// the field, which for the required text and author fails validation.
+func (r *Router) patchQuoteHandler(w http.ResponseWriter, req *http.Request, id string) {
+	contentType := req.Header.Get("Content-Type")
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/merge-patch+json" && mediaType != "application/json") {
+			w.Header().Set("Accept-Patch", "application/merge-patch+json")
+			writeProblem(w, req, http.StatusUnsupportedMediaType, codeUnsupportedMediaType, "Content-Type must be application/merge-patch+json")
+			return
 		}
+	}
+
+	var members map[string]json.RawMessage
+	if !decodeJSONBody(w, req, &members) {
+		return
+	}
+	if members == nil {
+		writeProblem(w, req, http.StatusBadRequest, codeInvalidJSON, "Merge patch must be a JSON object")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(quote)
+	var patch domain.QuotePatch
+	if raw, ok := members["tags"]; ok {
+		var tags []string
+		if err := json.Unmarshal(raw, &tags); err != nil {
+			writeProblem(w, req, http.StatusBadRequest, codeInvalidJSON, "Invalid JSON data types")
+			return
+		}
+		patch.Tags = &tags
+	}
+	for name, target := range map[string]**string{"text": &patch.Text, "author": &patch.Author} {
+		raw, ok := members[name]
+		if !ok {
+			continue
+		}
+		value := ""
+		if string(raw) != "null" {
+			if err := json.Unmarshal(raw, &value); err != nil {
+				writeProblem(w, req, http.StatusBadRequest, codeInvalidJSON, "Invalid JSON data types")
+				return
+			}
+		}
+		*target = &value
+	}
+
+	version, ok := r.ifMatchVersion(w, req, id)
+	if !ok {
+		return
+	}
+
+	quote, err := r.service.PatchQuote(req.Context(), id, patch, version)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to update quote")
+		return
+	}
+
+	writeQuote(w, http.StatusOK, quote)
 }
 
 func (r *Router) deleteQuoteHandler(w http.ResponseWriter, req *http.Request, id string) {
-	err := r.service.DeleteQuote(id)
+	version, ok := r.ifMatchVersion(w, req, id)
+	if !ok {
+		return
+	}
+
+	err := r.service.DeleteQuote(req.Context(), id, version)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to delete quote")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *Router) searchQuotesHandler(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query().Get("q")
+
+	limit, ok := parseLimit(w, req)
+	if !ok {
+		return
+	}
+
+	results, err := r.service.SearchQuotes(req.Context(), query, limit)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to search quotes")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (r *Router) listTagsHandler(w http.ResponseWriter, req *http.Request) {
+	tags, err := r.service.ListTags(req.Context())
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, "Quote not found", http.StatusNotFound)
-		} else {
-			log.Printf("Error deleting quote: %v", err)
-			http.Error(w, "Failed to delete quote", http.StatusInternalServerError)
+		writeServiceError(w, req, err, "Failed to retrieve tags")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// getDailyQuoteHandler serves the quote of the day. Without date it uses today
+// in tz (an IANA zone name, UTC by default).
+func (r *Router) getDailyQuoteHandler(w http.ResponseWriter, req *http.Request) {
+	params := req.URL.Query()
+	date := params.Get("date")
+	if date == "" {
+		loc := time.UTC
+		if tz := params.Get("tz"); tz != "" {
+			var err error
+			loc, err = time.LoadLocation(tz)
+			if err != nil {
+				writeFieldProblem(w, req, "tz", "Unknown time zone: "+tz)
+				return
+			}
 		}
+		date = time.Now().In(loc).Format(service.DailyDateLayout)
+	}
+
+	r.writeDailyQuote(w, req, date)
+}
+
+func (r *Router) writeDailyQuote(w http.ResponseWriter, req *http.Request, date string) {
+	daily, err := r.service.GetDailyQuote(req.Context(), date)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to retrieve daily quote")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, daily)
+}
+
+type pinRequest struct {
+	QuoteID string `json:"quote_id"`
+}
+
+func (r *Router) pinDailyQuoteHandler(w http.ResponseWriter, req *http.Request, date string) {
+	var pin pinRequest
+	if !decodeJSONBody(w, req, &pin) {
+		return
+	}
+
+	daily, err := r.service.PinDailyQuote(req.Context(), date, pin.QuoteID)
+	if err != nil {
+		writeServiceError(w, req, err, "Failed to pin daily quote")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, daily)
+}
+
+func (r *Router) unpinDailyQuoteHandler(w http.ResponseWriter, req *http.Request, date string) {
+	if err := r.service.UnpinDailyQuote(req.Context(), date); err != nil {
+		writeServiceError(w, req, err, "Failed to unpin daily quote")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/repository"
+	"test-task-scout-go/internal/service"
+)
+
+func newTestRouter() *Router {
+	return NewRouter(service.NewQuoteService(repository.NewInMemoryRepository()))
+}
+
+// serve sends a request to handler; header holds pairs of names and values.
+func serve(handler http.Handler, method, target, body string, header ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func createTestQuote(t *testing.T, handler http.Handler, text, author string) domain.Quote {
+	t.Helper()
+	body, err := json.Marshal(quoteRequest{Text: text, Author: author})
+	if err != nil {
+		t.Fatalf("Failed to encode quote: %v", err)
+	}
+	rec := serve(handler, http.MethodPost, "/quotes", string(body))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var quote domain.Quote
+	if err := json.NewDecoder(rec.Body).Decode(&quote); err != nil {
+		t.Fatalf("Failed to decode quote: %v", err)
+	}
+	return quote
+}
+
+// decodeProblem checks that rec holds a problem response with status and
+// code, and returns it.
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) problem {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("Expected status %d, got %d: %s", status, rec.Code, rec.Body)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Expected problem+json content type, got %q", contentType)
+	}
+	var p problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	if p.Type != "about:blank" || p.Title != http.StatusText(status) || p.Status != status || p.Code != code {
+		t.Errorf("Expected %d %s problem, got %+v", status, code, p)
+	}
+	return p
+}
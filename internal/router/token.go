@@ -0,0 +1,62 @@
+package router
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"test-task-scout-go/internal/jwt"
+)
+
+var (
+	errInvalidToken = errors.New("invalid token")
+	errExpiredToken = errors.New("token has expired")
+)
+
+// TokenAuth authenticates requests by a JWT bearer token. Tokens get the
+// widest Scope whose token scope they grant: adminScope, writeScope or
+// readScope. Other tokens identify the caller without allowing anything.
+type TokenAuth struct {
+	verifier   *jwt.Verifier
+	readScope  string
+	writeScope string
+	adminScope string
+}
+
+func NewTokenAuth(verifier *jwt.Verifier, readScope, writeScope, adminScope string) *TokenAuth {
+	return &TokenAuth{verifier: verifier, readScope: readScope, writeScope: writeScope, adminScope: adminScope}
+}
+
+// Authenticate logs why a token was refused, since the client is told only
+// whether it has expired.
+func (a *TokenAuth) Authenticate(req *http.Request) (*Principal, error) {
+	scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	claims, err := a.verifier.Verify(strings.TrimSpace(token))
+	if errors.Is(err, jwt.ErrExpired) {
+		return nil, errExpiredToken
+	}
+	if err != nil {
+		log.Printf("%s %s: refused bearer token: %v", req.Method, req.URL.Path, err)
+		return nil, errInvalidToken
+	}
+
+	principal := &Principal{Name: claims.Subject}
+	switch {
+	case claims.HasScope(a.adminScope):
+		principal.Scope = ScopeAdmin
+	case claims.HasScope(a.writeScope):
+		principal.Scope = ScopeWrite
+	case claims.HasScope(a.readScope):
+		principal.Scope = ScopeRead
+	}
+	return principal, nil
+}
+
+func (a *TokenAuth) Challenge() string {
+	return `Bearer realm="quotes"`
+}
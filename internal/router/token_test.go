@@ -0,0 +1,148 @@
+package router
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/jwt"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// signTestToken signs an HS256 token for the test issuer and audience with
+// the given subject, scope and expiry.
+func signTestToken(t *testing.T, secret []byte, subject, scope string, expires time.Time) string {
+	t.Helper()
+	encode := base64.RawURLEncoding.EncodeToString
+	claims, err := json.Marshal(map[string]any{
+		"iss": "https://gateway.example", "aud": "quotes", "sub": subject,
+		"exp": expires.Unix(), "scope": scope,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode claims: %v", err)
+	}
+	signed := encode([]byte(`{"alg":"HS256","kid":"test"}`)) + "." + encode(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + encode(mac.Sum(nil))
+}
+
+func newTestTokenAuth(t *testing.T) *TokenAuth {
+	t.Helper()
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]any{
+		{"kty": "oct", "kid": "test", "alg": "HS256", "k": base64.RawURLEncoding.EncodeToString(testSecret)},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to encode JWKS: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("Failed to write JWKS: %v", err)
+	}
+	keys, err := jwt.LoadKeySource(path, time.Minute)
+	if err != nil {
+		t.Fatalf("LoadKeySource failed: %v", err)
+	}
+	verifier := jwt.NewVerifier(keys, "https://gateway.example", "quotes")
+	return NewTokenAuth(verifier, "quotes:read", "quotes:write", "quotes:admin")
+}
+
+func TestTokenAuth_Scopes(t *testing.T) {
+	handler := WithAuth(allowed, false, newTestTokenAuth(t))
+	requests := []struct{ method, target string }{
+		{http.MethodGet, "/quotes/q-1"},
+		{http.MethodPut, "/quotes/q-1"},
+		{http.MethodDelete, "/quotes/daily/2024-03-01"},
+	}
+
+	for name, test := range map[string]struct {
+		scope    string
+		expected []int
+	}{
+		"write":   {"quotes:write", []int{http.StatusNoContent, http.StatusNoContent, http.StatusForbidden}},
+		"read":    {"openid quotes:read", []int{http.StatusNoContent, http.StatusForbidden, http.StatusForbidden}},
+		"admin":   {"quotes:read quotes:admin", []int{http.StatusNoContent, http.StatusNoContent, http.StatusNoContent}},
+		"neither": {"profile email", []int{http.StatusForbidden, http.StatusForbidden, http.StatusForbidden}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			bearer := "Bearer " + signTestToken(t, testSecret, "alice", test.scope, time.Now().Add(time.Hour))
+			for i, r := range requests {
+				if rec := serve(handler, r.method, r.target, "", "Authorization", bearer); rec.Code != test.expected[i] {
+					t.Errorf("%s %s: expected %d, got %d", r.method, r.target, test.expected[i], rec.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenAuth_Refused(t *testing.T) {
+	handler := WithAuth(allowed, false, newTestTokenAuth(t))
+
+	for name, test := range map[string]struct {
+		header string
+		detail string
+	}{
+		"expired": {
+			"Bearer " + signTestToken(t, testSecret, "alice", "quotes:write", time.Now().Add(-time.Hour)),
+			"Credentials were refused: token has expired",
+		},
+		"wrong secret": {
+			"Bearer " + signTestToken(t, []byte("fedcba9876543210fedcba9876543210"), "alice", "quotes:write", time.Now().Add(time.Hour)),
+			"Credentials were refused: invalid token",
+		},
+		"malformed": {"bearer not-a-token", "Credentials were refused: invalid token"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := serve(handler, http.MethodGet, "/quotes", "", "Authorization", test.header)
+			p := decodeProblem(t, rec, http.StatusUnauthorized, codeUnauthorized)
+			if p.Detail != test.detail {
+				t.Errorf("Expected detail %q, got %q", test.detail, p.Detail)
+			}
+			if challenge := rec.Header().Get("WWW-Authenticate"); challenge != `Bearer realm="quotes"` {
+				t.Errorf("Expected a bearer challenge, got %q", challenge)
+			}
+		})
+	}
+}
+
+func TestTokenAuth_OtherSchemes(t *testing.T) {
+	handler := WithAuth(allowed, false, newTestTokenAuth(t), NewAPIKeyAuth([]APIKey{testAPIKey("ci", ScopeWrite)}))
+
+	rec := serve(handler, http.MethodPost, "/quotes", "", "Authorization", "Basic Y2k6c2VjcmV0", APIKeyHeader, "ci-secret")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected a Basic header to fall through to the API key, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(handler, http.MethodPost, "/quotes", "", "Authorization", "Basic Y2k6c2VjcmV0")
+	if p := decodeProblem(t, rec, http.StatusUnauthorized, codeUnauthorized); p.Detail != "Credentials are required" {
+		t.Errorf("Expected a Basic header to count as no credentials, got %q", p.Detail)
+	}
+	if challenges := rec.Header().Values("WWW-Authenticate"); len(challenges) != 2 {
+		t.Errorf("Expected a challenge for each authenticator, got %q", challenges)
+	}
+}
+
+func TestTokenAuth_Creator(t *testing.T) {
+	handler := WithAuth(newTestRouter(), false, newTestTokenAuth(t))
+	bearer := "Bearer " + signTestToken(t, testSecret, "svc-importer", "quotes:write", time.Now().Add(time.Hour))
+
+	rec := serve(handler, http.MethodPost, "/quotes", `{"text":"Text","author":"Author"}`, "Authorization", bearer)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var quote domain.Quote
+	if err := json.NewDecoder(rec.Body).Decode(&quote); err != nil {
+		t.Fatalf("Failed to decode quote: %v", err)
+	}
+	if quote.CreatedBy != "svc-importer" {
+		t.Errorf("Expected the token subject as creator, got %q", quote.CreatedBy)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"test-task-scout-go/internal/domain"
+)
+
+const (
+	MaxAuthorBioLength  = 2000
+	MaxAliasesPerAuthor = 20
+)
+
+func (s *QuoteServiceImpl) CreateAuthor(ctx context.Context, name, bio string, aliases []string) (*domain.Author, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, validationError("name", "name cannot be empty")
+	}
+	bio = strings.TrimSpace(bio)
+	if utf8.RuneCountInString(bio) > MaxAuthorBioLength {
+		return nil, validationError("bio", fmt.Sprintf("bio cannot be longer than %d characters", MaxAuthorBioLength))
+	}
+	if len(aliases) > MaxAliasesPerAuthor {
+		return nil, validationError("aliases", fmt.Sprintf("an author can have at most %d aliases", MaxAliasesPerAuthor))
+	}
+	trimmed := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			return nil, validationError("aliases", "alias cannot be empty")
+		}
+		trimmed = append(trimmed, alias)
+	}
+
+	author := &domain.Author{Name: name, Bio: bio, Aliases: trimmed}
+	if err := s.repo.CreateAuthor(ctx, author); err != nil {
+		return nil, fmt.Errorf("failed to create author in repository: %w", err)
+	}
+	return author, nil
+}
+
+func (s *QuoteServiceImpl) GetAuthor(ctx context.Context, id string) (*domain.Author, error) {
+	if id == "" {
+		return nil, validationError("id", "ID cannot be empty")
+	}
+	author, err := s.repo.GetAuthor(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author from repository: %w", err)
+	}
+	return author, nil
+}
+
+func (s *QuoteServiceImpl) ListAuthors(ctx context.Context) ([]domain.Author, error) {
+	authors, err := s.repo.ListAuthors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authors from repository: %w", err)
+	}
+	return authors, nil
+}
+
+// ListAuthorQuotes pages through the quotes of one author like ListQuotes; a
+// missing author is reported as not found rather than as an empty page.
+func (s *QuoteServiceImpl) ListAuthorQuotes(ctx context.Context, id string, query domain.ListQuery) (*domain.QuotePage, error) {
+	if _, err := s.GetAuthor(ctx, id); err != nil {
+		return nil, err
+	}
+	query.AuthorID = id
+	return s.ListQuotes(ctx, query)
+}
+
+// MergeAuthors folds the author sourceID into targetID; see
+// repository.AuthorRepository.
+func (s *QuoteServiceImpl) MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error) {
+	if targetID == "" {
+		return nil, validationError("id", "ID cannot be empty")
+	}
+	if sourceID == "" {
+		return nil, validationError("author_id", "author_id cannot be empty")
+	}
+	if sourceID == targetID {
+		return nil, validationError("author_id", "an author cannot be merged into itself")
+	}
+	author, err := s.repo.MergeAuthors(ctx, targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge authors in repository: %w", err)
+	}
+	return author, nil
+}
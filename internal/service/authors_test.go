@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"errors"
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/service"
+	"testing"
+)
+
+func TestQuoteService_CreateAuthor(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var stored *domain.Author
+		mockRepo := &MockQuoteRepository{
+			CreateAuthorFunc: func(author *domain.Author) error {
+				stored = author
+				author.ID = "author-1"
+				return nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		author, err := quoteService.CreateAuthor(t.Context(), "  Панда По ", " Воин дракона ", []string{" Po "})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if author.ID != "author-1" || stored.Name != "Панда По" || stored.Bio != "Воин дракона" {
+			t.Errorf("Expected trimmed author, got %+v", stored)
+		}
+		if len(stored.Aliases) != 1 || stored.Aliases[0] != "Po" {
+			t.Errorf("Expected trimmed aliases, got %v", stored.Aliases)
+		}
+	})
+
+	t.Run("ValidationErrors", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{})
+
+		for field, args := range map[string]struct {
+			name    string
+			aliases []string
+		}{
+			"name":    {name: "  "},
+			"aliases": {name: "Панда По", aliases: []string{""}},
+		} {
+			_, err := quoteService.CreateAuthor(t.Context(), args.name, "", args.aliases)
+			var verr *service.ValidationError
+			if !errors.As(err, &verr) || verr.Field != field {
+				t.Errorf("Expected validation error on %s, got %v", field, err)
+			}
+		}
+	})
+
+	t.Run("NameTaken", func(t *testing.T) {
+		mockRepo := &MockQuoteRepository{
+			CreateAuthorFunc: func(author *domain.Author) error {
+				return &service.AuthorExistsError{ExistingID: "author-1", Name: author.Name}
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		_, err := quoteService.CreateAuthor(t.Context(), "панда по", "", nil)
+		var exists *service.AuthorExistsError
+		if !errors.As(err, &exists) || exists.ExistingID != "author-1" || !errors.Is(err, service.ErrAlreadyExists) {
+			t.Errorf("Expected author exists error, got %v", err)
+		}
+	})
+}
+
+func TestQuoteService_ListAuthorQuotes(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		GetAuthorFunc: func(id string) (*domain.Author, error) {
+			if id != "author-1" {
+				return nil, service.ErrAuthorNotFound
+			}
+			return &domain.Author{ID: id}, nil
+		},
+		ListFunc: func(query domain.ListQuery) (*domain.QuotePage, error) {
+			if query.AuthorID != "author-1" || query.Limit != service.DefaultPageLimit {
+				t.Errorf("Unexpected query: %+v", query)
+			}
+			return &domain.QuotePage{Quotes: []domain.Quote{{ID: "1", AuthorID: "author-1"}}}, nil
+		},
+	}
+	quoteService := service.NewQuoteService(mockRepo)
+
+	page, err := quoteService.ListAuthorQuotes(t.Context(), "author-1", domain.ListQuery{})
+	if err != nil || len(page.Quotes) != 1 {
+		t.Fatalf("Expected one quote, got %+v, %v", page, err)
+	}
+
+	_, err = quoteService.ListAuthorQuotes(t.Context(), "missing", domain.ListQuery{})
+	if !errors.Is(err, service.ErrAuthorNotFound) || !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected author not found, got %v", err)
+	}
+}
+
+func TestQuoteService_MergeAuthors(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		MergeAuthorsFunc: func(targetID, sourceID string) (*domain.Author, error) {
+			return &domain.Author{ID: targetID, Aliases: []string{sourceID}}, nil
+		},
+	}
+	quoteService := service.NewQuoteService(mockRepo)
+
+	author, err := quoteService.MergeAuthors(t.Context(), "author-1", "author-2")
+	if err != nil || author.ID != "author-1" {
+		t.Fatalf("Expected merged author-1, got %+v, %v", author, err)
+	}
+
+	_, err = quoteService.MergeAuthors(t.Context(), "author-1", "author-1")
+	var verr *service.ValidationError
+	if !errors.As(err, &verr) || verr.Field != "author_id" {
+		t.Errorf("Expected validation error on author_id, got %v", err)
+	}
+}
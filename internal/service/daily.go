@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// DailyDateLayout is the calendar date format used for the quote of the day.
+const DailyDateLayout = time.DateOnly
+
+func parseDailyDate(date string) error {
+	if _, err := time.Parse(DailyDateLayout, date); err != nil {
+		return validationError("date", "date must be in YYYY-MM-DD format")
+	}
+	return nil
+}
+
+// dailyScore ranks a quote for a date. The quote with the highest score wins
+// (rendezvous hashing), so the choice depends only on the date and the set of
+// IDs: every instance agrees regardless of storage or restarts, and adding a
+// quote changes a day's pick only if the new quote itself wins.
+func dailyScore(date, id string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(date))
+	h.Write([]byte{0})
+	h.Write([]byte(id))
+	return h.Sum64()
+}
+
+// pickDaily keeps only the best quote seen so far, so the quotes are never
+// held in memory all at once.
+func pickDaily(date string, quotes iter.Seq2[domain.Quote, error]) (*domain.Quote, error) {
+	var best *domain.Quote
+	var bestScore uint64
+	for quote, err := range quotes {
+		if err != nil {
+			return nil, err
+		}
+		score := dailyScore(date, quote.ID)
+		if best == nil || score > bestScore || (score == bestScore && quote.ID < best.ID) {
+			best, bestScore = &quote, score
+		}
+	}
+	return best, nil
+}
+
+// GetDailyQuote returns the quote for date, preferring a pinned quote. A pin
+// whose quote has since been deleted is ignored.
+func (s *QuoteServiceImpl) GetDailyQuote(ctx context.Context, date string) (*domain.DailyQuote, error) {
+	if err := parseDailyDate(date); err != nil {
+		return nil, err
+	}
+
+	quoteID, err := s.repo.GetDailyPin(ctx, date)
+	switch {
+	case err == nil:
+		quote, err := s.repo.GetByID(ctx, quoteID)
+		if err == nil {
+			return &domain.DailyQuote{Date: date, Pinned: true, Quote: *quote}, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("failed to get pinned quote from repository: %w", err)
+		}
+	case !errors.Is(err, ErrNotFound):
+		return nil, fmt.Errorf("failed to get daily pin from repository: %w", err)
+	}
+
+	quote, err := pickDaily(date, s.repo.Iterate(ctx, domain.QuoteFilter{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate quotes from repository: %w", err)
+	}
+	if quote == nil {
+		return nil, ErrNoQuotes
+	}
+	return &domain.DailyQuote{Date: date, Quote: *quote}, nil
+}
+
+func (s *QuoteServiceImpl) PinDailyQuote(ctx context.Context, date, quoteID string) (*domain.DailyQuote, error) {
+	if err := parseDailyDate(date); err != nil {
+		return nil, err
+	}
+	if quoteID == "" {
+		return nil, validationError("quote_id", "quote_id cannot be empty")
+	}
+
+	if err := s.repo.SetDailyPin(ctx, date, quoteID); err != nil {
+		return nil, fmt.Errorf("failed to pin daily quote in repository: %w", err)
+	}
+	return s.GetDailyQuote(ctx, date)
+}
+
+func (s *QuoteServiceImpl) UnpinDailyQuote(ctx context.Context, date string) error {
+	if err := parseDailyDate(date); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteDailyPin(ctx, date); err != nil {
+		return fmt.Errorf("failed to unpin daily quote in repository: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/repository"
+	"test-task-scout-go/internal/service"
+	"testing"
+)
+
+func newDailyRepo(quotes []domain.Quote, pins map[string]string) *MockQuoteRepository {
+	return &MockQuoteRepository{
+		IterateFunc: func(filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+			return func(yield func(domain.Quote, error) bool) {
+				for _, quote := range quotes {
+					if !yield(quote, nil) {
+						return
+					}
+				}
+			}
+		},
+		GetByIDFunc: func(id string) (*domain.Quote, error) {
+			for _, quote := range quotes {
+				if quote.ID == id {
+					return &quote, nil
+				}
+			}
+			return nil, repository.ErrNotFound
+		},
+		GetDailyPinFunc: func(date string) (string, error) {
+			quoteID, ok := pins[date]
+			if !ok {
+				return "", repository.ErrNotFound
+			}
+			return quoteID, nil
+		},
+	}
+}
+
+func TestQuoteService_GetDailyQuote(t *testing.T) {
+	quotes := make([]domain.Quote, 20)
+	for i := range quotes {
+		quotes[i] = domain.Quote{ID: fmt.Sprintf("q-%d", i), Text: "Text", Author: "Author"}
+	}
+	reversed := make([]domain.Quote, len(quotes))
+	for i, quote := range quotes {
+		reversed[len(quotes)-1-i] = quote
+	}
+
+	t.Run("Deterministic", func(t *testing.T) {
+		first := service.NewQuoteService(newDailyRepo(quotes, nil))
+		second := service.NewQuoteService(newDailyRepo(reversed, nil))
+
+		picked := make(map[string]bool)
+		for day := 1; day <= 10; day++ {
+			date := fmt.Sprintf("2024-03-%02d", day)
+			a, err := first.GetDailyQuote(t.Context(), date)
+			if err != nil {
+				t.Fatalf("GetDailyQuote failed: %v", err)
+			}
+			b, err := second.GetDailyQuote(t.Context(), date)
+			if err != nil {
+				t.Fatalf("GetDailyQuote failed: %v", err)
+			}
+			if a.Quote.ID != b.Quote.ID || a.Pinned || a.Date != date {
+				t.Errorf("%s: expected the same unpinned quote regardless of order, got %+v and %+v", date, a, b)
+			}
+			picked[a.Quote.ID] = true
+		}
+		if len(picked) < 2 {
+			t.Errorf("Expected different quotes across days, got %v", picked)
+		}
+	})
+
+	t.Run("StableWhenOthersAreAdded", func(t *testing.T) {
+		before, err := service.NewQuoteService(newDailyRepo(quotes[:10], nil)).GetDailyQuote(t.Context(), "2024-03-01")
+		if err != nil {
+			t.Fatalf("GetDailyQuote failed: %v", err)
+		}
+		after, err := service.NewQuoteService(newDailyRepo(quotes, nil)).GetDailyQuote(t.Context(), "2024-03-01")
+		if err != nil {
+			t.Fatalf("GetDailyQuote failed: %v", err)
+		}
+		// Only one of the added quotes may take over the day.
+		for _, quote := range quotes[:10] {
+			if after.Quote.ID == quote.ID && quote.ID != before.Quote.ID {
+				t.Errorf("Adding quotes moved the pick from %s to an older quote %s", before.Quote.ID, after.Quote.ID)
+			}
+		}
+	})
+
+	t.Run("Pinned", func(t *testing.T) {
+		quoteService := service.NewQuoteService(newDailyRepo(quotes, map[string]string{"2024-03-01": "q-7"}))
+
+		daily, err := quoteService.GetDailyQuote(t.Context(), "2024-03-01")
+		if err != nil {
+			t.Fatalf("GetDailyQuote failed: %v", err)
+		}
+		if !daily.Pinned || daily.Quote.ID != "q-7" {
+			t.Errorf("Expected pinned quote q-7, got %+v", daily)
+		}
+	})
+
+	t.Run("PinnedQuoteDeleted", func(t *testing.T) {
+		quoteService := service.NewQuoteService(newDailyRepo(quotes, map[string]string{"2024-03-01": "gone"}))
+
+		daily, err := quoteService.GetDailyQuote(t.Context(), "2024-03-01")
+		if err != nil {
+			t.Fatalf("GetDailyQuote failed: %v", err)
+		}
+		if daily.Pinned || daily.Quote.ID == "gone" {
+			t.Errorf("Expected fallback to the computed quote, got %+v", daily)
+		}
+	})
+
+	t.Run("NoQuotes", func(t *testing.T) {
+		quoteService := service.NewQuoteService(newDailyRepo(nil, nil))
+
+		_, err := quoteService.GetDailyQuote(t.Context(), "2024-03-01")
+		if !errors.Is(err, service.ErrNoQuotes) {
+			t.Errorf("Expected ErrNoQuotes, got: %v", err)
+		}
+	})
+
+	t.Run("IterateError", func(t *testing.T) {
+		mockRepo := newDailyRepo(quotes, nil)
+		mockRepo.IterateFunc = func(filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+			return func(yield func(domain.Quote, error) bool) {
+				if yield(quotes[0], nil) {
+					yield(domain.Quote{}, errors.New("connection lost"))
+				}
+			}
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		_, err := quoteService.GetDailyQuote(t.Context(), "2024-03-01")
+		if err == nil || !strings.Contains(err.Error(), "connection lost") {
+			t.Errorf("Expected the repository error, got: %v", err)
+		}
+	})
+
+	t.Run("InvalidDate", func(t *testing.T) {
+		quoteService := service.NewQuoteService(newDailyRepo(quotes, nil))
+
+		_, err := quoteService.GetDailyQuote(t.Context(), "01.03.2024")
+		var validationErr *service.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "date" {
+			t.Errorf("Expected a validation error for field 'date', got: %v", err)
+		}
+	})
+}
+
+func TestQuoteService_PinDailyQuote(t *testing.T) {
+	pins := make(map[string]string)
+	mockRepo := newDailyRepo([]domain.Quote{{ID: "q-1"}, {ID: "q-2"}}, pins)
+	mockRepo.SetDailyPinFunc = func(date, quoteID string) error {
+		if quoteID != "q-1" && quoteID != "q-2" {
+			return repository.ErrNotFound
+		}
+		pins[date] = quoteID
+		return nil
+	}
+	mockRepo.DeleteDailyPinFunc = func(date string) error {
+		if _, ok := pins[date]; !ok {
+			return repository.ErrNotFound
+		}
+		delete(pins, date)
+		return nil
+	}
+	quoteService := service.NewQuoteService(mockRepo)
+
+	daily, err := quoteService.PinDailyQuote(t.Context(), "2024-03-01", "q-2")
+	if err != nil {
+		t.Fatalf("PinDailyQuote failed: %v", err)
+	}
+	if !daily.Pinned || daily.Quote.ID != "q-2" {
+		t.Errorf("Expected pinned quote q-2, got %+v", daily)
+	}
+
+	_, err = quoteService.PinDailyQuote(t.Context(), "2024-03-01", "missing")
+	if !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when pinning a missing quote, got: %v", err)
+	}
+
+	if err := quoteService.UnpinDailyQuote(t.Context(), "2024-03-01"); err != nil {
+		t.Fatalf("UnpinDailyQuote failed: %v", err)
+	}
+	if err := quoteService.UnpinDailyQuote(t.Context(), "2024-03-01"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when unpinning twice, got: %v", err)
+	}
+}
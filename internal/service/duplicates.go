@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// bigrams counts the pairs of adjacent runes in s. A string shorter than two
+// runes counts as a single gram so that it can still match itself.
+func bigrams(s string) map[string]int {
+	runes := []rune(s)
+	grams := make(map[string]int, len(runes))
+	if len(runes) < 2 {
+		grams[s]++
+		return grams
+	}
+	for i := 0; i < len(runes)-1; i++ {
+		grams[string(runes[i:i+2])]++
+	}
+	return grams
+}
+
+// similarity is the Sørensen–Dice coefficient of two bigram multisets: 1 for
+// identical texts, 0 for texts without a common pair of characters.
+func similarity(a, b map[string]int) float64 {
+	total, common := 0, 0
+	for gram, countA := range a {
+		total += countA
+		common += min(countA, b[gram])
+	}
+	for _, countB := range b {
+		total += countB
+	}
+	if total == 0 {
+		return 1
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+// findNearDuplicate looks for the stored quote by the same author whose text
+// is most similar to text, and reports it if the similarity reaches the
+// configured threshold. The quote excludeID, the one being edited, is not
+// compared with itself. It scans every quote, which is why it is opt-in.
+func (s *QuoteServiceImpl) findNearDuplicate(ctx context.Context, text, author, excludeID string) error {
+	if s.nearDuplicateThreshold <= 0 {
+		return nil
+	}
+
+	author = domain.AuthorKey(author)
+	grams := bigrams(domain.NormalizeForMatch(text))
+	var best *DuplicateError
+	for quote, err := range s.repo.Iterate(ctx, domain.QuoteFilter{}) {
+		if err != nil {
+			return fmt.Errorf("failed to check for near duplicates in repository: %w", err)
+		}
+		if quote.ID == excludeID || domain.AuthorKey(quote.Author) != author {
+			continue
+		}
+		score := similarity(grams, bigrams(domain.NormalizeForMatch(quote.Text)))
+		if score >= s.nearDuplicateThreshold && (best == nil || score > best.Similarity) {
+			best = &DuplicateError{ExistingID: quote.ID, Similarity: score}
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return nil
+}
+
+// findStoredDuplicates checks a batch of new quotes against the stored ones in
+// a single scan and returns an error per quote, nil for a quote without a
+// duplicate. Near duplicates are looked for when the check is enabled, and
+// exact ones, which the repository reports on its own when storing, only when
+// exact is set.
+func (s *QuoteServiceImpl) findStoredDuplicates(ctx context.Context, quotes []*domain.Quote, exact bool) ([]error, error) {
+	dupErrs := make([]error, len(quotes))
+	near := s.nearDuplicateThreshold > 0
+	if len(quotes) == 0 || (!exact && !near) {
+		return dupErrs, nil
+	}
+
+	fingerprints := make(map[string]int, len(quotes))
+	byAuthor := make(map[string][]int)
+	grams := make([]map[string]int, len(quotes))
+	for i, quote := range quotes {
+		fingerprints[domain.Fingerprint(quote.Text, quote.Author)] = i
+		if near {
+			key := domain.AuthorKey(quote.Author)
+			byAuthor[key] = append(byAuthor[key], i)
+			grams[i] = bigrams(domain.NormalizeForMatch(quote.Text))
+		}
+	}
+
+	best := make([]*DuplicateError, len(quotes))
+	for stored, err := range s.repo.Iterate(ctx, domain.QuoteFilter{}) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for duplicates in repository: %w", err)
+		}
+		if exact {
+			if i, ok := fingerprints[domain.Fingerprint(stored.Text, stored.Author)]; ok {
+				best[i] = &DuplicateError{ExistingID: stored.ID, Similarity: 1}
+				continue
+			}
+		}
+		candidates := byAuthor[domain.AuthorKey(stored.Author)]
+		if len(candidates) == 0 {
+			continue
+		}
+		storedGrams := bigrams(domain.NormalizeForMatch(stored.Text))
+		for _, i := range candidates {
+			score := similarity(grams[i], storedGrams)
+			if score >= s.nearDuplicateThreshold && (best[i] == nil || score > best[i].Similarity) {
+				best[i] = &DuplicateError{ExistingID: stored.ID, Similarity: score}
+			}
+		}
+	}
+	for i, duplicate := range best {
+		if duplicate != nil {
+			dupErrs[i] = duplicate
+		}
+	}
+	return dupErrs, nil
+}
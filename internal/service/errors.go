@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+
+	"test-task-scout-go/internal/repository"
+)
+
+var (
+	ErrNotFound        = repository.ErrNotFound
+	ErrNoQuotes        = repository.ErrNoQuotes
+	ErrAlreadyExists   = repository.ErrAlreadyExists
+	ErrVersionConflict = repository.ErrVersionConflict
+	ErrDuplicate       = repository.ErrDuplicate
+	ErrAuthorNotFound  = repository.ErrAuthorNotFound
+	ErrValidation      = errors.New("validation failed")
+)
+
+// DuplicateError identifies the existing quote that a new one repeats.
+type DuplicateError = repository.DuplicateError
+
+// AuthorExistsError identifies the author that already goes by a name.
+type AuthorExistsError = repository.AuthorExistsError
+
+// ValidationError describes input rejected by the service. It matches
+// ErrValidation, and Field names the offending input when there is one.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+func validationError(field, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
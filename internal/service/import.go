@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"test-task-scout-go/internal/domain"
+)
+
+// MaxImportRows caps the number of quotes accepted by a single import.
+const MaxImportRows = 5000
+
+// ImportQuotes validates every row and stores the valid ones in a single
+// repository batch. Row problems are reported in the returned report rather
+// than as an error; the error result is reserved for a request that cannot be
+// processed at all.
+func (s *QuoteServiceImpl) ImportQuotes(ctx context.Context, rows []domain.QuoteInput, opts domain.ImportOptions) (*domain.ImportReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = domain.ImportAtomic
+	}
+	if !opts.Mode.Valid() {
+		return nil, validationError("mode", fmt.Sprintf("invalid import mode: %s", opts.Mode))
+	}
+	if len(rows) == 0 {
+		return nil, validationError("", "import contains no quotes")
+	}
+	if len(rows) > MaxImportRows {
+		return nil, validationError("", fmt.Sprintf("import cannot contain more than %d quotes", MaxImportRows))
+	}
+	if opts.CreatedBy == "" {
+		opts.CreatedBy = AnonymousCreator
+	}
+
+	report := &domain.ImportReport{
+		Mode:   opts.Mode,
+		DryRun: opts.DryRun,
+		Total:  len(rows),
+		Rows:   make([]domain.ImportRowResult, len(rows)),
+	}
+
+	now := time.Now().UTC()
+	var batch []*domain.Quote
+	var batchRows []int
+	// firstRows remembers the first row with each fingerprint, so repeats
+	// within the upload are reported against a row rather than a quote ID.
+	firstRows := make(map[string]int, len(rows))
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Row = i + 1
+
+		tags, err := validateTags(row.Tags)
+		if err == nil {
+			err = validateQuote(row.Text, row.Author)
+		}
+		if err != nil {
+			var verr *ValidationError
+			errors.As(err, &verr)
+			result.Status = domain.ImportRowInvalid
+			result.Field = verr.Field
+			result.Error = verr.Message
+			report.Failed++
+			continue
+		}
+
+		fingerprint := domain.Fingerprint(row.Text, row.Author)
+		if first, ok := firstRows[fingerprint]; ok {
+			result.Status = domain.ImportRowInvalid
+			result.Error = fmt.Sprintf("duplicate of row %d", first)
+			report.Failed++
+			continue
+		}
+		firstRows[fingerprint] = result.Row
+
+		result.Status = domain.ImportRowValid
+		batch = append(batch, &domain.Quote{
+			ID:        s.ids.NewID(),
+			Text:      row.Text,
+			Author:    row.Author,
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: opts.CreatedBy,
+			Tags:      tags,
+		})
+		batchRows = append(batchRows, i)
+	}
+
+	// A dry run looks for exact duplicates itself, as it never reaches the
+	// repository, so that it reports what a real import would.
+	dupErrs, err := s.findStoredDuplicates(ctx, batch, opts.DryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import quotes: %w", err)
+	}
+	kept := batch[:0]
+	keptRows := batchRows[:0]
+	for j, dupErr := range dupErrs {
+		if dupErr != nil {
+			result := &report.Rows[batchRows[j]]
+			result.Status = domain.ImportRowFailed
+			result.Error = dupErr.Error()
+			report.Failed++
+			continue
+		}
+		kept = append(kept, batch[j])
+		keptRows = append(keptRows, batchRows[j])
+	}
+	batch, batchRows = kept, keptRows
+
+	rejected := opts.Mode == domain.ImportAtomic && report.Failed > 0
+	if opts.DryRun || rejected || len(batch) == 0 {
+		if rejected && !opts.DryRun {
+			markSkipped(report)
+		}
+		return report, nil
+	}
+
+	rowErrs, err := s.createBatch(ctx, batch, opts.Mode == domain.ImportAtomic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import quotes into repository: %w", err)
+	}
+	for j, i := range batchRows {
+		result := &report.Rows[i]
+		if rowErrs[j] != nil {
+			result.Status = domain.ImportRowFailed
+			result.Error = rowErrs[j].Error()
+			report.Failed++
+			continue
+		}
+		result.Status = domain.ImportRowCreated
+		result.ID = batch[j].ID
+	}
+	if opts.Mode == domain.ImportAtomic && report.Failed > 0 {
+		markSkipped(report)
+		return report, nil
+	}
+	report.Imported = report.Total - report.Failed
+	return report, nil
+}
+
+// createBatch stores the batch, drawing new IDs for rows whose ID turned out
+// to be taken and storing them again, up to maxIDAttempts times like
+// CreateQuote. An atomic batch stored nothing when a row failed, so it is
+// retried whole, and only while taken IDs are its sole failures.
+func (s *QuoteServiceImpl) createBatch(ctx context.Context, batch []*domain.Quote, atomic bool) ([]error, error) {
+	rowErrs, err := s.repo.CreateBatch(ctx, batch, atomic)
+	for attempt := 2; err == nil && attempt <= maxIDAttempts; attempt++ {
+		var taken []int
+		failed := 0
+		for j, rowErr := range rowErrs {
+			if rowErr == nil {
+				continue
+			}
+			failed++
+			if errors.Is(rowErr, ErrAlreadyExists) {
+				taken = append(taken, j)
+			}
+		}
+		if len(taken) == 0 || (atomic && failed > len(taken)) {
+			break
+		}
+		for _, j := range taken {
+			batch[j].ID = s.ids.NewID()
+		}
+
+		if atomic {
+			rowErrs, err = s.repo.CreateBatch(ctx, batch, true)
+			continue
+		}
+		retry := make([]*domain.Quote, len(taken))
+		for k, j := range taken {
+			retry[k] = batch[j]
+		}
+		var retryErrs []error
+		retryErrs, err = s.repo.CreateBatch(ctx, retry, false)
+		if err != nil {
+			break
+		}
+		for k, j := range taken {
+			rowErrs[j] = retryErrs[k]
+		}
+	}
+	return rowErrs, err
+}
+
+// markSkipped records that an atomic import stored none of its valid rows.
+func markSkipped(report *domain.ImportReport) {
+	for i := range report.Rows {
+		switch report.Rows[i].Status {
+		case domain.ImportRowValid, domain.ImportRowCreated:
+			report.Rows[i].Status = domain.ImportRowSkipped
+			report.Rows[i].ID = ""
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/repository"
+	"test-task-scout-go/internal/service"
+	"testing"
+)
+
+func TestQuoteService_ImportQuotes(t *testing.T) {
+	rows := []domain.QuoteInput{
+		{Text: "First", Author: "Author", Tags: []string{"Wisdom"}},
+		{Text: "", Author: "Author"},
+		{Text: "Third", Author: "Author", Tags: []string{"bad tag"}},
+		{Text: "Fourth", Author: "Author"},
+	}
+
+	t.Run("BestEffort", func(t *testing.T) {
+		var stored []*domain.Quote
+		mockRepo := &MockQuoteRepository{
+			CreateBatchFunc: func(quotes []*domain.Quote, atomic bool) ([]error, error) {
+				if atomic {
+					t.Error("Expected a best-effort batch")
+				}
+				stored = quotes
+				return []error{nil, &repository.DuplicateError{ExistingID: "q-1", Similarity: 1}}, nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		report, err := quoteService.ImportQuotes(t.Context(), rows, domain.ImportOptions{Mode: domain.ImportBestEffort})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(stored) != 2 || stored[0].ID == stored[1].ID {
+			t.Fatalf("Expected two quotes with distinct IDs in the batch, got %+v", stored)
+		}
+		if stored[0].CreatedBy != service.AnonymousCreator || len(stored[0].Tags) != 1 || stored[0].Tags[0] != "wisdom" {
+			t.Errorf("Expected normalized tags and anonymous creator, got %+v", stored[0])
+		}
+		if report.Total != 4 || report.Imported != 1 || report.Failed != 3 {
+			t.Errorf("Unexpected counts: %+v", report)
+		}
+
+		want := []domain.ImportRowStatus{domain.ImportRowCreated, domain.ImportRowInvalid, domain.ImportRowInvalid, domain.ImportRowFailed}
+		for i, status := range want {
+			if report.Rows[i].Row != i+1 || report.Rows[i].Status != status {
+				t.Errorf("Row %d: expected %s, got %+v", i+1, status, report.Rows[i])
+			}
+		}
+		if report.Rows[0].ID != stored[0].ID {
+			t.Errorf("Expected created row to carry ID %s, got %q", stored[0].ID, report.Rows[0].ID)
+		}
+		if report.Rows[2].Field != "tags" {
+			t.Errorf("Expected tags field on invalid row, got %q", report.Rows[2].Field)
+		}
+	})
+
+	t.Run("AtomicRejectsInvalidRows", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{})
+
+		report, err := quoteService.ImportQuotes(t.Context(), rows, domain.ImportOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Mode != domain.ImportAtomic || report.Imported != 0 || report.Failed != 2 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+		if report.Rows[0].Status != domain.ImportRowSkipped || report.Rows[3].Status != domain.ImportRowSkipped {
+			t.Errorf("Expected valid rows to be skipped, got %+v", report.Rows)
+		}
+	})
+
+	t.Run("AtomicRepositoryConflict", func(t *testing.T) {
+		mockRepo := &MockQuoteRepository{
+			CreateBatchFunc: func(quotes []*domain.Quote, atomic bool) ([]error, error) {
+				return []error{&repository.DuplicateError{ExistingID: "q-1", Similarity: 1}, nil}, nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		report, err := quoteService.ImportQuotes(t.Context(), []domain.QuoteInput{rows[0], rows[3]}, domain.ImportOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Imported != 0 || report.Rows[0].Status != domain.ImportRowFailed || report.Rows[1].Status != domain.ImportRowSkipped {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("RetriesTakenIDs", func(t *testing.T) {
+		for _, mode := range []domain.ImportMode{domain.ImportAtomic, domain.ImportBestEffort} {
+			t.Run(string(mode), func(t *testing.T) {
+				stored := map[string]string{"taken": "Existing"}
+				var batches []string
+				mockRepo := &MockQuoteRepository{
+					CreateBatchFunc: func(quotes []*domain.Quote, atomic bool) ([]error, error) {
+						ids := make([]string, len(quotes))
+						rowErrs := make([]error, len(quotes))
+						failed := false
+						for i, quote := range quotes {
+							ids[i] = quote.ID
+							if _, ok := stored[quote.ID]; ok {
+								rowErrs[i] = repository.ErrAlreadyExists
+								failed = true
+							}
+						}
+						batches = append(batches, strings.Join(ids, ","))
+						if atomic && failed {
+							return rowErrs, nil
+						}
+						for i, quote := range quotes {
+							if rowErrs[i] == nil {
+								stored[quote.ID] = quote.Text
+							}
+						}
+						return rowErrs, nil
+					},
+				}
+				ids := &sequenceIDs{ids: []string{"a", "taken", "taken", "b"}}
+				quoteService := service.NewQuoteService(mockRepo, service.WithIDGenerator(ids))
+
+				report, err := quoteService.ImportQuotes(t.Context(), []domain.QuoteInput{rows[0], rows[3]}, domain.ImportOptions{Mode: mode})
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if report.Imported != 2 || report.Failed != 0 || report.Rows[1].ID != "b" || stored["b"] != "Fourth" {
+					t.Errorf("Expected the taken ID to be replaced, got %+v", report)
+				}
+				expected := "a,taken|a,taken|a,b"
+				if mode == domain.ImportBestEffort {
+					expected = "a,taken|taken|b"
+				}
+				if got := strings.Join(batches, "|"); got != expected {
+					t.Errorf("Expected batches %s, got %s", expected, got)
+				}
+			})
+		}
+	})
+
+	t.Run("GivesUpOnTakenIDs", func(t *testing.T) {
+		calls := 0
+		mockRepo := &MockQuoteRepository{
+			CreateBatchFunc: func(quotes []*domain.Quote, atomic bool) ([]error, error) {
+				calls++
+				rowErrs := make([]error, len(quotes))
+				for i := range rowErrs {
+					rowErrs[i] = repository.ErrAlreadyExists
+				}
+				return rowErrs, nil
+			},
+		}
+		ids := &sequenceIDs{ids: []string{"a", "b", "c", "d"}}
+		quoteService := service.NewQuoteService(mockRepo, service.WithIDGenerator(ids))
+
+		report, err := quoteService.ImportQuotes(t.Context(), rows[:1], domain.ImportOptions{Mode: domain.ImportBestEffort})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if calls != 3 || report.Rows[0].Status != domain.ImportRowFailed {
+			t.Errorf("Expected the row to fail after 3 attempts, got %+v after %d", report.Rows[0], calls)
+		}
+	})
+
+	t.Run("AtomicKeepsIDsWithOtherFailures", func(t *testing.T) {
+		calls := 0
+		mockRepo := &MockQuoteRepository{
+			CreateBatchFunc: func(quotes []*domain.Quote, atomic bool) ([]error, error) {
+				calls++
+				return []error{repository.ErrAlreadyExists, &repository.DuplicateError{ExistingID: "q-1", Similarity: 1}}, nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		report, err := quoteService.ImportQuotes(t.Context(), []domain.QuoteInput{rows[0], rows[3]}, domain.ImportOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if calls != 1 || report.Failed != 2 {
+			t.Errorf("Expected no retry of a batch that fails anyway, got %+v after %d calls", report, calls)
+		}
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{IterateFunc: iterateQuotes()})
+
+		report, err := quoteService.ImportQuotes(t.Context(), rows, domain.ImportOptions{Mode: domain.ImportBestEffort, DryRun: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !report.DryRun || report.Imported != 0 || report.Failed != 2 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+		if report.Rows[0].Status != domain.ImportRowValid || report.Rows[0].ID != "" {
+			t.Errorf("Expected a valid row without ID, got %+v", report.Rows[0])
+		}
+	})
+
+	t.Run("DryRunPredictsDuplicates", func(t *testing.T) {
+		stored := []domain.Quote{
+			{ID: "q-1", Text: "First!", Author: "AUTHOR"},
+			{ID: "q-2", Text: "The fourth quote", Author: "Author"},
+		}
+		input := []domain.QuoteInput{{Text: "First", Author: "Author"}, {Text: "Fourth quote", Author: "Author"}, {Text: "Fifth", Author: "Author"}}
+		var batch []*domain.Quote
+		mockRepo := &MockQuoteRepository{
+			IterateFunc: iterateQuotes(stored...),
+			CreateBatchFunc: func(quotes []*domain.Quote, atomic bool) ([]error, error) {
+				batch = quotes
+				return make([]error, len(quotes)), nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo, service.WithNearDuplicateThreshold(0.8))
+
+		opts := domain.ImportOptions{Mode: domain.ImportBestEffort, DryRun: true}
+		dryRun, err := quoteService.ImportQuotes(t.Context(), input, opts)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if batch != nil {
+			t.Fatal("Expected a dry run not to store anything")
+		}
+		opts.DryRun = false
+		report, err := quoteService.ImportQuotes(t.Context(), input, opts)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(batch) != 1 || batch[0].Text != "Fifth" {
+			t.Errorf("Expected the duplicates to be left out of the batch, got %+v", batch)
+		}
+
+		want := []struct {
+			status domain.ImportRowStatus
+			err    string
+		}{
+			{domain.ImportRowFailed, "duplicate of quote q-1"},
+			{domain.ImportRowFailed, "duplicate of quote q-2"},
+			{domain.ImportRowValid, ""},
+		}
+		if dryRun.Failed != 2 || report.Failed != 2 || report.Imported != 1 {
+			t.Errorf("Expected both runs to fail two rows, got %+v and %+v", dryRun, report)
+		}
+		for i, row := range want {
+			if dryRun.Rows[i].Status != row.status || dryRun.Rows[i].Error != row.err {
+				t.Errorf("Row %d: expected %s %q, got %+v", i+1, row.status, row.err, dryRun.Rows[i])
+			}
+			if report.Rows[i].Error != row.err {
+				t.Errorf("Row %d: expected the import to fail with %q, got %+v", i+1, row.err, report.Rows[i])
+			}
+		}
+	})
+
+	t.Run("DuplicateRows", func(t *testing.T) {
+		mockRepo := &MockQuoteRepository{
+			CreateBatchFunc: func(quotes []*domain.Quote, atomic bool) ([]error, error) {
+				if len(quotes) != 1 {
+					t.Errorf("Expected repeated rows to be left out of the batch, got %d quotes", len(quotes))
+				}
+				return make([]error, len(quotes)), nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		report, err := quoteService.ImportQuotes(t.Context(), []domain.QuoteInput{
+			{Text: "Stay hungry, stay foolish.", Author: "Steve Jobs"},
+			{Text: "stay hungry stay foolish", Author: "steve jobs"},
+		}, domain.ImportOptions{Mode: domain.ImportBestEffort})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if report.Imported != 1 || report.Rows[1].Status != domain.ImportRowInvalid || report.Rows[1].Error != "duplicate of row 1" {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{})
+
+		_, err := quoteService.ImportQuotes(t.Context(), rows, domain.ImportOptions{Mode: "partial"})
+		var verr *service.ValidationError
+		if !errors.As(err, &verr) || verr.Field != "mode" {
+			t.Errorf("Expected validation error on mode, got %v", err)
+		}
+
+		_, err = quoteService.ImportQuotes(t.Context(), nil, domain.ImportOptions{})
+		if !errors.Is(err, service.ErrValidation) {
+			t.Errorf("Expected validation error for empty import, got %v", err)
+		}
+	})
+}
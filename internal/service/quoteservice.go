@@ -1,63 +1,231 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strconv"
+	"iter"
+	"strings"
 	"test-task-scout-go/internal/domain"
+	"test-task-scout-go/internal/idgen"
 	"test-task-scout-go/internal/repository"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+
+	// AnonymousCreator is recorded as CreatedBy when the caller is unknown.
+	AnonymousCreator = "anonymous"
+
+	MaxTagsPerQuote = 10
+	MaxTagLength    = 32
+
+	// maxIDAttempts bounds how often CreateQuote and ImportQuotes draw a new
+	// ID after the generated one turned out to be taken.
+	maxIDAttempts = 3
+)
+
+// IDGenerator produces IDs for new quotes. Implementations must be safe for
+// concurrent use; package idgen has several.
+type IDGenerator interface {
+	NewID() string
+}
+
 type QuoteServiceImpl struct {
 	repo repository.QuoteRepository
+	ids  IDGenerator
+
+	nearDuplicateThreshold float64
 }
 
-func NewQuoteService(repo repository.QuoteRepository) *QuoteServiceImpl {
-	return &QuoteServiceImpl{repo: repo}
+// Option configures optional QuoteServiceImpl behaviour.
+type Option func(*QuoteServiceImpl)
+
+// WithNearDuplicateThreshold makes CreateQuote also reject quotes whose text is
+// at least threshold similar (between 0 and 1) to a quote by the same author.
+// Exact duplicates are always rejected; zero leaves the near check off.
+func WithNearDuplicateThreshold(threshold float64) Option {
+	return func(s *QuoteServiceImpl) {
+		s.nearDuplicateThreshold = threshold
+	}
+}
+
+// WithIDGenerator sets how IDs of new quotes are generated. The default is
+// random UUIDs (version 4).
+func WithIDGenerator(ids IDGenerator) Option {
+	return func(s *QuoteServiceImpl) {
+		s.ids = ids
+	}
 }
 
-func (s *QuoteServiceImpl) CreateQuote(text, author string) (*domain.Quote, error) {
+func NewQuoteService(repo repository.QuoteRepository, opts ...Option) *QuoteServiceImpl {
+	s := &QuoteServiceImpl{repo: repo, ids: idgen.UUIDv4{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func validateQuote(text, author string) error {
 	if text == "" || author == "" {
-		return nil, errors.New("text and author cannot be empty")
+		return validationError("", "text and author cannot be empty")
 	}
+	return nil
+}
 
-	// Простая генерация ID на основе времени. В ТЗ сказанно, не использовать сторонние библеотеки,
-	// хотя я бы здесь генерировал UUID используя github.com/google/uuid
-	id := strconv.FormatInt(time.Now().UnixNano(), 10)
+// validateTags normalizes tags and checks that each one is a short word made of
+// letters, digits, '-' or '_'.
+func validateTags(tags []string) ([]string, error) {
+	tags = domain.NormalizeTags(tags)
+	if len(tags) > MaxTagsPerQuote {
+		return nil, validationError("tags", fmt.Sprintf("a quote can have at most %d tags", MaxTagsPerQuote))
+	}
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, validationError("tags", fmt.Sprintf("tag is longer than %d characters: %s", MaxTagLength, tag))
+		}
+		for _, r := range tag {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+				return nil, validationError("tags", fmt.Sprintf("invalid tag: %s", tag))
+			}
+		}
+	}
+	return tags, nil
+}
 
-	quote := &domain.Quote{
-		ID:     id,
-		Text:   text,
-		Author: author,
+func validateFilter(filter domain.QuoteFilter) (domain.QuoteFilter, error) {
+	if filter.TagMode == "" {
+		filter.TagMode = domain.TagModeAny
+	}
+	if !filter.TagMode.Valid() {
+		return filter, validationError("tag_mode", fmt.Sprintf("invalid tag mode: %s", filter.TagMode))
+	}
+	if filter.AuthorMatch == "" {
+		filter.AuthorMatch = domain.AuthorMatchExact
+	}
+	if !filter.AuthorMatch.Valid() {
+		return filter, validationError("author_match", fmt.Sprintf("invalid author match mode: %s", filter.AuthorMatch))
 	}
+	if filter.Author != "" && filter.AuthorMatch != domain.AuthorMatchExact && domain.AuthorKey(filter.Author) == "" {
+		return filter, validationError("author", "author cannot be blank")
+	}
+	filter.Tags = domain.NormalizeTags(filter.Tags)
+	if filter.MinLength < 0 {
+		return filter, validationError("min_length", "min_length cannot be negative")
+	}
+	if filter.MaxLength < 0 {
+		return filter, validationError("max_length", "max_length cannot be negative")
+	}
+	if filter.MaxLength > 0 && filter.MinLength > filter.MaxLength {
+		return filter, validationError("max_length", "max_length cannot be less than min_length")
+	}
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return filter, validationError("created_before", "created_before must be later than created_after")
+	}
+	return filter, nil
+}
 
-	err := s.repo.Create(quote)
+func (s *QuoteServiceImpl) CreateQuote(ctx context.Context, text, author string, tags []string, createdBy string) (*domain.Quote, error) {
+	if err := validateQuote(text, author); err != nil {
+		return nil, err
+	}
+	tags, err := validateTags(tags)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create quote in repository: %w", err)
+		return nil, err
+	}
+	if createdBy == "" {
+		createdBy = AnonymousCreator
+	}
+	if err := s.findNearDuplicate(ctx, text, author, ""); err != nil {
+		return nil, err
 	}
 
-	return quote, nil
+	now := time.Now().UTC()
+	quote := &domain.Quote{
+		Text:      text,
+		Author:    author,
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: createdBy,
+		Tags:      tags,
+	}
+
+	for attempt := 1; ; attempt++ {
+		quote.ID = s.ids.NewID()
+		err = s.repo.Create(ctx, quote)
+		if err == nil {
+			return quote, nil
+		}
+		if !errors.Is(err, ErrAlreadyExists) || attempt == maxIDAttempts {
+			return nil, fmt.Errorf("failed to create quote in repository: %w", err)
+		}
+	}
 }
 
-func (s *QuoteServiceImpl) GetAllQuotes(author string) ([]domain.Quote, error) {
-	if author == "" {
-		quotes, err := s.repo.GetAll()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get all quotes from repository: %w", err)
+func (s *QuoteServiceImpl) ListQuotes(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error) {
+	if query.SortBy == "" {
+		query.SortBy = domain.SortByID
+	}
+	if !query.SortBy.Valid() {
+		return nil, validationError("sort", fmt.Sprintf("invalid sort field: %s", query.SortBy))
+	}
+	filter, err := validateFilter(query.QuoteFilter)
+	if err != nil {
+		return nil, err
+	}
+	query.QuoteFilter = filter
+	if query.Limit <= 0 {
+		query.Limit = DefaultPageLimit
+	}
+	if query.Limit > MaxPageLimit {
+		query.Limit = MaxPageLimit
+	}
+
+	page, err := s.repo.List(ctx, query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, validationError("cursor", "invalid cursor")
 		}
-		return quotes, nil
+		return nil, fmt.Errorf("failed to list quotes from repository: %w", err)
 	}
+	return page, nil
+}
 
-	quotes, err := s.repo.GetByAuthor(author)
+// ExportQuotes streams every quote matching filter in ID order. Errors from the
+// repository arrive through the sequence, after which it ends.
+func (s *QuoteServiceImpl) ExportQuotes(ctx context.Context, filter domain.QuoteFilter) (iter.Seq2[domain.Quote, error], error) {
+	filter, err := validateFilter(filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get quotes by author from repository: %w", err)
+		return nil, err
 	}
-	return quotes, nil
+
+	return func(yield func(domain.Quote, error) bool) {
+		for quote, err := range s.repo.Iterate(ctx, filter) {
+			if err != nil {
+				yield(domain.Quote{}, fmt.Errorf("failed to export quotes from repository: %w", err))
+				return
+			}
+			if !yield(quote, nil) {
+				return
+			}
+		}
+	}, nil
 }
 
-func (s *QuoteServiceImpl) GetRandomQuote() (*domain.Quote, error) {
-	quote, err := s.repo.GetRandom()
+func (s *QuoteServiceImpl) GetRandomQuote(ctx context.Context, filter domain.QuoteFilter) (*domain.Quote, error) {
+	filter, err := validateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.repo.GetRandom(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get random quote from repository: %w", err)
 	}
@@ -65,23 +233,129 @@ func (s *QuoteServiceImpl) GetRandomQuote() (*domain.Quote, error) {
 	return quote, nil
 }
 
-func (s *QuoteServiceImpl) DeleteQuote(id string) error {
+// UpdateQuote replaces the quote's content. A non-zero version makes the write
+// conditional on the stored version, as with every other mutation.
+func (s *QuoteServiceImpl) UpdateQuote(ctx context.Context, id, text, author string, tags []string, version int64) (*domain.Quote, error) {
 	if id == "" {
-		return errors.New("ID cannot be empty")
+		return nil, validationError("id", "ID cannot be empty")
+	}
+	if err := validateQuote(text, author); err != nil {
+		return nil, err
+	}
+	tags, err := validateTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.findNearDuplicate(ctx, text, author, id); err != nil {
+		return nil, err
+	}
+
+	quote := &domain.Quote{
+		ID:        id,
+		Text:      text,
+		Author:    author,
+		Version:   version,
+		UpdatedAt: time.Now().UTC(),
+		Tags:      tags,
+	}
+
+	if err := s.repo.Update(ctx, quote); err != nil {
+		return nil, fmt.Errorf("failed to update quote in repository: %w", err)
+	}
+
+	return quote, nil
+}
+
+func (s *QuoteServiceImpl) PatchQuote(ctx context.Context, id string, patch domain.QuotePatch, version int64) (*domain.Quote, error) {
+	if id == "" {
+		return nil, validationError("id", "ID cannot be empty")
+	}
+
+	quote, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote by ID from repository: %w", err)
+	}
+	if version != 0 && quote.Version != version {
+		return nil, ErrVersionConflict
+	}
+
+	if patch.Text != nil {
+		quote.Text = *patch.Text
+	}
+	if patch.Author != nil {
+		quote.Author = *patch.Author
+	}
+	if patch.Tags != nil {
+		quote.Tags = *patch.Tags
 	}
-	if err := s.repo.Delete(id); err != nil {
+	if err := validateQuote(quote.Text, quote.Author); err != nil {
+		return nil, err
+	}
+	if quote.Tags, err = validateTags(quote.Tags); err != nil {
+		return nil, err
+	}
+	if patch.Text != nil || patch.Author != nil {
+		if err := s.findNearDuplicate(ctx, quote.Text, quote.Author, id); err != nil {
+			return nil, err
+		}
+	}
+	quote.UpdatedAt = time.Now().UTC()
+
+	// quote.Version still holds the version that was read, so a concurrent
+	// write between GetByID and Update is reported as a conflict.
+	if err := s.repo.Update(ctx, quote); err != nil {
+		return nil, fmt.Errorf("failed to update quote in repository: %w", err)
+	}
+
+	return quote, nil
+}
+
+func (s *QuoteServiceImpl) DeleteQuote(ctx context.Context, id string, version int64) error {
+	if id == "" {
+		return validationError("id", "ID cannot be empty")
+	}
+	if err := s.repo.Delete(ctx, id, version); err != nil {
 		return fmt.Errorf("failed to delete quote from repository: %w", err)
 	}
 	return nil	
 }
 
-func (s *QuoteServiceImpl) GetByID(id string) (*domain.Quote, error) {
+func (s *QuoteServiceImpl) GetByID(ctx context.Context, id string) (*domain.Quote, error) {
 	if id == "" {
-		return nil, errors.New("ID cannot be empty")
+		return nil, validationError("id", "ID cannot be empty")
 	}
-	quote, err := s.repo.GetByID(id)
+	quote, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get quote by ID from repository: %w", err)
 	}
 	return quote, nil
-} 
\ No newline at end of file
+}
+
+func (s *QuoteServiceImpl) SearchQuotes(ctx context.Context, query string, limit int) ([]domain.SearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, validationError("q", "search query cannot be empty")
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	results, err := s.repo.Search(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search quotes in repository: %w", err)
+	}
+	if results == nil {
+		results = []domain.SearchResult{}
+	}
+	return results, nil
+}
+
+func (s *QuoteServiceImpl) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	tags, err := s.repo.ListTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags from repository: %w", err)
+	}
+	return tags, nil
+}
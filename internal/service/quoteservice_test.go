@@ -1,38 +1,100 @@
 package service_test
 
 import (
+	"context"
 	"errors"
+	"iter"
+	"strings"
 	"test-task-scout-go/internal/domain"
 	"test-task-scout-go/internal/service"
 	"testing"
+	"time"
 )
 
 type MockQuoteRepository struct {
 	CreateFunc      func(quote *domain.Quote) error
+	CreateBatchFunc func(quotes []*domain.Quote, atomic bool) ([]error, error)
 	GetAllFunc      func() ([]domain.Quote, error)
+	CountFunc       func() (int, error)
 	GetByIDFunc     func(id string) (*domain.Quote, error)
 	GetByAuthorFunc func(author string) ([]domain.Quote, error)
-	DeleteFunc      func(id string) error
-	GetRandomFunc   func() (*domain.Quote, error)
+	DeleteFunc      func(id string, version int64) error
+	GetRandomFunc   func(filter domain.QuoteFilter) (*domain.Quote, error)
+	SearchFunc      func(query string, limit int) ([]domain.SearchResult, error)
+	ListFunc        func(query domain.ListQuery) (*domain.QuotePage, error)
+	IterateFunc     func(filter domain.QuoteFilter) iter.Seq2[domain.Quote, error]
+	UpdateFunc      func(quote *domain.Quote) error
+	ListTagsFunc    func() ([]domain.TagCount, error)
+
+	GetDailyPinFunc    func(date string) (string, error)
+	SetDailyPinFunc    func(date, quoteID string) error
+	DeleteDailyPinFunc func(date string) error
+
+	CreateAuthorFunc func(author *domain.Author) error
+	GetAuthorFunc    func(id string) (*domain.Author, error)
+	ListAuthorsFunc  func() ([]domain.Author, error)
+	MergeAuthorsFunc func(targetID, sourceID string) (*domain.Author, error)
 }
 
-func (m *MockQuoteRepository) Create(quote *domain.Quote) error {
+func (m *MockQuoteRepository) Create(ctx context.Context, quote *domain.Quote) error {
 	return m.CreateFunc(quote)
 }
-func (m *MockQuoteRepository) GetAll() ([]domain.Quote, error) {
+func (m *MockQuoteRepository) CreateBatch(ctx context.Context, quotes []*domain.Quote, atomic bool) ([]error, error) {
+	return m.CreateBatchFunc(quotes, atomic)
+}
+func (m *MockQuoteRepository) GetAll(ctx context.Context) ([]domain.Quote, error) {
 	return m.GetAllFunc()
 }
-func (m *MockQuoteRepository) GetByID(id string) (*domain.Quote, error) {
+func (m *MockQuoteRepository) Count(ctx context.Context) (int, error) {
+	return m.CountFunc()
+}
+func (m *MockQuoteRepository) GetByID(ctx context.Context, id string) (*domain.Quote, error) {
 	return m.GetByIDFunc(id)
 }
-func (m *MockQuoteRepository) GetByAuthor(author string) ([]domain.Quote, error) {
+func (m *MockQuoteRepository) GetByAuthor(ctx context.Context, author string) ([]domain.Quote, error) {
 	return m.GetByAuthorFunc(author)
 }
-func (m *MockQuoteRepository) Delete(id string) error {
-	return m.DeleteFunc(id)
+func (m *MockQuoteRepository) Update(ctx context.Context, quote *domain.Quote) error {
+	return m.UpdateFunc(quote)
+}
+func (m *MockQuoteRepository) Delete(ctx context.Context, id string, version int64) error {
+	return m.DeleteFunc(id, version)
+}
+func (m *MockQuoteRepository) GetRandom(ctx context.Context, filter domain.QuoteFilter) (*domain.Quote, error) {
+	return m.GetRandomFunc(filter)
+}
+func (m *MockQuoteRepository) Search(ctx context.Context, query string, limit int) ([]domain.SearchResult, error) {
+	return m.SearchFunc(query, limit)
+}
+func (m *MockQuoteRepository) List(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error) {
+	return m.ListFunc(query)
+}
+func (m *MockQuoteRepository) Iterate(ctx context.Context, filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+	return m.IterateFunc(filter)
+}
+func (m *MockQuoteRepository) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	return m.ListTagsFunc()
+}
+func (m *MockQuoteRepository) GetDailyPin(ctx context.Context, date string) (string, error) {
+	return m.GetDailyPinFunc(date)
 }
-func (m *MockQuoteRepository) GetRandom() (*domain.Quote, error) {
-	return m.GetRandomFunc()
+func (m *MockQuoteRepository) SetDailyPin(ctx context.Context, date, quoteID string) error {
+	return m.SetDailyPinFunc(date, quoteID)
+}
+func (m *MockQuoteRepository) DeleteDailyPin(ctx context.Context, date string) error {
+	return m.DeleteDailyPinFunc(date)
+}
+func (m *MockQuoteRepository) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	return m.CreateAuthorFunc(author)
+}
+func (m *MockQuoteRepository) GetAuthor(ctx context.Context, id string) (*domain.Author, error) {
+	return m.GetAuthorFunc(id)
+}
+func (m *MockQuoteRepository) ListAuthors(ctx context.Context) ([]domain.Author, error) {
+	return m.ListAuthorsFunc()
+}
+func (m *MockQuoteRepository) MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error) {
+	return m.MergeAuthorsFunc(targetID, sourceID)
 }
 
 func TestQuoteService_CreateQuote(t *testing.T) {
@@ -48,7 +110,7 @@ func TestQuoteService_CreateQuote(t *testing.T) {
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		quote, err := quoteService.CreateQuote("Test Text", "Test Author")
+		quote, err := quoteService.CreateQuote(t.Context(), "Test Text", "Test Author", nil, "")
 		if err != nil {
 			t.Fatalf("CreateQuote failed: %v", err)
 		}
@@ -61,129 +123,104 @@ func TestQuoteService_CreateQuote(t *testing.T) {
 		if quote.Text != "Test Text" || quote.Author != "Test Author" {
 			t.Errorf("Created quote has wrong text or author: %+v", quote)
 		}
+		if quote.CreatedAt.IsZero() || !quote.UpdatedAt.Equal(quote.CreatedAt) {
+			t.Errorf("Expected CreatedAt to be set and equal to UpdatedAt, got %v and %v", quote.CreatedAt, quote.UpdatedAt)
+		}
+		if quote.CreatedBy != service.AnonymousCreator {
+			t.Errorf("Expected CreatedBy '%s', got '%s'", service.AnonymousCreator, quote.CreatedBy)
+		}
 	})
 
-	t.Run("ValidationError", func(t *testing.T) {
+	t.Run("Tags", func(t *testing.T) {
 		mockRepo := &MockQuoteRepository{
 			CreateFunc: func(quote *domain.Quote) error {
-				if quote.Text == "" || quote.Author == "" {
-					return errors.New("validation error")
-				}
 				return nil
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		_, err := quoteService.CreateQuote("", "Test Author")
-		if err == nil {
-			t.Error("CreateQuote did not return error for empty text")
+		quote, err := quoteService.CreateQuote(t.Context(), "Test Text", "Test Author", []string{"Film", " motivation ", "film", ""}, "")
+		if err != nil {
+			t.Fatalf("CreateQuote failed: %v", err)
 		}
-		if err != nil && err.Error() != "text and author cannot be empty" {
-			t.Errorf("Expected validation error, got: %v", err)
+		if strings.Join(quote.Tags, ",") != "film,motivation" {
+			t.Errorf("Expected normalized tags [film motivation], got %v", quote.Tags)
 		}
 
-		_, err = quoteService.CreateQuote("Test Text", "")
-		if err == nil {
-			t.Error("CreateQuote did not return error for empty author")
-		}
-		if err != nil && err.Error() != "text and author cannot be empty" {
-			t.Errorf("Expected validation error, got: %v", err)
+		_, err = quoteService.CreateQuote(t.Context(), "Test Text", "Test Author", []string{"not a tag"}, "")
+		var validationErr *service.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "tags" {
+			t.Errorf("Expected a validation error for field 'tags', got: %v", err)
 		}
 	})
 
-	t.Run("RepositoryError", func(t *testing.T) {
+	t.Run("RecordsCreator", func(t *testing.T) {
+		var stored *domain.Quote
 		mockRepo := &MockQuoteRepository{
 			CreateFunc: func(quote *domain.Quote) error {
-				return errors.New("database error")
+				stored = quote
+				return nil
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		_, err := quoteService.CreateQuote("Test Text", "Test Author")
-		if err == nil {
-			t.Error("CreateQuote did not return repository error")
+		if _, err := quoteService.CreateQuote(t.Context(), "Test Text", "Test Author", nil, "alice"); err != nil {
+			t.Fatalf("CreateQuote failed: %v", err)
 		}
-		expectedErr := "failed to create quote in repository: database error"
-		if err != nil && err.Error() != expectedErr {
-			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		if stored == nil || stored.CreatedBy != "alice" {
+			t.Errorf("Expected repository to receive CreatedBy 'alice', got %+v", stored)
 		}
 	})
-}
 
-func TestQuoteService_GetAllQuotes(t *testing.T) {
-	t.Run("Success", func(t *testing.T) {
-		expectedQuotes := []domain.Quote{
-			{ID: "1", Text: "Quote 1", Author: "Author 1"},
-			{ID: "2", Text: "Quote 2", Author: "Author 2"},
-		}
+	t.Run("ValidationError", func(t *testing.T) {
 		mockRepo := &MockQuoteRepository{
-			GetAllFunc: func() ([]domain.Quote, error) {
-				return expectedQuotes, nil
-			},
-			GetByAuthorFunc: func(author string) ([]domain.Quote, error) {
-				if author == "Author 1" {
-					return []domain.Quote{{ID: "1", Text: "Quote 1", Author: "Author 1"}}, nil
+			CreateFunc: func(quote *domain.Quote) error {
+				if quote.Text == "" || quote.Author == "" {
+					return errors.New("validation error")
 				}
-				return nil, nil
+				return nil
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		quotes, err := quoteService.GetAllQuotes("")
-		if err != nil {
-			t.Fatalf("GetAllQuotes without filter failed: %v", err)
+		_, err := quoteService.CreateQuote(t.Context(), "", "Test Author", nil, "")
+		if err == nil {
+			t.Error("CreateQuote did not return error for empty text")
 		}
-		if len(quotes) != len(expectedQuotes) {
-			t.Errorf("Expected %d quotes, got %d", len(expectedQuotes), len(quotes))
+		if err != nil && err.Error() != "text and author cannot be empty" {
+			t.Errorf("Expected validation error, got: %v", err)
 		}
 
-		quotesByAuthor, err := quoteService.GetAllQuotes("Author 1")
-		if err != nil {
-			t.Fatalf("GetAllQuotes with filter failed: %v", err)
-		}
-		if len(quotesByAuthor) != 1 {
-			t.Errorf("Expected 1 quote for author 'Author 1', got %d", len(quotesByAuthor))
-		}
-		if len(quotesByAuthor) > 0 && (quotesByAuthor[0].Text != "Quote 1" || quotesByAuthor[0].Author != "Author 1") {
-			t.Errorf("Unexpected quote returned for author filter: %+v", quotesByAuthor[0])
+		_, err = quoteService.CreateQuote(t.Context(), "Test Text", "", nil, "")
+		if err == nil {
+			t.Error("CreateQuote did not return error for empty author")
 		}
-
-		quotesByNonExistentAuthor, err := quoteService.GetAllQuotes("NonExistent Author")
-		if err != nil {
-			t.Fatalf("GetAllQuotes with non-existent author filter failed: %v", err)
+		if err != nil && err.Error() != "text and author cannot be empty" {
+			t.Errorf("Expected validation error, got: %v", err)
 		}
-		if len(quotesByNonExistentAuthor) != 0 {
-			t.Errorf("Expected 0 quotes for non-existent author, got %d", len(quotesByNonExistentAuthor))
+		if !errors.Is(err, service.ErrValidation) {
+			t.Errorf("Expected error to match ErrValidation, got: %v", err)
 		}
 	})
 
 	t.Run("RepositoryError", func(t *testing.T) {
 		mockRepo := &MockQuoteRepository{
-			GetAllFunc: func() ([]domain.Quote, error) {
-				return nil, errors.New("database error")
-			},
-			GetByAuthorFunc: func(author string) ([]domain.Quote, error) {
-				return nil, errors.New("database error")
+			CreateFunc: func(quote *domain.Quote) error {
+				return errors.New("database error")
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		_, err := quoteService.GetAllQuotes("")
+		_, err := quoteService.CreateQuote(t.Context(), "Test Text", "Test Author", nil, "")
 		if err == nil {
-			t.Error("GetAllQuotes without filter did not return repository error")
-		}
-		expectedErrGetAll := "failed to get all quotes from repository: database error"
-		if err != nil && err.Error() != expectedErrGetAll {
-			t.Errorf("Expected '%s' error, got: %v", expectedErrGetAll, err)
+			t.Error("CreateQuote did not return repository error")
 		}
-
-		_, err = quoteService.GetAllQuotes("Some Author")
-		if err == nil {
-			t.Error("GetAllQuotes with filter did not return repository error")
+		expectedErr := "failed to create quote in repository: database error"
+		if err != nil && err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
 		}
-		expectedErrGetByAuthor := "failed to get quotes by author from repository: database error"
-		if err != nil && err.Error() != expectedErrGetByAuthor {
-			t.Errorf("Expected '%s' error, got: %v", expectedErrGetByAuthor, err)
+		if errors.Is(err, service.ErrValidation) {
+			t.Errorf("Repository error should not match ErrValidation: %v", err)
 		}
 	})
 }
@@ -192,13 +229,13 @@ func TestQuoteService_GetRandomQuote(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		expectedQuote := &domain.Quote{ID: "random-1", Text: "Random Quote", Author: "Random Author"}
 		mockRepo := &MockQuoteRepository{
-			GetRandomFunc: func() (*domain.Quote, error) {
+			GetRandomFunc: func(filter domain.QuoteFilter) (*domain.Quote, error) {
 				return expectedQuote, nil
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		quote, err := quoteService.GetRandomQuote()
+		quote, err := quoteService.GetRandomQuote(t.Context(), domain.QuoteFilter{})
 		if err != nil {
 			t.Fatalf("GetRandomQuote failed: %v", err)
 		}
@@ -212,13 +249,13 @@ func TestQuoteService_GetRandomQuote(t *testing.T) {
 
 	t.Run("NotFound", func(t *testing.T) {
 		mockRepo := &MockQuoteRepository{
-			GetRandomFunc: func() (*domain.Quote, error) {
+			GetRandomFunc: func(filter domain.QuoteFilter) (*domain.Quote, error) {
 				return nil, errors.New("not found")
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		quote, err := quoteService.GetRandomQuote()
+		quote, err := quoteService.GetRandomQuote(t.Context(), domain.QuoteFilter{})
 		if err == nil {
 			t.Error("GetRandomQuote did not return error when not found")
 		}
@@ -233,13 +270,13 @@ func TestQuoteService_GetRandomQuote(t *testing.T) {
 
 	t.Run("RepositoryError", func(t *testing.T) {
 		mockRepo := &MockQuoteRepository{
-			GetRandomFunc: func() (*domain.Quote, error) {
+			GetRandomFunc: func(filter domain.QuoteFilter) (*domain.Quote, error) {
 				return nil, errors.New("database error")
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		_, err := quoteService.GetRandomQuote()
+		_, err := quoteService.GetRandomQuote(t.Context(), domain.QuoteFilter{})
 		if err == nil {
 			t.Error("GetRandomQuote did not return repository error")
 		}
@@ -248,12 +285,89 @@ func TestQuoteService_GetRandomQuote(t *testing.T) {
 			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
 		}
 	})
+
+	t.Run("TagFilter", func(t *testing.T) {
+		var got domain.QuoteFilter
+		mockRepo := &MockQuoteRepository{
+			GetRandomFunc: func(filter domain.QuoteFilter) (*domain.Quote, error) {
+				got = filter
+				return &domain.Quote{ID: "1"}, nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		if _, err := quoteService.GetRandomQuote(t.Context(), domain.QuoteFilter{Tags: []string{" Humor", "film", "humor"}}); err != nil {
+			t.Fatalf("GetRandomQuote failed: %v", err)
+		}
+		if strings.Join(got.Tags, ",") != "film,humor" || got.TagMode != domain.TagModeAny {
+			t.Errorf("Expected normalized tags with default mode, got %+v", got)
+		}
+
+		_, err := quoteService.GetRandomQuote(t.Context(), domain.QuoteFilter{Tags: []string{"film"}, TagMode: "some"})
+		var validationErr *service.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "tag_mode" {
+			t.Errorf("Expected a validation error for field 'tag_mode', got: %v", err)
+		}
+	})
+
+	t.Run("AuthorMatch", func(t *testing.T) {
+		var got domain.QuoteFilter
+		mockRepo := &MockQuoteRepository{
+			GetRandomFunc: func(filter domain.QuoteFilter) (*domain.Quote, error) {
+				got = filter
+				return &domain.Quote{ID: "1"}, nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		if _, err := quoteService.GetRandomQuote(t.Context(), domain.QuoteFilter{Author: "Steve Jobs"}); err != nil {
+			t.Fatalf("GetRandomQuote failed: %v", err)
+		}
+		if got.AuthorMatch != domain.AuthorMatchExact {
+			t.Errorf("Expected exact author match by default, got %q", got.AuthorMatch)
+		}
+
+		tests := []struct {
+			filter domain.QuoteFilter
+			field  string
+		}{
+			{domain.QuoteFilter{Author: "steve", AuthorMatch: "soundex"}, "author_match"},
+			{domain.QuoteFilter{Author: "  ", AuthorMatch: domain.AuthorMatchPrefix}, "author"},
+		}
+		for _, tt := range tests {
+			_, err := quoteService.GetRandomQuote(t.Context(), tt.filter)
+			var validationErr *service.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Expected a validation error for field %q with %+v, got: %v", tt.field, tt.filter, err)
+			}
+		}
+	})
+
+	t.Run("InvalidLength", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{})
+
+		tests := []struct {
+			filter domain.QuoteFilter
+			field  string
+		}{
+			{domain.QuoteFilter{MinLength: -1}, "min_length"},
+			{domain.QuoteFilter{MaxLength: -1}, "max_length"},
+			{domain.QuoteFilter{MinLength: 50, MaxLength: 10}, "max_length"},
+		}
+		for _, tt := range tests {
+			_, err := quoteService.GetRandomQuote(t.Context(), tt.filter)
+			var validationErr *service.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Expected a validation error for field '%s' with %+v, got: %v", tt.field, tt.filter, err)
+			}
+		}
+	})
 }
 
 func TestQuoteService_DeleteQuote(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := &MockQuoteRepository{
-			DeleteFunc: func(id string) error {
+			DeleteFunc: func(id string, version int64) error {
 				if id == "123" {
 					return nil
 				}
@@ -262,7 +376,7 @@ func TestQuoteService_DeleteQuote(t *testing.T) {
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		err := quoteService.DeleteQuote("123")
+		err := quoteService.DeleteQuote(t.Context(), "123", 0)
 		if err != nil {
 			t.Fatalf("DeleteQuote failed: %v", err)
 		}
@@ -270,13 +384,13 @@ func TestQuoteService_DeleteQuote(t *testing.T) {
 
 	t.Run("NotFound", func(t *testing.T) {
 		mockRepo := &MockQuoteRepository{
-			DeleteFunc: func(id string) error {
+			DeleteFunc: func(id string, version int64) error {
 				return errors.New("not found")
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		err := quoteService.DeleteQuote("non-existent")
+		err := quoteService.DeleteQuote(t.Context(), "non-existent", 0)
 		if err == nil {
 			t.Error("DeleteQuote did not return error when not found")
 		}
@@ -288,13 +402,13 @@ func TestQuoteService_DeleteQuote(t *testing.T) {
 
 	t.Run("RepositoryError", func(t *testing.T) {
 		mockRepo := &MockQuoteRepository{
-			DeleteFunc: func(id string) error {
+			DeleteFunc: func(id string, version int64) error {
 				return errors.New("database error")
 			},
 		}
 		quoteService := service.NewQuoteService(mockRepo)
 
-		err := quoteService.DeleteQuote("some-id")
+		err := quoteService.DeleteQuote(t.Context(), "some-id", 0)
 		if err == nil {
 			t.Error("DeleteQuote did not return repository error")
 		}
@@ -303,4 +417,537 @@ func TestQuoteService_DeleteQuote(t *testing.T) {
 			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestQuoteService_SearchQuotes(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var gotLimit int
+		mockRepo := &MockQuoteRepository{
+			SearchFunc: func(query string, limit int) ([]domain.SearchResult, error) {
+				gotLimit = limit
+				return []domain.SearchResult{
+					{Quote: domain.Quote{ID: "1", Text: "Quote 1", Author: "Author 1"}, Score: 1.5, Snippet: "<mark>Quote</mark> 1"},
+				}, nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		results, err := quoteService.SearchQuotes(t.Context(), "quote", 0)
+		if err != nil {
+			t.Fatalf("SearchQuotes failed: %v", err)
+		}
+		if len(results) != 1 || results[0].Quote.ID != "1" {
+			t.Errorf("SearchQuotes returned unexpected results: %+v", results)
+		}
+		if gotLimit != service.DefaultSearchLimit {
+			t.Errorf("Expected default limit %d, got %d", service.DefaultSearchLimit, gotLimit)
+		}
+
+		quoteService.SearchQuotes(t.Context(), "quote", 1000)
+		if gotLimit != service.MaxSearchLimit {
+			t.Errorf("Expected limit capped at %d, got %d", service.MaxSearchLimit, gotLimit)
+		}
+	})
+
+	t.Run("EmptyQuery", func(t *testing.T) {
+		mockRepo := &MockQuoteRepository{
+			SearchFunc: func(query string, limit int) ([]domain.SearchResult, error) {
+				t.Error("Search should not be called for an empty query")
+				return nil, nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		_, err := quoteService.SearchQuotes(t.Context(), "   ", 10)
+		expectedErr := "search query cannot be empty"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		mockRepo := &MockQuoteRepository{
+			SearchFunc: func(query string, limit int) ([]domain.SearchResult, error) {
+				return nil, errors.New("database error")
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		_, err := quoteService.SearchQuotes(t.Context(), "quote", 10)
+		expectedErr := "failed to search quotes in repository: database error"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+	})
+}
+
+func TestQuoteService_ListQuotes(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		var got domain.ListQuery
+		mockRepo := &MockQuoteRepository{
+			ListFunc: func(query domain.ListQuery) (*domain.QuotePage, error) {
+				got = query
+				return &domain.QuotePage{Quotes: []domain.Quote{{ID: "1"}}, NextCursor: "next"}, nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		page, err := quoteService.ListQuotes(t.Context(), domain.ListQuery{})
+		if err != nil {
+			t.Fatalf("ListQuotes failed: %v", err)
+		}
+		if len(page.Quotes) != 1 || page.NextCursor != "next" {
+			t.Errorf("ListQuotes returned unexpected page: %+v", page)
+		}
+		if got.SortBy != domain.SortByID || got.Limit != service.DefaultPageLimit {
+			t.Errorf("Expected default sort and limit, got %+v", got)
+		}
+
+		quoteService.ListQuotes(t.Context(), domain.ListQuery{SortBy: domain.SortByAuthor, Limit: 5000})
+		if got.SortBy != domain.SortByAuthor || got.Limit != service.MaxPageLimit {
+			t.Errorf("Expected author sort with capped limit, got %+v", got)
+		}
+	})
+
+	t.Run("InvalidSort", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{})
+
+		_, err := quoteService.ListQuotes(t.Context(), domain.ListQuery{SortBy: "text"})
+		expectedErr := "invalid sort field: text"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+		var validationErr *service.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "sort" {
+			t.Errorf("Expected a validation error for field 'sort', got: %v", err)
+		}
+	})
+
+	t.Run("InvalidCreatedRange", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{})
+
+		now := time.Now()
+		_, err := quoteService.ListQuotes(t.Context(), domain.ListQuery{QuoteFilter: domain.QuoteFilter{CreatedAfter: now, CreatedBefore: now.Add(-time.Hour)}})
+		var validationErr *service.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "created_before" {
+			t.Errorf("Expected a validation error for field 'created_before', got: %v", err)
+		}
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		mockRepo := &MockQuoteRepository{
+			ListFunc: func(query domain.ListQuery) (*domain.QuotePage, error) {
+				return nil, errors.New("database error")
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		_, err := quoteService.ListQuotes(t.Context(), domain.ListQuery{})
+		expectedErr := "failed to list quotes from repository: database error"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+	})
+}
+
+func TestQuoteService_UpdateQuote(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var stored *domain.Quote
+		mockRepo := &MockQuoteRepository{
+			UpdateFunc: func(quote *domain.Quote) error {
+				stored = quote
+				return nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		quote, err := quoteService.UpdateQuote(t.Context(), "123", "New Text", "New Author", nil, 0)
+		if err != nil {
+			t.Fatalf("UpdateQuote failed: %v", err)
+		}
+		if quote.ID != "123" || quote.Text != "New Text" || quote.Author != "New Author" {
+			t.Errorf("UpdateQuote returned unexpected quote: %+v", quote)
+		}
+		if stored == nil || stored.ID != "123" {
+			t.Errorf("UpdateQuote did not pass the quote to the repository: %+v", stored)
+		}
+		if stored != nil && stored.UpdatedAt.IsZero() {
+			t.Error("UpdateQuote did not set UpdatedAt")
+		}
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{})
+
+		_, err := quoteService.UpdateQuote(t.Context(), "123", "", "Author", nil, 0)
+		expectedErr := "text and author cannot be empty"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockRepo := &MockQuoteRepository{
+			UpdateFunc: func(quote *domain.Quote) error {
+				return errors.New("quote not found")
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		_, err := quoteService.UpdateQuote(t.Context(), "123", "Text", "Author", nil, 0)
+		expectedErr := "failed to update quote in repository: quote not found"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+	})
+}
+
+func TestQuoteService_PatchQuote(t *testing.T) {
+	newRepo := func(stored *domain.Quote) *MockQuoteRepository {
+		return &MockQuoteRepository{
+			GetByIDFunc: func(id string) (*domain.Quote, error) {
+				if id != "123" {
+					return nil, errors.New("quote not found")
+				}
+				quote := *stored
+				return &quote, nil
+			},
+			UpdateFunc: func(quote *domain.Quote) error {
+				*stored = *quote
+				return nil
+			},
+		}
+	}
+	ptr := func(s string) *string { return &s }
+
+	t.Run("Success", func(t *testing.T) {
+		stored := &domain.Quote{ID: "123", Text: "Old Text", Author: "Author"}
+		quoteService := service.NewQuoteService(newRepo(stored))
+
+		quote, err := quoteService.PatchQuote(t.Context(), "123", domain.QuotePatch{Text: ptr("Fixed Text")}, 0)
+		if err != nil {
+			t.Fatalf("PatchQuote failed: %v", err)
+		}
+		if quote.Text != "Fixed Text" || quote.Author != "Author" {
+			t.Errorf("PatchQuote returned unexpected quote: %+v", quote)
+		}
+		if stored.Text != "Fixed Text" || stored.Author != "Author" {
+			t.Errorf("PatchQuote did not store the merged quote: %+v", stored)
+		}
+	})
+
+	t.Run("RemovingRequiredField", func(t *testing.T) {
+		stored := &domain.Quote{ID: "123", Text: "Old Text", Author: "Author"}
+		quoteService := service.NewQuoteService(newRepo(stored))
+
+		_, err := quoteService.PatchQuote(t.Context(), "123", domain.QuotePatch{Author: ptr("")}, 0)
+		expectedErr := "text and author cannot be empty"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+		if stored.Author != "Author" {
+			t.Errorf("Invalid patch was stored: %+v", stored)
+		}
+	})
+
+	t.Run("VersionMismatch", func(t *testing.T) {
+		stored := &domain.Quote{ID: "123", Text: "Old Text", Author: "Author", Version: 3}
+		quoteService := service.NewQuoteService(newRepo(stored))
+
+		_, err := quoteService.PatchQuote(t.Context(), "123", domain.QuotePatch{Text: ptr("Fixed Text")}, 2)
+		expectedErr := "quote version conflict"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+		if stored.Text != "Old Text" {
+			t.Errorf("Patch against a stale version was stored: %+v", stored)
+		}
+	})
+
+	t.Run("UpdatesReadVersion", func(t *testing.T) {
+		var updatedWith int64
+		mockRepo := &MockQuoteRepository{
+			GetByIDFunc: func(id string) (*domain.Quote, error) {
+				return &domain.Quote{ID: id, Text: "Text", Author: "Author", Version: 5}, nil
+			},
+			UpdateFunc: func(quote *domain.Quote) error {
+				updatedWith = quote.Version
+				return nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		if _, err := quoteService.PatchQuote(t.Context(), "123", domain.QuotePatch{Text: ptr("New")}, 0); err != nil {
+			t.Fatalf("PatchQuote failed: %v", err)
+		}
+		if updatedWith != 5 {
+			t.Errorf("Expected update conditional on read version 5, got %d", updatedWith)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		quoteService := service.NewQuoteService(newRepo(&domain.Quote{}))
+
+		_, err := quoteService.PatchQuote(t.Context(), "missing", domain.QuotePatch{Text: ptr("Text")}, 0)
+		expectedErr := "failed to get quote by ID from repository: quote not found"
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+		}
+	})
+}
+
+func TestQuoteService_ListTags(t *testing.T) {
+	mockRepo := &MockQuoteRepository{
+		ListTagsFunc: func() ([]domain.TagCount, error) {
+			return nil, errors.New("database error")
+		},
+	}
+	quoteService := service.NewQuoteService(mockRepo)
+
+	_, err := quoteService.ListTags(t.Context())
+	expectedErr := "failed to list tags from repository: database error"
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected '%s' error, got: %v", expectedErr, err)
+	}
+}
+
+func TestQuoteService_ExportQuotes(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := &MockQuoteRepository{
+			IterateFunc: func(filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+				if filter.TagMode != domain.TagModeAny {
+					t.Errorf("Expected default tag mode, got %q", filter.TagMode)
+				}
+				return func(yield func(domain.Quote, error) bool) {
+					if !yield(domain.Quote{ID: "1"}, nil) {
+						return
+					}
+					yield(domain.Quote{}, errors.New("db error"))
+				}
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		quotes, err := quoteService.ExportQuotes(t.Context(), domain.QuoteFilter{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var ids []string
+		var iterErr error
+		for quote, err := range quotes {
+			if err != nil {
+				iterErr = err
+				break
+			}
+			ids = append(ids, quote.ID)
+		}
+		if len(ids) != 1 || ids[0] != "1" {
+			t.Errorf("Expected quote 1 before the error, got %v", ids)
+		}
+		if iterErr == nil || !strings.Contains(iterErr.Error(), "failed to export quotes from repository") {
+			t.Errorf("Expected wrapped repository error, got %v", iterErr)
+		}
+	})
+
+	t.Run("InvalidFilter", func(t *testing.T) {
+		quoteService := service.NewQuoteService(&MockQuoteRepository{})
+
+		_, err := quoteService.ExportQuotes(t.Context(), domain.QuoteFilter{MinLength: -1})
+		if !errors.Is(err, service.ErrValidation) {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+}
+
+func TestQuoteService_CreateQuote_NearDuplicate(t *testing.T) {
+	stored := []domain.Quote{
+		{ID: "1", Text: "Stay hungry, stay foolish.", Author: "Steve Jobs"},
+		{ID: "2", Text: "Stay hungry and stay foolish", Author: "Someone Else"},
+	}
+	newRepo := func(created *bool) *MockQuoteRepository {
+		return &MockQuoteRepository{
+			IterateFunc: func(filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+				return func(yield func(domain.Quote, error) bool) {
+					for _, quote := range stored {
+						if !yield(quote, nil) {
+							return
+						}
+					}
+				}
+			},
+			CreateFunc: func(quote *domain.Quote) error {
+				*created = true
+				return nil
+			},
+		}
+	}
+
+	t.Run("Rejected", func(t *testing.T) {
+		var created bool
+		quoteService := service.NewQuoteService(newRepo(&created), service.WithNearDuplicateThreshold(0.8))
+
+		_, err := quoteService.CreateQuote(t.Context(), "Stay hungry and stay foolish", "STEVE JOBS", nil, "")
+		var duplicate *service.DuplicateError
+		if !errors.As(err, &duplicate) || !errors.Is(err, service.ErrDuplicate) {
+			t.Fatalf("Expected a duplicate error, got %v", err)
+		}
+		if duplicate.ExistingID != "1" || duplicate.Similarity < 0.8 || duplicate.Similarity >= 1 {
+			t.Errorf("Unexpected duplicate: %+v", duplicate)
+		}
+		if created {
+			t.Error("Expected the near duplicate not to be stored")
+		}
+	})
+
+	t.Run("BelowThreshold", func(t *testing.T) {
+		var created bool
+		quoteService := service.NewQuoteService(newRepo(&created), service.WithNearDuplicateThreshold(0.8))
+
+		if _, err := quoteService.CreateQuote(t.Context(), "Stay curious", "Steve Jobs", nil, ""); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !created {
+			t.Error("Expected the quote to be stored")
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		created := false
+		mockRepo := &MockQuoteRepository{
+			CreateFunc: func(quote *domain.Quote) error {
+				created = true
+				return nil
+			},
+		}
+		quoteService := service.NewQuoteService(mockRepo)
+
+		if _, err := quoteService.CreateQuote(t.Context(), "Stay hungry and stay foolish", "Steve Jobs", nil, ""); err != nil || !created {
+			t.Errorf("Expected the quote to be stored without a near-duplicate scan, got %v", err)
+		}
+	})
+}
+
+func TestQuoteService_EditQuote_NearDuplicate(t *testing.T) {
+	stored := []domain.Quote{
+		{ID: "1", Text: "Stay hungry, stay foolish.", Author: "Steve Jobs", Version: 1},
+		{ID: "2", Text: "Design is how it works", Author: "Steve Jobs", Version: 1},
+	}
+	newRepo := func(updated *bool) *MockQuoteRepository {
+		return &MockQuoteRepository{
+			IterateFunc: iterateQuotes(stored...),
+			GetByIDFunc: func(id string) (*domain.Quote, error) {
+				for _, quote := range stored {
+					if quote.ID == id {
+						return &quote, nil
+					}
+				}
+				return nil, service.ErrNotFound
+			},
+			UpdateFunc: func(quote *domain.Quote) error {
+				*updated = true
+				return nil
+			},
+		}
+	}
+	near := "Stay hungry and stay foolish"
+
+	edits := map[string]func(s service.QuoteService, id string) error{
+		"Update": func(s service.QuoteService, id string) error {
+			_, err := s.UpdateQuote(t.Context(), id, near, "Steve Jobs", nil, 0)
+			return err
+		},
+		"Patch": func(s service.QuoteService, id string) error {
+			_, err := s.PatchQuote(t.Context(), id, domain.QuotePatch{Text: &near}, 0)
+			return err
+		},
+	}
+	for name, edit := range edits {
+		t.Run(name+"Rejected", func(t *testing.T) {
+			var updated bool
+			quoteService := service.NewQuoteService(newRepo(&updated), service.WithNearDuplicateThreshold(0.8))
+
+			err := edit(quoteService, "2")
+			var duplicate *service.DuplicateError
+			if !errors.As(err, &duplicate) || duplicate.ExistingID != "1" {
+				t.Fatalf("Expected a duplicate of quote 1, got %v", err)
+			}
+			if updated {
+				t.Error("Expected the near duplicate not to be stored")
+			}
+		})
+
+		t.Run(name+"OwnQuote", func(t *testing.T) {
+			var updated bool
+			quoteService := service.NewQuoteService(newRepo(&updated), service.WithNearDuplicateThreshold(0.8))
+
+			if err := edit(quoteService, "1"); err != nil || !updated {
+				t.Errorf("Expected a quote not to duplicate itself, got %v", err)
+			}
+		})
+	}
+}
+
+// iterateQuotes serves the quotes as the contents of the repository.
+func iterateQuotes(quotes ...domain.Quote) func(domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+	return func(filter domain.QuoteFilter) iter.Seq2[domain.Quote, error] {
+		return func(yield func(domain.Quote, error) bool) {
+			for _, quote := range quotes {
+				if !yield(quote, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+type sequenceIDs struct {
+	ids []string
+}
+
+func (s *sequenceIDs) NewID() string {
+	id := s.ids[0]
+	s.ids = s.ids[1:]
+	return id
+}
+
+func TestQuoteService_CreateQuote_IDGenerator(t *testing.T) {
+	t.Run("RetriesTakenID", func(t *testing.T) {
+		var attempted []string
+		mockRepo := &MockQuoteRepository{
+			CreateFunc: func(quote *domain.Quote) error {
+				attempted = append(attempted, quote.ID)
+				if quote.ID == "taken" {
+					return service.ErrAlreadyExists
+				}
+				return nil
+			},
+		}
+		ids := &sequenceIDs{ids: []string{"taken", "free"}}
+		quoteService := service.NewQuoteService(mockRepo, service.WithIDGenerator(ids))
+
+		quote, err := quoteService.CreateQuote(t.Context(), "Test Text", "Test Author", nil, "")
+		if err != nil {
+			t.Fatalf("CreateQuote failed: %v", err)
+		}
+		if quote.ID != "free" || strings.Join(attempted, ",") != "taken,free" {
+			t.Errorf("Expected a retry with a fresh ID, got %s after attempts %v", quote.ID, attempted)
+		}
+	})
+
+	t.Run("GivesUp", func(t *testing.T) {
+		attempts := 0
+		mockRepo := &MockQuoteRepository{
+			CreateFunc: func(quote *domain.Quote) error {
+				attempts++
+				return service.ErrAlreadyExists
+			},
+		}
+		ids := &sequenceIDs{ids: []string{"a", "b", "c", "d"}}
+		quoteService := service.NewQuoteService(mockRepo, service.WithIDGenerator(ids))
+
+		_, err := quoteService.CreateQuote(t.Context(), "Test Text", "Test Author", nil, "")
+		if !errors.Is(err, service.ErrAlreadyExists) || attempts != 3 {
+			t.Errorf("Expected ErrAlreadyExists after 3 attempts, got %v after %d", err, attempts)
+		}
+	})
+}
@@ -1,11 +1,35 @@
 package service
 
-import "test-task-scout-go/internal/domain"
+import (
+	"context"
+	"iter"
+
+	"test-task-scout-go/internal/domain"
+)
 
 type QuoteService interface {
-	CreateQuote(text, author string) (*domain.Quote, error)
-	GetAllQuotes(authorFilter string) ([]domain.Quote, error)
-	GetRandomQuote() (*domain.Quote, error)
-	DeleteQuote(id string) error
-	GetByID(id string) (*domain.Quote, error)
-} 
\ No newline at end of file
+	AuthorService
+
+	CreateQuote(ctx context.Context, text, author string, tags []string, createdBy string) (*domain.Quote, error)
+	ImportQuotes(ctx context.Context, rows []domain.QuoteInput, opts domain.ImportOptions) (*domain.ImportReport, error)
+	ListQuotes(ctx context.Context, query domain.ListQuery) (*domain.QuotePage, error)
+	ExportQuotes(ctx context.Context, filter domain.QuoteFilter) (iter.Seq2[domain.Quote, error], error)
+	GetRandomQuote(ctx context.Context, filter domain.QuoteFilter) (*domain.Quote, error)
+	UpdateQuote(ctx context.Context, id, text, author string, tags []string, version int64) (*domain.Quote, error)
+	PatchQuote(ctx context.Context, id string, patch domain.QuotePatch, version int64) (*domain.Quote, error)
+	DeleteQuote(ctx context.Context, id string, version int64) error
+	GetByID(ctx context.Context, id string) (*domain.Quote, error)
+	SearchQuotes(ctx context.Context, query string, limit int) ([]domain.SearchResult, error)
+	ListTags(ctx context.Context) ([]domain.TagCount, error)
+	GetDailyQuote(ctx context.Context, date string) (*domain.DailyQuote, error)
+	PinDailyQuote(ctx context.Context, date, quoteID string) (*domain.DailyQuote, error)
+	UnpinDailyQuote(ctx context.Context, date string) error
+}
+
+type AuthorService interface {
+	CreateAuthor(ctx context.Context, name, bio string, aliases []string) (*domain.Author, error)
+	GetAuthor(ctx context.Context, id string) (*domain.Author, error)
+	ListAuthors(ctx context.Context) ([]domain.Author, error)
+	ListAuthorQuotes(ctx context.Context, id string, query domain.ListQuery) (*domain.QuotePage, error)
+	MergeAuthors(ctx context.Context, targetID, sourceID string) (*domain.Author, error)
+}
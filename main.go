@@ -9,44 +9,126 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// Embedded zone data keeps /quotes/daily?tz=... working on hosts without tzdata.
+	_ "time/tzdata"
 
 	"test-task-scout-go/internal/config"
+	"test-task-scout-go/internal/idgen"
+	"test-task-scout-go/internal/metrics"
 	"test-task-scout-go/internal/repository"
 	"test-task-scout-go/internal/router"
 	"test-task-scout-go/internal/service"
 )
 
 func main() {
+	// Generating a key needs no configuration, and a broken API_KEYS must
+	// not stop anyone from generating a replacement.
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		if err := runAPIKey(os.Args[2:]); err != nil {
+			log.Fatalf("Failed to generate API key: %v", err)
+		}
+		return
+	}
+
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	quoteRepo, repoCloser, err := initRepository(cfg)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrations(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	registry := metrics.NewRegistry()
+	quoteRepo, repoCloser, err := initRepository(cfg, registry)
 	if err != nil {
 		log.Fatalf("Failed to initialize repository: %v", err)
 	}
 
-	quoteService := service.NewQuoteService(quoteRepo)
+	idGenerator, err := newIDGenerator(cfg.IDStrategy)
+	if err != nil {
+		log.Fatalf("Failed to initialize ID generator: %v", err)
+	}
+
+	quoteService := service.NewQuoteService(quoteRepo,
+		service.WithIDGenerator(idGenerator),
+		service.WithNearDuplicateThreshold(cfg.NearDuplicateThreshold))
 
-	httpHandler := router.NewRouter(quoteService)
+	health := router.NewHealth(func(ctx context.Context) error {
+		return repository.CheckHealth(ctx, quoteRepo)
+	}, buildInfo())
+	authenticators, err := newAuthenticators(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize authentication: %v", err)
+	}
+	httpHandler := newHandler(router.NewRouter(quoteService), authenticators, cfg.AuthPublicRead, registry, health)
 
 	server := startServer(cfg.Port, httpHandler, cfg.RepositoryType, cfg.DatabasePath)
 
-	shutdownServer(server, repoCloser)
+	shutdownServer(server, repoCloser, health, cfg.ShutdownDrainDelay)
 
 	log.Println("Application stopped.")
 }
 
-func initRepository(cfg *config.Config) (repository.QuoteRepository, func() error, error) {
+func newIDGenerator(strategy string) (service.IDGenerator, error) {
+	switch strategy {
+	case "uuidv4":
+		return idgen.UUIDv4{}, nil
+	case "uuidv7":
+		return &idgen.UUIDv7{}, nil
+	case "ulid":
+		return &idgen.ULID{}, nil
+	case "slug":
+		return idgen.Slug{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ID strategy: %s", strategy)
+	}
+}
+
+// newHandler serves the API with request metrics, behind authentication
+// unless there are no authenticators, and the metrics themselves on /metrics
+// and the health probes, which are always open.
+func newHandler(api *router.Router, authenticators []router.Authenticator, publicRead bool, registry *metrics.Registry, health *router.Health) http.Handler {
+	var apiHandler http.Handler = api
+	if len(authenticators) > 0 {
+		apiHandler = router.WithAuth(api, publicRead, authenticators...)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry)
+	mux.HandleFunc("/healthz", health.Liveness)
+	mux.HandleFunc("/readyz", health.Readiness)
+	mux.Handle("/", router.WithMetrics(apiHandler, api, router.NewHTTPMetrics(registry)))
+	return mux
+}
+
+func initRepository(cfg *config.Config, registry *metrics.Registry) (repository.QuoteRepository, func() error, error) {
 	var quoteRepo repository.QuoteRepository
 	var repoCloser func() error
 
 	switch cfg.RepositoryType {
 	case "inmemory":
-		log.Println("Using In-Memory Repository")
-		quoteRepo = repository.NewInMemoryRepository()
-		repoCloser = func() error { return nil }
+		if cfg.WALDir == "" {
+			log.Println("Using In-Memory Repository")
+			quoteRepo = repository.NewInMemoryRepository()
+			repoCloser = func() error { return nil }
+			break
+		}
+		log.Printf("Using In-Memory Repository with write-ahead log in %s (sync: %s)", cfg.WALDir, cfg.WALSync)
+		memoryRepo, err := repository.NewDurableInMemoryRepository(repository.DurabilityOptions{
+			Dir:           cfg.WALDir,
+			Sync:          repository.SyncPolicy(cfg.WALSync),
+			SyncInterval:  cfg.WALSyncInterval,
+			SnapshotEvery: cfg.WALSnapshotEvery,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize durable in-memory repository: %w", err)
+		}
+		quoteRepo = memoryRepo
+		repoCloser = memoryRepo.Close
 	case "sqlite":
 		log.Println("Using SQLite Repository")
 		sqliteRepo, err := repository.NewSQLiteRepository(cfg.DatabasePath)
@@ -55,25 +137,66 @@ func initRepository(cfg *config.Config) (repository.QuoteRepository, func() erro
 		}
 		quoteRepo = sqliteRepo
 		repoCloser = sqliteRepo.Close
+	case "file":
+		log.Printf("Using JSON File Repository at %s", cfg.DataFilePath)
+		fileRepo, err := repository.NewFileRepository(cfg.DataFilePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize file repository: %w", err)
+		}
+		quoteRepo = fileRepo
+		repoCloser = fileRepo.Close
+	case "postgres":
+		log.Println("Using PostgreSQL Repository")
+		postgresRepo, err := repository.NewPostgresRepository(cfg.PostgresDSN, repository.PostgresPoolOptions{
+			MaxOpenConns:    cfg.PostgresMaxOpenConns,
+			MaxIdleConns:    cfg.PostgresMaxIdleConns,
+			ConnMaxLifetime: cfg.PostgresConnMaxLifetime,
+			ConnMaxIdleTime: cfg.PostgresConnMaxIdleTime,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize PostgreSQL repository: %w", err)
+		}
+		quoteRepo = postgresRepo
+		repoCloser = postgresRepo.Close
 	default:
 		return nil, nil, fmt.Errorf("unknown repository type: %s", cfg.RepositoryType)
 	}
 
+	quoteRepo = instrumentRepository(quoteRepo, registry)
+	registerQuoteCount(quoteRepo, registry)
+
+	if cfg.CacheSize > 0 {
+		log.Printf("Caching up to %d quotes and %d lists for %s", cfg.CacheSize, cfg.CacheSize, cfg.CacheTTL)
+		cachingRepo := repository.NewCachingRepository(quoteRepo, repository.CacheOptions{Size: cfg.CacheSize, TTL: cfg.CacheTTL})
+		quoteRepo = cachingRepo
+		registerCacheMetrics(cachingRepo, registry)
+		closeBackend := repoCloser
+		repoCloser = func() error {
+			quotes, lists := cachingRepo.QuoteStats(), cachingRepo.ListStats()
+			log.Printf("Cache stats: quotes %d hits / %d misses, lists %d hits / %d misses",
+				quotes.Hits, quotes.Misses, lists.Hits, lists.Misses)
+			return closeBackend()
+		}
+	}
+
 	return quoteRepo, repoCloser, nil
 }
 
 func startServer(port string, handler http.Handler, repoType, dbPath string) *http.Server {
+	const writeTimeout = 10 * time.Second
+
 	addr := fmt.Sprintf(":%s", port)
 	server := &http.Server{
 		Addr:    addr,
-		Handler: handler,
+		Handler: router.WithTimeout(handler, writeTimeout),
 		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		WriteTimeout: writeTimeout,
 		IdleTimeout:  120 * time.Second,
 	}
 
 	go func() {
-		log.Printf("Starting server on %s", addr)
+		build := buildInfo()
+		log.Printf("Starting server %s (commit %s, %s) on %s", build.Version, build.Commit, build.GoVersion, addr)
 		log.Printf("Repository type: %s", repoType)
 		if repoType == "sqlite" {
 			log.Printf("Database path: %s", dbPath)
@@ -87,11 +210,19 @@ func startServer(port string, handler http.Handler, repoType, dbPath string) *ht
 	return server
 }
 
-func shutdownServer(server *http.Server, repoCloser func() error) {
+// shutdownServer waits for a stop signal, then reports the service not ready
+// and keeps serving for drainDelay, so that the orchestrator can stop routing
+// requests to it before the listener closes.
+func shutdownServer(server *http.Server, repoCloser func() error, health *router.Health, drainDelay time.Duration) {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
 	<-stop
+	health.Drain()
+	if drainDelay > 0 {
+		log.Printf("Draining for %s...", drainDelay)
+		time.Sleep(drainDelay)
+	}
 	log.Println("Shutting down server...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
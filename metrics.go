@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"test-task-scout-go/internal/metrics"
+	"test-task-scout-go/internal/repository"
+)
+
+// instrumentRepository times every operation of repo and counts the failed
+// ones. Outcomes the API reports to the client, such as a missing quote or a
+// version conflict, are not failures.
+func instrumentRepository(repo repository.QuoteRepository, registry *metrics.Registry) repository.QuoteRepository {
+	durations := registry.NewHistogramVec("quote_repository_operation_duration_seconds",
+		"Time taken by repository operations, by operation.", metrics.DefaultBuckets, "operation")
+	failures := registry.NewCounterVec("quote_repository_operation_errors_total",
+		"Number of repository operations that failed, by operation.", "operation")
+
+	return repository.NewInstrumentedRepository(repo, func(operation string, took time.Duration, err error) {
+		durations.Observe(took.Seconds(), operation)
+		if err != nil && !expectedRepositoryError(err) {
+			failures.Inc(operation)
+		}
+	})
+}
+
+func expectedRepositoryError(err error) bool {
+	for _, expected := range []error{
+		repository.ErrNotFound,
+		repository.ErrAlreadyExists,
+		repository.ErrVersionConflict,
+		repository.ErrDuplicate,
+		repository.ErrInvalidCursor,
+		context.Canceled,
+	} {
+		if errors.Is(err, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// registerQuoteCount exposes the number of stored quotes, counted at scrape
+// time. A failed count is reported as NaN.
+func registerQuoteCount(repo repository.QuoteRepository, registry *metrics.Registry) {
+	registry.NewGaugeFunc("quotes_stored", "Number of quotes in the repository.", func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		count, err := repo.Count(ctx)
+		if err != nil {
+			log.Printf("Failed to count quotes for metrics: %v", err)
+			return math.NaN()
+		}
+		return float64(count)
+	})
+}
+
+func registerCacheMetrics(cache *repository.CachingRepository, registry *metrics.Registry) {
+	for _, c := range []struct {
+		name  string
+		stats func() repository.CacheStats
+	}{
+		{"quotes", cache.QuoteStats},
+		{"lists", cache.ListStats},
+	} {
+		name, stats := c.name, c.stats
+		registry.NewCounterFunc("quote_cache_hits_total", "Number of lookups answered by the read cache, by cache.",
+			func() float64 { return float64(stats().Hits) }, "cache", name)
+		registry.NewCounterFunc("quote_cache_misses_total", "Number of lookups the read cache passed to the repository, by cache.",
+			func() float64 { return float64(stats().Misses) }, "cache", name)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"test-task-scout-go/internal/config"
+	"test-task-scout-go/internal/repository"
+)
+
+const migrateUsage = "usage: migrate up | migrate down [steps] | migrate status"
+
+func runMigrations(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	var db *sql.DB
+	var newMigrator func(*sql.DB) (*repository.Migrator, error)
+	var target string
+	var err error
+	switch cfg.RepositoryType {
+	case "sqlite":
+		db, err = repository.OpenSQLiteDB(cfg.DatabasePath)
+		newMigrator, target = repository.NewSQLiteMigrator, cfg.DatabasePath
+	case "postgres":
+		// The DSN may contain a password, so it is not logged.
+		db, err = repository.OpenPostgresDB(cfg.PostgresDSN, repository.PostgresPoolOptions{MaxOpenConns: 1})
+		newMigrator, target = repository.NewPostgresMigrator, "the Postgres database"
+	default:
+		return fmt.Errorf("migrations are only supported for the sqlite and postgres repositories, got %s", cfg.RepositoryType)
+	}
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d migration(s) to %s", applied, target)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("steps must be a positive integer, got %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("Rolled back %d migration(s) on %s", rolledBack, target)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s  %s\n", status.Version, status.Name, state)
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
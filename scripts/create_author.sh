@@ -0,0 +1,26 @@
+#!/bin/bash
+# ./scripts/create_author.sh "Name" ["Bio"] ["Alias 1;Alias 2"]
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+NAME="$1"
+BIO="$2"
+ALIASES="$3"
+
+if [ -z "$NAME" ]; then
+  echo "Usage: $0 \"Name\" [\"Bio\"] [\"Alias 1;Alias 2\"]"
+  exit 1
+fi
+
+echo "Creating author $NAME on $BASE_URL..."
+
+curl -s -X POST \
+  $BASE_URL/authors \
+  -H "X-API-Key: $API_KEY" \
+  -H "Content-Type: application/json" \
+  -d "$(python3 -c 'import json,sys; print(json.dumps({"name": sys.argv[1], "bio": sys.argv[2], "aliases": [a for a in sys.argv[3].split(";") if a]}))' "$NAME" "$BIO" "$ALIASES")"
+echo ""
+
+echo "Done."
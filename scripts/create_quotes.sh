@@ -2,11 +2,13 @@
 
 API_PORT=${API_PORT:-8000}
 BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
 
 echo "Creating example quotes at $BASE_URL..."
 
 curl -s -X POST \
   $BASE_URL/quotes \
+  -H "X-API-Key: $API_KEY" \
   -H "Content-Type: application/json" \
   -d '{
     "text": "За свою улетность денег не беру, а за красоту тем более...",
@@ -16,6 +18,7 @@ echo ""
 
 curl -s -X POST \
   $BASE_URL/quotes \
+  -H "X-API-Key: $API_KEY" \
   -H "Content-Type: application/json" \
   -d '{
     "text": "Счастье для всех, даром, и пусть никто не уйдет обиженный!",
@@ -25,6 +28,7 @@ echo ""
 
 curl -s -X POST \
   $BASE_URL/quotes \
+  -H "X-API-Key: $API_KEY" \
   -H "Content-Type: application/json" \
   -d '{
     "text": "Вы всё твердите про белые и чёрные полосы, а я считаю, что даже все оттенки серого не смогут описать всю цветную красоту нашего мира!",
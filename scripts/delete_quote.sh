@@ -3,6 +3,7 @@
 
 API_PORT=${API_PORT:-8000}
 BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
 
 QUOTE_ID="$1"
 
@@ -14,7 +15,7 @@ fi
 
 echo "Deleting quote with ID: $QUOTE_ID on $BASE_URL..."
 
-curl -X DELETE $BASE_URL/quotes/$QUOTE_ID
+curl -H "X-API-Key: $API_KEY" -X DELETE $BASE_URL/quotes/$QUOTE_ID
 echo ""
 
 echo "Done."
\ No newline at end of file
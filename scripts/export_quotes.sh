@@ -0,0 +1,15 @@
+#!/bin/bash
+# ./scripts/export_quotes.sh [json|ndjson|csv] [output file]
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+FORMAT=${1:-json}
+OUTPUT=${2:-quotes-export.$FORMAT}
+
+echo "Exporting quotes from $BASE_URL as $FORMAT to $OUTPUT..."
+
+curl -H "X-API-Key: $API_KEY" -f -X GET "$BASE_URL/quotes/export?format=$FORMAT" -o "$OUTPUT"
+
+echo "Done."
@@ -2,10 +2,11 @@
 
 API_PORT=${API_PORT:-8000}
 BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
 
 echo "Getting all quotes from $BASE_URL..."
 
-curl $BASE_URL/quotes
+curl -H "X-API-Key: $API_KEY" $BASE_URL/quotes
 echo ""
 
 echo "Done."
\ No newline at end of file
@@ -0,0 +1,21 @@
+#!/bin/bash
+# ./scripts/get_author_quotes.sh "author-id"
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+AUTHOR_ID="$1"
+
+if [ -z "$AUTHOR_ID" ]; then
+  echo "Usage: $0 \"author-id\""
+  echo "You can get author IDs by running ./scripts/get_authors.sh"
+  exit 1
+fi
+
+echo "Getting quotes of author with ID: $AUTHOR_ID from $BASE_URL..."
+
+curl -H "X-API-Key: $API_KEY" "$BASE_URL/authors/$AUTHOR_ID/quotes"
+echo ""
+
+echo "Done."
@@ -0,0 +1,13 @@
+#!/bin/bash
+# ./scripts/get_authors.sh
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+echo "Getting authors with quote counts from $BASE_URL..."
+
+curl -H "X-API-Key: $API_KEY" -X GET "$BASE_URL/authors"
+echo ""
+
+echo "Done."
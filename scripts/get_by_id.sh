@@ -4,6 +4,7 @@
 
 API_PORT=${API_PORT:-8000}
 BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
 
 QUOTE_ID="$1"
 
@@ -15,7 +16,7 @@ fi
 
 echo "Getting quote with ID: $QUOTE_ID from $BASE_URL..."
 
-curl $BASE_URL/quotes/$QUOTE_ID
+curl -H "X-API-Key: $API_KEY" $BASE_URL/quotes/$QUOTE_ID
 echo ""
 
 echo "Done." 
\ No newline at end of file
@@ -0,0 +1,19 @@
+#!/bin/bash
+# ./scripts/get_daily_quote.sh [YYYY-MM-DD] [time zone, e.g. Europe/Moscow]
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+DATE="$1"
+TZ_NAME="$2"
+
+echo "Getting the quote of the day from $BASE_URL..."
+
+curl -G "$BASE_URL/quotes/daily" \
+  ${DATE:+--data-urlencode "date=$DATE"} \
+  -H "X-API-Key: $API_KEY" \
+  ${TZ_NAME:+--data-urlencode "tz=$TZ_NAME"}
+echo ""
+
+echo "Done."
@@ -1,19 +1,21 @@
 #!/bin/bash
-# ./scripts/get_quotes_by_author.sh "Author Name"
+# ./scripts/get_quotes_by_author.sh "Author Name" [exact|case_insensitive|prefix|fuzzy]
 
 API_PORT=${API_PORT:-8000}
 BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
 
 AUTHOR="$1"
+MATCH="${2:-exact}"
 
 if [ -z "$AUTHOR" ]; then
-  echo "Usage: $0 \"Author Name\""
+  echo "Usage: $0 \"Author Name\" [exact|case_insensitive|prefix|fuzzy]"
   exit 1
 fi
 
-echo "Getting quotes by author: \"$AUTHOR\" from $BASE_URL..."
+echo "Getting quotes by author: \"$AUTHOR\" ($MATCH match) from $BASE_URL..."
 
-curl "$BASE_URL/quotes?author=$(echo "$AUTHOR" | sed 's/ /%20/g')"
+curl -H "X-API-Key: $API_KEY" "$BASE_URL/quotes?author=$(echo "$AUTHOR" | sed 's/ /%20/g')&author_match=$MATCH"
 echo ""
 
 echo "Done."
\ No newline at end of file
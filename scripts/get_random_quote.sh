@@ -1,11 +1,19 @@
 #!/bin/bash
+# ./scripts/get_random_quote.sh ["author=...&tag=...&max_length=..."]
 
 API_PORT=${API_PORT:-8000}
 BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+FILTER="$1"
 
 echo "Getting a random quote from $BASE_URL..."
 
-curl $BASE_URL/quotes/random
+if [ -n "$FILTER" ]; then
+  curl -H "X-API-Key: $API_KEY" "$BASE_URL/quotes/random?$FILTER"
+else
+  curl -H "X-API-Key: $API_KEY" $BASE_URL/quotes/random
+fi
 echo ""
 
-echo "Done."
\ No newline at end of file
+echo "Done."
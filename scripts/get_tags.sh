@@ -0,0 +1,13 @@
+#!/bin/bash
+# ./scripts/get_tags.sh
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+echo "Getting tags with quote counts from $BASE_URL..."
+
+curl -H "X-API-Key: $API_KEY" -X GET "$BASE_URL/tags"
+echo ""
+
+echo "Done."
@@ -0,0 +1,24 @@
+#!/bin/bash
+# ./scripts/import_quotes.sh <file.json|file.ndjson|file.csv> [atomic|best_effort] [dry_run]
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+FILE=$1
+MODE=${2:-atomic}
+DRY_RUN=${3:-false}
+
+if [ -z "$FILE" ]; then
+  echo "Usage: $0 <file.json|file.ndjson|file.csv> [atomic|best_effort] [dry_run]"
+  exit 1
+fi
+
+echo "Importing quotes from $FILE to $BASE_URL (mode=$MODE, dry_run=$DRY_RUN)..."
+
+curl -X POST "$BASE_URL/quotes/import?mode=$MODE&dry_run=$DRY_RUN" \
+  -F "file=@$FILE"
+  -H "X-API-Key: $API_KEY" \
+echo ""
+
+echo "Done."
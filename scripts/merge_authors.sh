@@ -0,0 +1,26 @@
+#!/bin/bash
+# ./scripts/merge_authors.sh "target-author-id" "merged-author-id"
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+TARGET_ID="$1"
+SOURCE_ID="$2"
+
+if [ -z "$TARGET_ID" ] || [ -z "$SOURCE_ID" ]; then
+  echo "Usage: $0 \"target-author-id\" \"merged-author-id\""
+  echo "You can get author IDs by running ./scripts/get_authors.sh"
+  exit 1
+fi
+
+echo "Merging author $SOURCE_ID into $TARGET_ID on $BASE_URL..."
+
+curl -s -X POST \
+  $BASE_URL/authors/$TARGET_ID/merge \
+  -H "X-API-Key: $API_KEY" \
+  -H "Content-Type: application/json" \
+  -d "{\"author_id\": \"$SOURCE_ID\"}"
+echo ""
+
+echo "Done."
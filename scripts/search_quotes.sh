@@ -0,0 +1,20 @@
+#!/bin/bash
+# ./scripts/search_quotes.sh "search terms"
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+QUERY="$1"
+
+if [ -z "$QUERY" ]; then
+  echo "Usage: $0 \"search terms\""
+  exit 1
+fi
+
+echo "Searching quotes for: \"$QUERY\" on $BASE_URL..."
+
+curl -H "X-API-Key: $API_KEY" -G "$BASE_URL/quotes/search" --data-urlencode "q=$QUERY"
+echo ""
+
+echo "Done."
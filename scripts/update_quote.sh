@@ -0,0 +1,27 @@
+#!/bin/bash
+# ./scripts/update_quote.sh "quote-id" "New text" "New author"
+
+API_PORT=${API_PORT:-8000}
+BASE_URL="http://localhost:$API_PORT"
+API_KEY=${API_KEY:-}
+
+QUOTE_ID="$1"
+TEXT="$2"
+AUTHOR="$3"
+
+if [ -z "$QUOTE_ID" ] || [ -z "$TEXT" ] || [ -z "$AUTHOR" ]; then
+  echo "Usage: $0 \"quote-id\" \"New text\" \"New author\""
+  echo "You can get quote IDs by running ./scripts/get_all_quotes.sh"
+  exit 1
+fi
+
+echo "Updating quote with ID: $QUOTE_ID on $BASE_URL..."
+
+curl -s -X PUT \
+  $BASE_URL/quotes/$QUOTE_ID \
+  -H "X-API-Key: $API_KEY" \
+  -H "Content-Type: application/json" \
+  -d "$(printf '{"text": %s, "author": %s}' "$(printf '%s' "$TEXT" | python3 -c 'import json,sys; print(json.dumps(sys.stdin.read()))')" "$(printf '%s' "$AUTHOR" | python3 -c 'import json,sys; print(json.dumps(sys.stdin.read()))')")"
+echo ""
+
+echo "Done."
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"test-task-scout-go/internal/router"
+)
+
+// version is set at build time with -ldflags "-X main.version=...", which the
+// Makefile does from git describe.
+var version = "dev"
+
+// buildInfo describes this build, taking the commit from the VCS details the
+// go command stamps into binaries built inside a repository.
+func buildInfo() router.BuildInfo {
+	info := router.BuildInfo{Version: version, GoVersion: runtime.Version()}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if version == "dev" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			info.CommitTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}